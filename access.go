@@ -0,0 +1,180 @@
+package tss
+
+// MaxAccessDepth is the deepest nesting of groups in an AccessStructure
+const MaxAccessDepth = 16
+
+var (
+	ErrInvalidAccessStructure = validationError("invalid access structure")
+)
+
+// AccessStructure is a recovery policy: Threshold of its Members are
+// required, a member being a party or a nested group with its own policy.
+// "2 of the board AND 1 of IT" is
+//
+//	NewAccessStructure(2,
+//		Group(2, Party("alice"), Party("bob"), Party("carol")),
+//		Group(1, Party("it1"), Party("it2")))
+//
+// A threshold of 1 gives every member the whole value of the group.
+type AccessStructure struct {
+	Threshold int
+	Members   []Member
+}
+
+// Member is a member of an AccessStructure, either a Party or a Group
+type Member struct {
+	Party string
+	Group *AccessStructure
+}
+
+// NewAccessStructure returns the policy requiring threshold of members
+func NewAccessStructure(threshold int, members ...Member) *AccessStructure {
+	return &AccessStructure{Threshold: threshold, Members: members}
+}
+
+// Party is a member held by the named party
+func Party(name string) Member {
+	return Member{Party: name}
+}
+
+// Group is a member shared among a nested group, threshold of members being
+// required
+func Group(threshold int, members ...Member) Member {
+	return Member{Group: NewAccessStructure(threshold, members...)}
+}
+
+// PathShare is a share held for a single leaf of an AccessStructure, Path
+// being the positions of the members from the root down to the leaf
+type PathShare struct {
+	Path  []byte
+	Share Share
+}
+
+// PartyBundle gathers the shares of a party, one per leaf naming it
+type PartyBundle struct {
+	Party  string
+	Shares []PathShare
+}
+
+// Split splits secret according to the policy and returns the bundle of every
+// party, in the order the parties first appear in the policy
+func (a *AccessStructure) Split(secret []byte, opts ...Option) ([]PartyBundle, error) {
+	if err := a.validate(0); err != nil {
+		return nil, err
+	}
+	if len(secret) == 0 {
+		return nil, ErrSecretRequired
+	}
+	var bundles []PartyBundle
+	positions := make(map[string]int)
+	emit := func(party string, path []byte, share Share) {
+		i, ok := positions[party]
+		if !ok {
+			i = len(bundles)
+			positions[party] = i
+			bundles = append(bundles, PartyBundle{Party: party})
+		}
+		bundles[i].Shares = append(bundles[i].Shares, PathShare{Path: append([]byte{}, path...), Share: share})
+	}
+	if err := a.split(secret, nil, emit, opts); err != nil {
+		return nil, err
+	}
+	return bundles, nil
+}
+
+func (a *AccessStructure) split(value []byte, path []byte, emit func(string, []byte, Share), opts []Option) error {
+	values := make([][]byte, len(a.Members))
+	if a.Threshold == 1 {
+		for i := range values {
+			values[i] = append([]byte{}, value...)
+		}
+	} else {
+		shares, err := CreateShares(value, len(a.Members), a.Threshold, opts...)
+		if err != nil {
+			return err
+		}
+		for i, s := range shares {
+			values[i] = s
+		}
+	}
+	for i, m := range a.Members {
+		p := append(path[:len(path):len(path)], byte(i))
+		if m.Group == nil {
+			emit(m.Party, p, values[i])
+			continue
+		}
+		err := m.Group.split(values[i], p, emit, opts)
+		erase(values[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Recover recovers the secret from the bundles of parties satisfying the
+// policy. Shares of a leaf naming another party are ignored.
+func (a *AccessStructure) Recover(bundles []PartyBundle, opts ...Option) ([]byte, error) {
+	if err := a.validate(0); err != nil {
+		return nil, err
+	}
+	held := make(map[string]Share)
+	for _, b := range bundles {
+		for _, ps := range b.Shares {
+			held[b.Party+"\x00"+string(ps.Path)] = ps.Share
+		}
+	}
+	return a.recover(held, nil, opts)
+}
+
+func (a *AccessStructure) recover(held map[string]Share, path []byte, opts []Option) ([]byte, error) {
+	var values ShareSet
+	defer func() {
+		for _, v := range values {
+			erase(v)
+		}
+	}()
+	for i, m := range a.Members {
+		if len(values) == a.Threshold {
+			break
+		}
+		p := append(path[:len(path):len(path)], byte(i))
+		if m.Group == nil {
+			if s, ok := held[m.Party+"\x00"+string(p)]; ok {
+				values = append(values, append(Share{}, s...))
+			}
+			continue
+		}
+		if v, err := m.Group.recover(held, p, opts); err == nil {
+			values = append(values, v)
+		}
+	}
+	if len(values) < a.Threshold {
+		return nil, ErrTooFewShares
+	}
+	if a.Threshold == 1 {
+		return append([]byte{}, values[0]...), nil
+	}
+	return RecoverSecret(values, opts...)
+}
+
+// validate checks the thresholds and members of the policy down to depth
+func (a *AccessStructure) validate(depth int) error {
+	if a == nil || depth >= MaxAccessDepth || len(a.Members) == 0 || len(a.Members) > MaxShares {
+		return ErrInvalidAccessStructure
+	}
+	if a.Threshold < 1 || a.Threshold > len(a.Members) {
+		return ErrInvalidAccessStructure
+	}
+	for _, m := range a.Members {
+		if (m.Party == "") == (m.Group == nil) {
+			return ErrInvalidAccessStructure
+		}
+		if m.Group != nil {
+			if err := m.Group.validate(depth + 1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestAccessStructure(t *testing.T) {
+	secret := randomBytes(32)
+	policy := NewAccessStructure(2,
+		Group(2, Party("alice"), Party("bob"), Party("carol")),
+		Group(1, Party("it1"), Party("it2")))
+	bundles, err := policy.Split(secret)
+	if err != nil {
+		failNow(t, err)
+	}
+	byName := make(map[string]PartyBundle)
+	for _, b := range bundles {
+		byName[b.Party] = b
+	}
+	pick := func(names ...string) []PartyBundle {
+		var r []PartyBundle
+		for _, n := range names {
+			r = append(r, byName[n])
+		}
+		return r
+	}
+	for _, names := range [][]string{{"alice", "carol", "it2"}, {"bob", "carol", "it1", "it2"}} {
+		got, err := policy.Recover(pick(names...))
+		if err != nil {
+			failNow(t, err)
+		}
+		if !bytes.Equal(got, secret) {
+			failNow(t, fmt.Errorf("secret mismatch for %v", names))
+		}
+	}
+	for _, names := range [][]string{{"alice", "bob", "carol"}, {"alice", "it1", "it2"}} {
+		if _, err := policy.Recover(pick(names...)); err != ErrTooFewShares {
+			failNow(t, fmt.Errorf("%v: %v", names, expected(ErrTooFewShares, err)))
+		}
+	}
+	// shares presented under another party name do not count
+	stolen := byName["alice"]
+	stolen.Party = "bob"
+	if _, err := policy.Recover([]PartyBundle{stolen, byName["carol"], byName["it1"]}); err != ErrTooFewShares {
+		failNow(t, expected(ErrTooFewShares, err))
+	}
+}
+
+func TestAccessStructureSameParty(t *testing.T) {
+	secret := randomBytes(16)
+	// alice is on the board and in IT
+	policy := NewAccessStructure(2,
+		Group(2, Party("alice"), Party("bob")),
+		Group(1, Party("alice"), Party("it")))
+	bundles, err := policy.Split(secret)
+	if err != nil {
+		failNow(t, err)
+	}
+	if bundles[0].Party != "alice" || len(bundles[0].Shares) != 2 {
+		failNow(t, fmt.Errorf("unexpected bundles %v", bundles))
+	}
+	got, err := policy.Recover(bundles[:2])
+	if err != nil || !bytes.Equal(got, secret) {
+		failNow(t, fmt.Errorf("secret mismatch %v", err))
+	}
+}
+
+func TestAccessStructureInvalid(t *testing.T) {
+	for _, policy := range []*AccessStructure{
+		nil,
+		NewAccessStructure(3, Party("a"), Party("b")),
+		NewAccessStructure(0, Party("a")),
+		NewAccessStructure(1, Member{}),
+		NewAccessStructure(1, Group(2, Party("a"))),
+	} {
+		if _, err := policy.Split(randomBytes(16)); err != ErrInvalidAccessStructure {
+			failNow(t, expected(ErrInvalidAccessStructure, err))
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package tss
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"strings"
+
+	"github.com/antik10ud/go-tss/internal/bech32"
+)
+
+const (
+	// ageIdentityHRP and ageRecipientHRP are the Bech32 prefixes of the
+	// X25519 identities and recipients of age
+	ageIdentityHRP  = "age-secret-key-"
+	ageRecipientHRP = "age"
+	// agePluginHRP is the Bech32 prefix of the identities of
+	// AgePluginIdentities, as an age-plugin-tss plugin would name them
+	agePluginHRP = "age-plugin-tss-"
+)
+
+var (
+	ErrInvalidAgeIdentity = validationError("invalid age identity")
+)
+
+// parseAgeIdentity returns the X25519 key of an AGE-SECRET-KEY-1 identity
+func parseAgeIdentity(identity string) (*ecdh.PrivateKey, error) {
+	hrp, data, err := bech32.Decode(strings.TrimSpace(identity))
+	if err != nil || hrp != ageIdentityHRP {
+		return nil, ErrInvalidAgeIdentity
+	}
+	defer erase(data)
+	key, err := ecdh.X25519().NewPrivateKey(data)
+	if err != nil {
+		return nil, ErrInvalidAgeIdentity
+	}
+	return key, nil
+}
+
+// formatAgeIdentity writes key as an AGE-SECRET-KEY-1 identity
+func formatAgeIdentity(key []byte) (string, error) {
+	s, err := bech32.Encode(ageIdentityHRP, key)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(s), nil
+}
+
+// AgeRecipient returns the age1 recipient of an X25519 age identity, files
+// encrypted to it need the identity, or a quorum of its shares, to decrypt
+func AgeRecipient(identity string) (string, error) {
+	key, err := parseAgeIdentity(identity)
+	if err != nil {
+		return "", err
+	}
+	return bech32.Encode(ageRecipientHRP, key.PublicKey().Bytes())
+}
+
+// SplitAgeIdentity splits an X25519 age identity, AGE-SECRET-KEY-1...
+func SplitAgeIdentity(identity string, sharesCount int, threshold int, opts ...Option) (ShareSet, error) {
+	key, err := parseAgeIdentity(identity)
+	if err != nil {
+		return nil, err
+	}
+	secret := key.Bytes()
+	defer erase(secret)
+	return CreateShares(secret, sharesCount, threshold, opts...)
+}
+
+// RecoverAgeIdentity recovers the age identity split by SplitAgeIdentity
+func RecoverAgeIdentity(shares ShareSet, opts ...Option) (string, error) {
+	secret, err := RecoverSecret(shares, opts...)
+	if err != nil {
+		return "", err
+	}
+	defer erase(secret)
+	if len(secret) != 32 {
+		return "", ErrInvalidAgeIdentity
+	}
+	return formatAgeIdentity(secret)
+}
+
+// AgePluginIdentities splits an age identity into AGE-PLUGIN-TSS-1...
+// identities, one per custodian, in the style of age plugins. Each carries
+// the recipient, the threshold and a share, so RecoverAgePluginIdentities
+// can check a quorum belongs together and rebuilds the right identity.
+func AgePluginIdentities(identity string, sharesCount int, threshold int, opts ...Option) ([]string, error) {
+	key, err := parseAgeIdentity(identity)
+	if err != nil {
+		return nil, err
+	}
+	shares, err := SplitAgeIdentity(identity, sharesCount, threshold, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer eraseShares(shares)
+	prefix := append(key.PublicKey().Bytes(), byte(threshold))
+	identities := make([]string, len(shares))
+	for i, s := range shares {
+		data := append(append([]byte{}, prefix...), s...)
+		encoded, err := bech32.Encode(agePluginHRP, data)
+		erase(data)
+		if err != nil {
+			return nil, err
+		}
+		identities[i] = strings.ToUpper(encoded)
+	}
+	return identities, nil
+}
+
+// RecoverAgePluginIdentities rebuilds the age identity from a quorum of the
+// identities of AgePluginIdentities. Identities of another split give a
+// *ShareError matching ErrMixedShares, a rebuilt key not matching the
+// recipient ErrChecksum.
+func RecoverAgePluginIdentities(identities []string, opts ...Option) (string, error) {
+	if len(identities) == 0 {
+		return "", ErrTooFewShares
+	}
+	shares := make(ShareSet, len(identities))
+	defer eraseShares(shares)
+	var prefix []byte
+	for i, identity := range identities {
+		hrp, data, err := bech32.Decode(strings.TrimSpace(identity))
+		if err != nil || hrp != agePluginHRP || len(data) < 33+MinShareBytes {
+			return "", &ShareError{Position: i + 1, Reason: ErrInvalidAgeIdentity}
+		}
+		if i == 0 {
+			prefix = data[:33]
+		} else if !bytes.Equal(data[:33], prefix) {
+			return "", &ShareError{Position: i + 1, Index: data[33], Reason: ErrMixedShares}
+		}
+		shares[i] = data[33:]
+	}
+	secret, err := RecoverSecret(shares, append(opts, WithThreshold(int(prefix[32])))...)
+	if err != nil {
+		return "", err
+	}
+	defer erase(secret)
+	key, err := ecdh.X25519().NewPrivateKey(secret)
+	if err != nil || !bytes.Equal(key.PublicKey().Bytes(), prefix[:32]) {
+		return "", ErrChecksum
+	}
+	return formatAgeIdentity(secret)
+}
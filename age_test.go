@@ -0,0 +1,65 @@
+package tss
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testAgeIdentity() string {
+	key, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	identity, _ := formatAgeIdentity(key.Bytes())
+	return identity
+}
+
+func TestAgeIdentity(t *testing.T) {
+	identity := testAgeIdentity()
+	if !strings.HasPrefix(identity, "AGE-SECRET-KEY-1") {
+		failNow(t, ErrInvalidAgeIdentity)
+	}
+	recipient, err := AgeRecipient(identity)
+	if err != nil || !strings.HasPrefix(recipient, "age1") {
+		failNow(t, ErrInvalidAgeIdentity)
+	}
+	shares, err := SplitAgeIdentity(identity, 3, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	recovered, err := RecoverAgeIdentity(shares[1:])
+	if err != nil {
+		failNow(t, err)
+	}
+	if recovered != identity {
+		failNow(t, ErrInvalidAgeIdentity)
+	}
+	if _, err := SplitAgeIdentity(strings.Replace(identity, "AGE-SECRET-KEY", "AGE-PUBLIC-KEY", 1), 3, 2); err != ErrInvalidAgeIdentity {
+		failNow(t, expected(ErrInvalidAgeIdentity, err))
+	}
+}
+
+func TestAgePluginIdentities(t *testing.T) {
+	identity := testAgeIdentity()
+	identities, err := AgePluginIdentities(identity, 5, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	if !strings.HasPrefix(identities[0], "AGE-PLUGIN-TSS-1") {
+		failNow(t, ErrInvalidAgeIdentity)
+	}
+	recovered, err := RecoverAgePluginIdentities([]string{identities[4], identities[0], identities[2]})
+	if err != nil {
+		failNow(t, err)
+	}
+	if recovered != identity {
+		failNow(t, ErrInvalidAgeIdentity)
+	}
+	if _, err := RecoverAgePluginIdentities(identities[:2]); !errors.Is(err, ErrThresholdNotMet) {
+		failNow(t, expected(ErrThresholdNotMet, err))
+	}
+	other, _ := AgePluginIdentities(testAgeIdentity(), 5, 3)
+	if _, err := RecoverAgePluginIdentities([]string{identities[0], other[1], identities[2]}); !errors.Is(err, ErrMixedShares) {
+		failNow(t, expected(ErrMixedShares, err))
+	}
+}
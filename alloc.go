@@ -0,0 +1,43 @@
+package tss
+
+var (
+	ErrSecureMemoryUnsupported = limitsError("secure memory not supported on this platform")
+)
+
+// Allocator provides the buffers holding secret material: polynomial
+// coefficients, the share columns interpolated on recovery and recovered
+// secrets. By default they live on the Go heap or stack, which the runtime may
+// copy and the system may swap to disk.
+type Allocator interface {
+	// Alloc returns a zeroed buffer of n bytes
+	Alloc(n int) ([]byte, error)
+	// Free wipes and releases a buffer returned by Alloc
+	Free(b []byte)
+}
+
+// WithAllocator makes CreateShares and RecoverSecret take the buffers holding
+// secret material from a, such as a LockedAllocator. A secret returned by
+// RecoverSecret then comes from a too, release it with a.Free, or use Recover
+// whose Secret frees it on Destroy.
+func WithAllocator(a Allocator) Option {
+	return func(c *config) {
+		c.allocator = a
+	}
+}
+
+// alloc returns a buffer of n bytes from the allocator, or from the heap
+func (c *config) alloc(n int) ([]byte, error) {
+	if c.allocator == nil {
+		return make([]byte, n), nil
+	}
+	return c.allocator.Alloc(n)
+}
+
+// free wipes a buffer returned by alloc and gives it back to the allocator
+func (c *config) free(b []byte) {
+	if c.allocator == nil {
+		erase(b)
+		return
+	}
+	c.allocator.Free(b)
+}
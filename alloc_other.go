@@ -0,0 +1,23 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package tss
+
+// LockedAllocator allocates buffers in memory locked with mlock, it is not
+// supported on this platform
+type LockedAllocator struct{}
+
+// NewLockedAllocator returns a LockedAllocator, on platforms without mlock its
+// Alloc fails with ErrSecureMemoryUnsupported
+func NewLockedAllocator() *LockedAllocator {
+	return &LockedAllocator{}
+}
+
+// Alloc fails with ErrSecureMemoryUnsupported
+func (a *LockedAllocator) Alloc(n int) ([]byte, error) {
+	return nil, ErrSecureMemoryUnsupported
+}
+
+// Free wipes b
+func (a *LockedAllocator) Free(b []byte) {
+	erase(b)
+}
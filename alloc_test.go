@@ -0,0 +1,65 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// countingAllocator tracks the buffers not freed yet
+type countingAllocator struct {
+	live map[*byte]bool
+}
+
+func (a *countingAllocator) Alloc(n int) ([]byte, error) {
+	b := make([]byte, n)
+	a.live[&b[0]] = true
+	return b, nil
+}
+
+func (a *countingAllocator) Free(b []byte) {
+	erase(b)
+	delete(a.live, &b[0])
+}
+
+func TestWithAllocator(t *testing.T) {
+	a := &countingAllocator{live: make(map[*byte]bool)}
+	secret := randomBytes(32)
+	shares, err := CreateShares(secret, 5, 3, WithAllocator(a))
+	if err != nil {
+		failNow(t, err)
+	}
+	if len(a.live) != 0 {
+		failNow(t, fmt.Errorf("%d buffers left after split", len(a.live)))
+	}
+	s, err := Recover(shares[2:], WithAllocator(a))
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(s.Bytes(), secret) || len(a.live) != 1 || !a.live[&s.Bytes()[0]] {
+		failNow(t, fmt.Errorf("secret not taken from the allocator"))
+	}
+	s.Destroy()
+	if len(a.live) != 0 {
+		failNow(t, fmt.Errorf("secret not freed"))
+	}
+}
+
+func TestLockedAllocator(t *testing.T) {
+	a := NewLockedAllocator()
+	probe, err := a.Alloc(32)
+	if err != nil {
+		t.Skipf("secure memory unavailable: %v", err)
+	}
+	a.Free(probe)
+	secret := randomBytes(64)
+	shares, _ := CreateShares(secret, 3, 2)
+	s, err := Recover(shares[1:], WithAllocator(a))
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(s.Bytes(), secret) || cap(s.Bytes()) != len(secret) {
+		failNow(t, fmt.Errorf("unexpected locked secret"))
+	}
+	s.Destroy()
+}
@@ -0,0 +1,77 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package tss
+
+import (
+	"os"
+	"sync"
+	"syscall"
+)
+
+// LockedAllocator allocates buffers in memory locked with mlock, so they are
+// never swapped to disk, between inaccessible guard pages, so an overflow
+// faults instead of reading or writing past them. Every buffer takes whole
+// pages, it is meant for small, short lived secrets.
+type LockedAllocator struct {
+	mu      sync.Mutex
+	regions map[*byte][]byte
+}
+
+// NewLockedAllocator returns a LockedAllocator, on platforms without mlock its
+// Alloc fails with ErrSecureMemoryUnsupported
+func NewLockedAllocator() *LockedAllocator {
+	return &LockedAllocator{regions: make(map[*byte][]byte)}
+}
+
+// Alloc maps, locks and returns n bytes ending right before a guard page.
+// It fails when the process may not lock that much memory, see
+// RLIMIT_MEMLOCK.
+func (a *LockedAllocator) Alloc(n int) ([]byte, error) {
+	if n <= 0 {
+		return []byte{}, nil
+	}
+	page := os.Getpagesize()
+	inner := (n + page - 1) / page * page
+	region, err := syscall.Mmap(-1, 0, inner+2*page, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.guard(region, page, inner); err != nil {
+		syscall.Munmap(region)
+		return nil, err
+	}
+	b := region[page+inner-n : page+inner : page+inner]
+	a.mu.Lock()
+	a.regions[&b[0]] = region
+	a.mu.Unlock()
+	return b, nil
+}
+
+func (a *LockedAllocator) guard(region []byte, page int, inner int) error {
+	if err := syscall.Mprotect(region[:page], syscall.PROT_NONE); err != nil {
+		return err
+	}
+	if err := syscall.Mprotect(region[page+inner:], syscall.PROT_NONE); err != nil {
+		return err
+	}
+	return syscall.Mlock(region[page : page+inner])
+}
+
+// Free wipes b, unlocks and unmaps it. Buffers not returned by Alloc are only
+// wiped.
+func (a *LockedAllocator) Free(b []byte) {
+	erase(b)
+	if len(b) == 0 {
+		return
+	}
+	a.mu.Lock()
+	region, ok := a.regions[&b[0]]
+	delete(a.regions, &b[0])
+	a.mu.Unlock()
+	if !ok {
+		return
+	}
+	page := os.Getpagesize()
+	syscall.Munlock(region[page : len(region)-page])
+	syscall.Munmap(region)
+}
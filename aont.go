@@ -0,0 +1,95 @@
+package tss
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/subtle"
+	"io"
+)
+
+// aontCanaryBytes is the size of the known block appended to the data before
+// the transform, checked on recovery
+const aontCanaryBytes = 16
+
+var (
+	ErrCanary = integrityError("aont canary mismatch")
+)
+
+// CreateAONTShares splits data with AONT-RS: an all-or-nothing transform of
+// the data, then Rabin dispersal of the result. Any k shares rebuild the data
+// and every share is about 1/k of its size, like CreateIDAShares, but fewer
+// than k shares reveal nothing unless AES-256 or SHA-256 is broken.
+//
+// The transform encrypts the data followed by a canary block under a random
+// key, then appends the key masked with the SHA-256 of the ciphertext, so the
+// key can only be unmasked once the whole ciphertext is known. The key is
+// drawn from the source of the options, as the coefficients of CreateShares.
+func CreateAONTShares(data []byte, sharesCount int, k int, opts ...Option) (ShareSet, error) {
+	if len(data) == 0 {
+		return nil, ErrSecretRequired
+	}
+	if k > sharesCount || k < MinThreshold {
+		return nil, ErrInvalidThreshold
+	}
+	cfg := newConfig(opts)
+	random, err := cfg.source(data, k)
+	if err != nil {
+		return nil, err
+	}
+	key := make([]byte, sha256.Size)
+	defer erase(key)
+	if _, err := io.ReadFull(random, key); err != nil {
+		return nil, err
+	}
+	pkg := make([]byte, len(data)+aontCanaryBytes+sha256.Size)
+	defer erase(pkg)
+	body := pkg[:len(data)+aontCanaryBytes]
+	copy(body, data)
+	if err := aontStream(key, body); err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(body)
+	subtle.XORBytes(pkg[len(body):], key, digest[:])
+	return createPacked(pkg, sharesCount, k, k, cfg)
+}
+
+// RecoverAONTData rebuilds data split by CreateAONTShares from at least k
+// shares. A corrupt share fails the canary check with ErrCanary.
+func RecoverAONTData(shares ShareSet, k int, opts ...Option) ([]byte, error) {
+	if k < MinThreshold || k > MaxShares {
+		return nil, ErrInvalidThreshold
+	}
+	pkg, err := recoverPacked(shares, k, k, newConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	if len(pkg) < aontCanaryBytes+sha256.Size {
+		return nil, ErrInvalidShare
+	}
+	body := pkg[:len(pkg)-sha256.Size]
+	digest := sha256.Sum256(body)
+	key := make([]byte, sha256.Size)
+	defer erase(key)
+	subtle.XORBytes(key, pkg[len(body):], digest[:])
+	if err := aontStream(key, body); err != nil {
+		return nil, err
+	}
+	data, canary := body[:len(body)-aontCanaryBytes], body[len(body)-aontCanaryBytes:]
+	if subtle.ConstantTimeCompare(canary, make([]byte, aontCanaryBytes)) != 1 {
+		erase(pkg)
+		return nil, ErrCanary
+	}
+	return data, nil
+}
+
+// aontStream xors b with the AES-256-CTR key stream of key, the key being
+// used for a single message the iv is zero
+func aontStream(key []byte, b []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	cipher.NewCTR(block, make([]byte, aes.BlockSize)).XORKeyStream(b, b)
+	return nil
+}
@@ -0,0 +1,38 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestAONT(t *testing.T) {
+	data := randomBytes(10000)
+	shares, err := CreateAONTShares(data, 5, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	if len(shares[0]) > len(data)/3+100 {
+		failNow(t, fmt.Errorf("share size %d", len(shares[0])))
+	}
+	got, err := RecoverAONTData(shares[2:], 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(got, data) {
+		failNow(t, fmt.Errorf("data mismatch"))
+	}
+	shares[3][100] ^= 1
+	if _, err := RecoverAONTData(shares[2:], 3); err != ErrCanary {
+		failNow(t, expected(ErrCanary, err))
+	}
+}
+
+func TestAONTStrict(t *testing.T) {
+	if _, err := CreateAONTShares(randomBytes(100), 5, 3, WithRand(bytes.NewReader(randomBytes(100))), WithStrictMode()); err != ErrNotAllowed {
+		failNow(t, expected(ErrNotAllowed, err))
+	}
+	if _, err := CreateAONTShares(randomBytes(100), 5, 3, WithStrictMode()); err != nil {
+		failNow(t, err)
+	}
+}
@@ -0,0 +1,150 @@
+package tss
+
+// RecoverSecretCorrecting recovers the secret from more than threshold shares
+// when some of them may have been modified, and returns the indexes of the
+// modified shares. Every byte is decoded with the Berlekamp-Welch algorithm,
+// which corrects up to (len(shares)-threshold)/2 modified shares without any
+// digest of the secret. More modified shares give ErrUncorrectable.
+func RecoverSecretCorrecting(shares ShareSet, threshold int, opts ...Option) (secret []byte, cheaters []byte, err error) {
+	cfg := newConfig(opts)
+	if threshold < cfg.minThreshold() || threshold > MaxShares {
+		return nil, nil, ErrInvalidThreshold
+	}
+	if len(shares) < threshold {
+		return nil, nil, &ThresholdError{Need: threshold, Have: len(shares)}
+	}
+	secretSize, err := checkShares(shares, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	n := len(shares)
+	errs := (n - threshold) / 2
+	x := make([]byte, n)
+	y := make([]byte, n)
+	defer erase(y)
+	for i, s := range shares {
+		x[i] = s[0]
+	}
+	modified := make([]bool, n)
+	secret = make([]byte, secretSize)
+	for j := range secret {
+		for i, s := range shares {
+			y[i] = s[j+1]
+		}
+		p, err := welchDecode(x, y, threshold, errs)
+		if err != nil {
+			erase(secret)
+			return nil, nil, err
+		}
+		secret[j] = p[0]
+		for i := range x {
+			if eval(x[i], p) != y[i] {
+				modified[i] = true
+			}
+		}
+		erase(p)
+	}
+	cheaters = []byte{}
+	for i, m := range modified {
+		if m {
+			cheaters = append(cheaters, x[i])
+		}
+	}
+	return secret, cheaters, nil
+}
+
+// welchDecode returns the coefficients, lowest degree first, of the
+// polynomial of degree below k going through all the points (x, y) but at
+// most e of them
+func welchDecode(x []byte, y []byte, k int, e int) ([]byte, error) {
+	// unknowns are the k+e coefficients of Q and the e low coefficients of
+	// the monic error locator E, with Q(x) = y E(x) at every point
+	cols := k + 2*e
+	m := make([][]byte, len(x))
+	for i := range x {
+		row := make([]byte, cols+1)
+		var xi byte = 1
+		for c := 0; c < k+e; c++ {
+			row[c] = xi
+			if c < e {
+				row[k+e+c] = mul(y[i], xi)
+			}
+			if c == e {
+				row[cols] = mul(y[i], xi)
+			}
+			xi = mul(xi, x[i])
+		}
+		m[i] = row
+	}
+	sol, err := solveGF(m, cols)
+	if err != nil {
+		return nil, err
+	}
+	q := sol[:k+e]
+	loc := append(sol[k+e:], 1)
+	// P = Q / E, the division must be exact
+	p := make([]byte, k)
+	for d := len(q) - 1; d >= e; d-- {
+		c := q[d]
+		p[d-e] = c
+		for t := range loc {
+			q[d-e+t] = add(q[d-e+t], mul(c, loc[t]))
+		}
+	}
+	for _, r := range q[:e] {
+		if r != 0 {
+			return nil, ErrUncorrectable
+		}
+	}
+	wrong := 0
+	for i := range x {
+		if eval(x[i], p) != y[i] {
+			wrong++
+		}
+	}
+	if wrong > e {
+		return nil, ErrUncorrectable
+	}
+	return p, nil
+}
+
+// solveGF solves the linear system m, rows holding cols coefficients and the
+// right hand side, by Gaussian elimination. Free unknowns are set to zero.
+func solveGF(m [][]byte, cols int) ([]byte, error) {
+	pivots := make([]int, 0, cols)
+	r := 0
+	for c := 0; c < cols && r < len(m); c++ {
+		p := r
+		for p < len(m) && m[p][c] == 0 {
+			p++
+		}
+		if p == len(m) {
+			continue
+		}
+		m[r], m[p] = m[p], m[r]
+		inv := div(1, m[r][c])
+		for t := c; t <= cols; t++ {
+			m[r][t] = mul(m[r][t], inv)
+		}
+		for i := range m {
+			if i != r && m[i][c] != 0 {
+				f := m[i][c]
+				for t := c; t <= cols; t++ {
+					m[i][t] = add(m[i][t], mul(f, m[r][t]))
+				}
+			}
+		}
+		pivots = append(pivots, c)
+		r++
+	}
+	for i := r; i < len(m); i++ {
+		if m[i][cols] != 0 {
+			return nil, ErrUncorrectable
+		}
+	}
+	sol := make([]byte, cols)
+	for i, c := range pivots {
+		sol[c] = m[i][cols]
+	}
+	return sol, nil
+}
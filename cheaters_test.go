@@ -0,0 +1,48 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRecoverSecretCorrecting(t *testing.T) {
+	secret := randomBytes(32)
+	shares, _ := CreateShares(secret, 9, 3)
+	shares[2][4] ^= 0x55
+	shares[5] = NewShare(6, randomBytes(32))
+	shares[7][32] ^= 1
+	recovered, cheaters, err := RecoverSecretCorrecting(shares, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+	if !bytes.Equal(cheaters, []byte{3, 6, 8}) {
+		failNow(t, fmt.Errorf("cheaters %v, want [3 6 8]", cheaters))
+	}
+
+	honest, _ := CreateShares(secret, 4, 3)
+	recovered, cheaters, err = RecoverSecretCorrecting(honest, 3)
+	if err != nil || !bytes.Equal(recovered, secret) || len(cheaters) != 0 {
+		failNow(t, fmt.Errorf("honest shares: %v %v", cheaters, err))
+	}
+}
+
+func TestRecoverSecretCorrectingErrors(t *testing.T) {
+	secret := randomBytes(32)
+	shares, _ := CreateShares(secret, 5, 3)
+	shares[0][1] ^= 1
+	shares[1][1] ^= 2
+	if _, _, err := RecoverSecretCorrecting(shares, 3); err != ErrUncorrectable {
+		failNow(t, expected(ErrUncorrectable, err))
+	}
+	if _, _, err := RecoverSecretCorrecting(shares[:2], 3); !errors.Is(err, ErrThresholdNotMet) {
+		failNow(t, expected(ErrThresholdNotMet, err))
+	}
+	if _, _, err := RecoverSecretCorrecting(shares, 1); err != ErrInvalidThreshold {
+		failNow(t, expected(ErrInvalidThreshold, err))
+	}
+}
@@ -0,0 +1,246 @@
+package tss
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strconv"
+)
+
+const (
+	// ChunkBytes is the size of the chunks a large secret is cut in, the
+	// last one may be shorter
+	ChunkBytes = MaxSecretBytes
+	// chunkedMagic starts the binary form of a ChunkedShare
+	chunkedMagic = "TSSCHUNK"
+	// chunkedVersion is the version of the binary form of a ChunkedShare
+	chunkedVersion = 1
+	// manifestBytes is the size of the binary form of a Manifest
+	manifestBytes = MaxIdentifierBytes + 8 + 2 + 4 + 1
+)
+
+var (
+	ErrManifestMismatch = validationError("shares belong to different chunked splits")
+)
+
+// Manifest describes how a large secret was cut in chunks, every
+// ChunkedShare of a split carries the same manifest apart from Index
+type Manifest struct {
+	// Identifier is random and tells apart chunked splits
+	Identifier [MaxIdentifierBytes]byte
+	// SecretBytes is the size of the whole secret
+	SecretBytes int64
+	// ChunkBytes is the size of every chunk but the last
+	ChunkBytes int
+	// Chunks is the number of chunks
+	Chunks int
+	// Index is the share index of the custodian holding the ChunkedShare
+	Index byte
+}
+
+// ChunkedShare is what a custodian holds for a secret larger than
+// MaxSecretBytes: the manifest and one share per chunk, Chunks[i] being the
+// share of the chunk with sequence number i
+type ChunkedShare struct {
+	Manifest Manifest
+	Chunks   ShareSet
+}
+
+// CreateChunkedShares splits a secret of any size up to
+// DefaultMaxChunkedSecretBytes, or the size set by WithMaxSecretBytes, cutting
+// it in chunks of ChunkBytes that are split independently
+func CreateChunkedShares(secret []byte, sharesCount int, threshold int, opts ...Option) ([]ChunkedShare, error) {
+	cfg := newConfig(opts)
+	if len(secret) == 0 {
+		return nil, ErrSecretRequired
+	}
+	if int64(len(secret)) > cfg.chunkedSecretLimit() {
+		return nil, ErrSecretTooLarge
+	}
+	m := Manifest{SecretBytes: int64(len(secret)), ChunkBytes: ChunkBytes}
+	m.Chunks = (len(secret) + ChunkBytes - 1) / ChunkBytes
+	random, err := cfg.source(secret, threshold)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(random, m.Identifier[:]); err != nil {
+		return nil, err
+	}
+	shares := make([]ChunkedShare, sharesCount)
+	for seq := 0; seq < m.Chunks; seq++ {
+		end := (seq + 1) * ChunkBytes
+		if end > len(secret) {
+			end = len(secret)
+		}
+		// the secret is wiped once every chunk is split
+		chunkOpts := append(opts[:len(opts):len(opts)], WithConsumeSecret(false))
+		if cfg.deterministic {
+			// equal chunks must not get equal shares
+			chunkOpts = append(chunkOpts, WithDeterministic(cfg.salt, cfg.info+"\x00chunk"+string(m.Identifier[:])+strconv.Itoa(seq)))
+		}
+		chunk, err := CreateShares(secret[seq*ChunkBytes:end], sharesCount, threshold, chunkOpts...)
+		if err != nil {
+			for _, s := range shares {
+				eraseShares(s.Chunks)
+			}
+			return nil, err
+		}
+		for j := range shares {
+			shares[j].Chunks = append(shares[j].Chunks, chunk[j])
+		}
+	}
+	for j := range shares {
+		shares[j].Manifest = m
+		shares[j].Manifest.Index = byte(j + 1)
+	}
+	if cfg.consume {
+		erase(secret)
+	}
+	return shares, nil
+}
+
+// RecoverChunkedSecret reconstructs a secret split by CreateChunkedShares,
+// shares of different splits are reported as a *MixedSharesError
+func RecoverChunkedSecret(shares []ChunkedShare, opts ...Option) ([]byte, error) {
+	cfg := newConfig(opts)
+	if len(shares) < MinShares {
+		return nil, ErrTooFewShares
+	}
+	if len(shares) > cfg.sharesLimit() {
+		return nil, ErrTooManyShares
+	}
+	m := shares[0].Manifest
+	if m.SecretBytes > cfg.chunkedSecretLimit() {
+		return nil, ErrSecretTooLarge
+	}
+	ids := make([][]byte, len(shares))
+	for i := range shares {
+		ids[i] = shares[i].Manifest.Identifier[:]
+	}
+	if err := mixedShares(ids...); err != nil {
+		return nil, err
+	}
+	for i, s := range shares {
+		if err := s.validate(); err != nil {
+			return nil, &ShareError{Position: i + 1, Index: s.Manifest.Index, Reason: err}
+		}
+		sm := s.Manifest
+		sm.Index = m.Index
+		if sm != m {
+			return nil, ErrManifestMismatch
+		}
+	}
+	var chunkOpts []Option
+	if cfg.parallelism > 1 {
+		chunkOpts = append(chunkOpts, WithParallelism(cfg.parallelism))
+	}
+	secret := make([]byte, 0, m.SecretBytes)
+	set := make(ShareSet, len(shares))
+	for seq := 0; seq < m.Chunks; seq++ {
+		for i, s := range shares {
+			set[i] = s.Chunks[seq]
+		}
+		chunk, err := RecoverSecret(set, chunkOpts...)
+		if err != nil {
+			return nil, err
+		}
+		secret = append(secret, chunk...)
+		erase(chunk)
+	}
+	return secret, nil
+}
+
+// validate checks the chunks agree with the manifest
+func (s ChunkedShare) validate() error {
+	m := s.Manifest
+	if m.SecretBytes < 1 || m.ChunkBytes < MinSecretBytes || m.ChunkBytes > MaxSecretBytes || m.Index == 0 {
+		return ErrInvalidShare
+	}
+	if int64(m.Chunks) != (m.SecretBytes+int64(m.ChunkBytes)-1)/int64(m.ChunkBytes) || len(s.Chunks) != m.Chunks {
+		return ErrInvalidShare
+	}
+	for seq, chunk := range s.Chunks {
+		size := int64(m.ChunkBytes)
+		if seq == m.Chunks-1 {
+			size = m.SecretBytes - int64(seq)*size
+		}
+		if int64(len(chunk)) != size+1 || chunk[0] != m.Index {
+			return ErrInvalidShare
+		}
+	}
+	return nil
+}
+
+// MarshalBinary encodes the share as the manifest followed by the chunks,
+// each prefixed by its sequence number
+func (s ChunkedShare) MarshalBinary() ([]byte, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	m := s.Manifest
+	var b bytes.Buffer
+	b.WriteString(chunkedMagic)
+	b.WriteByte(chunkedVersion)
+	b.Write(m.Identifier[:])
+	binary.Write(&b, binary.BigEndian, uint64(m.SecretBytes))
+	binary.Write(&b, binary.BigEndian, uint16(m.ChunkBytes))
+	binary.Write(&b, binary.BigEndian, uint32(m.Chunks))
+	b.WriteByte(m.Index)
+	for seq, chunk := range s.Chunks {
+		binary.Write(&b, binary.BigEndian, uint32(seq))
+		b.Write(chunk[1:])
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a share written by MarshalBinary
+func (s *ChunkedShare) UnmarshalBinary(data []byte) error {
+	if !bytes.HasPrefix(data, []byte(chunkedMagic)) {
+		return ErrInvalidShare
+	}
+	p := data[len(chunkedMagic):]
+	if len(p) < 1+manifestBytes {
+		return ErrInvalidShare
+	}
+	if p[0] != chunkedVersion {
+		return ErrUnsupportedVersion
+	}
+	p = p[1:]
+	var m Manifest
+	copy(m.Identifier[:], p)
+	p = p[MaxIdentifierBytes:]
+	secretBytes := binary.BigEndian.Uint64(p)
+	m.ChunkBytes = int(binary.BigEndian.Uint16(p[8:]))
+	chunks := binary.BigEndian.Uint32(p[10:])
+	m.Index = p[14]
+	p = p[15:]
+	if secretBytes == 0 || secretBytes > 1<<62 || m.ChunkBytes < MinSecretBytes {
+		return ErrInvalidShare
+	}
+	m.SecretBytes = int64(secretBytes)
+	if uint64(chunks) != (secretBytes+uint64(m.ChunkBytes)-1)/uint64(m.ChunkBytes) {
+		return ErrInvalidShare
+	}
+	m.Chunks = int(chunks)
+	// not preallocated, the chunk count is not trusted until the data is there
+	var shares ShareSet
+	for seq := 0; seq < m.Chunks; seq++ {
+		size := int64(m.ChunkBytes)
+		if seq == m.Chunks-1 {
+			size = m.SecretBytes - int64(seq)*size
+		}
+		if int64(len(p)) < 4+size || binary.BigEndian.Uint32(p) != uint32(seq) {
+			return ErrInvalidShare
+		}
+		chunk := make(Share, 1+size)
+		chunk[0] = m.Index
+		copy(chunk[1:], p[4:4+size])
+		shares = append(shares, chunk)
+		p = p[4+size:]
+	}
+	if len(p) != 0 {
+		return ErrInvalidShare
+	}
+	s.Manifest, s.Chunks = m, shares
+	return s.validate()
+}
@@ -0,0 +1,58 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestChunked(t *testing.T) {
+	secret := randomBytes(2*ChunkBytes + 100)
+	shares, err := CreateChunkedShares(secret, 3, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	if shares[0].Manifest.Chunks != 3 {
+		failNow(t, fmt.Errorf("%d chunks, want 3", shares[0].Manifest.Chunks))
+	}
+	data, err := shares[2].MarshalBinary()
+	if err != nil {
+		failNow(t, err)
+	}
+	var decoded ChunkedShare
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		failNow(t, err)
+	}
+	recovered, err := RecoverChunkedSecret([]ChunkedShare{decoded, shares[0]})
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); err != ErrInvalidShare {
+		failNow(t, expected(ErrInvalidShare, err))
+	}
+}
+
+func TestChunkedErrors(t *testing.T) {
+	a, _ := CreateChunkedShares(randomBytes(100), 3, 2)
+	b, _ := CreateChunkedShares(randomBytes(100), 3, 2)
+	_, err := RecoverChunkedSecret([]ChunkedShare{a[0], b[1], a[2]})
+	var mixed *MixedSharesError
+	if !errors.As(err, &mixed) || !errors.Is(err, ErrMixedShares) {
+		failNow(t, expected(ErrMixedShares, err))
+	}
+	if len(mixed.Identifiers) != 2 || !bytes.Equal(mixed.Identifiers[0], a[0].Manifest.Identifier[:]) || !bytes.Equal(mixed.Identifiers[1], b[1].Manifest.Identifier[:]) {
+		failNow(t, fmt.Errorf("unexpected identifiers %x", mixed.Identifiers))
+	}
+	a[1].Chunks = a[1].Chunks[:0]
+	_, err = RecoverChunkedSecret(a)
+	if se, ok := err.(*ShareError); !ok || se.Position != 2 || !errors.Is(err, ErrInvalidShare) {
+		failNow(t, expected(ErrInvalidShare, err))
+	}
+	if _, err := CreateChunkedShares(nil, 2, 2); err != ErrSecretRequired {
+		failNow(t, expected(ErrSecretRequired, err))
+	}
+}
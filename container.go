@@ -0,0 +1,117 @@
+package tss
+
+import (
+	"bytes"
+	"crypto/sha256"
+)
+
+const (
+	// ContainerVersion is the container format version written by MarshalContainer
+	ContainerVersion = 1
+	// containerMagic starts every container. The high bit byte and the line
+	// endings catch 7 bit and text mode transfers, as in PNG.
+	containerMagic = "\x89TSS\r\n\x1a\n"
+)
+
+var (
+	ErrNotContainer       = validationError("not a share container")
+	ErrUnsupportedVersion = validationError("unsupported container version")
+	ErrCorruptContainer   = integrityError("corrupt share container")
+)
+
+// MarshalContainer writes a share and its params in the canonical on-disk
+// container:
+//
+//	magic       8 bytes "\x89TSS\r\n\x1a\n"
+//	version     1 byte
+//	header len  2 bytes
+//	header      threshold (1 byte), identifier len (1 byte), identifier, extensions
+//	share len   2 bytes
+//	share       index followed by the share bytes
+//	digest      SHA-256 of all the above
+//
+// Extensions follow the identifier up to the end of the header, each as type
+// (1 byte), length (2 bytes) and value.
+// Lengths are big endian. Readers keep extensions of unknown types without
+// interpreting them, so later revisions of the same version can add header
+// fields as new extension types; incompatible
+// layouts bump the version and are rejected by older readers instead of being
+// misread.
+func MarshalContainer(share Share, params ShareParams) ([]byte, error) {
+	if len(share) < MinShareBytes || len(share) > MaxShareBytes {
+		return nil, ErrInvalidShare
+	}
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	header := []byte{byte(params.Threshold), byte(len(params.Identifier))}
+	header = append(header, params.Identifier...)
+	header = appendExtensions(header, params.Extensions)
+	var b bytes.Buffer
+	b.WriteString(containerMagic)
+	b.WriteByte(ContainerVersion)
+	b.Write([]byte{byte(len(header) >> 8), byte(len(header))})
+	b.Write(header)
+	b.Write([]byte{byte(len(share) >> 8), byte(len(share))})
+	b.Write(share)
+	digest := sha256.Sum256(b.Bytes())
+	b.Write(digest[:])
+	return b.Bytes(), nil
+}
+
+// UnmarshalContainer reads a container written by MarshalContainer. Anything
+// that does not parse exactly, including trailing bytes, is rejected.
+func UnmarshalContainer(data []byte) (Share, ShareParams, error) {
+	if !bytes.HasPrefix(data, []byte(containerMagic)) {
+		return nil, ShareParams{}, ErrNotContainer
+	}
+	p := data[len(containerMagic):]
+	if len(p) < 1 {
+		return nil, ShareParams{}, ErrCorruptContainer
+	}
+	if p[0] != ContainerVersion {
+		return nil, ShareParams{}, ErrUnsupportedVersion
+	}
+	if len(data) < len(containerMagic)+sha256.Size {
+		return nil, ShareParams{}, ErrCorruptContainer
+	}
+	body := data[:len(data)-sha256.Size]
+	digest := sha256.Sum256(body)
+	if !bytes.Equal(digest[:], data[len(body):]) {
+		return nil, ShareParams{}, ErrCorruptContainer
+	}
+	p = body[len(containerMagic)+1:]
+	header, p, ok := cut16(p)
+	if !ok || len(header) < 2 || len(header) < 2+int(header[1]) {
+		return nil, ShareParams{}, ErrCorruptContainer
+	}
+	params := ShareParams{Threshold: int(header[0]), Identifier: append([]byte(nil), header[2:2+int(header[1])]...)}
+	exts, err := parseExtensions(header[2+int(header[1]):])
+	if err != nil {
+		return nil, ShareParams{}, err
+	}
+	params.Extensions = exts
+	share, p, ok := cut16(p)
+	if !ok || len(p) != 0 {
+		return nil, ShareParams{}, ErrCorruptContainer
+	}
+	if len(share) < MinShareBytes {
+		return nil, ShareParams{}, ErrInvalidShare
+	}
+	if err := params.validate(); err != nil {
+		return nil, ShareParams{}, err
+	}
+	return append(Share{}, share...), params, nil
+}
+
+// cut16 splits a field prefixed by its 2 bytes big endian length from p
+func cut16(p []byte) (field []byte, rest []byte, ok bool) {
+	if len(p) < 2 {
+		return nil, nil, false
+	}
+	n := int(p[0])<<8 | int(p[1])
+	if len(p) < 2+n {
+		return nil, nil, false
+	}
+	return p[2 : 2+n], p[2+n:], true
+}
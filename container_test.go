@@ -0,0 +1,99 @@
+package tss
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+)
+
+func TestContainer(t *testing.T) {
+	shares, err := CreateShares(randomBytes(32), 3, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	params := ShareParams{Identifier: []byte("vault"), Threshold: 2}
+	data, err := MarshalContainer(shares[2], params)
+	if err != nil {
+		failNow(t, err)
+	}
+	share, p, err := UnmarshalContainer(data)
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(share, shares[2]) || !bytes.Equal(p.Identifier, params.Identifier) || p.Threshold != 2 {
+		failNow(t, fmt.Errorf("roundtrip mismatch"))
+	}
+	corrupted := append([]byte{}, data...)
+	corrupted[len(corrupted)-40] ^= 1
+	testCaseContainerExpect(t, corrupted, ErrCorruptContainer)
+	testCaseContainerExpect(t, append(data, 0), ErrCorruptContainer)
+	testCaseContainerExpect(t, shares[2], ErrNotContainer)
+	v2 := append([]byte{}, data...)
+	v2[len(containerMagic)] = ContainerVersion + 1
+	testCaseContainerExpect(t, v2, ErrUnsupportedVersion)
+}
+
+func TestContainerUnknownHeaderFields(t *testing.T) {
+	share := randomBytes(10)
+	share[0] = 1
+	// header of a later revision, with an extension type unknown today
+	header := []byte{3, 2, 'i', 'd', 0x7f, 0, 2, 0xca, 0xfe}
+	var b bytes.Buffer
+	b.WriteString(containerMagic)
+	b.WriteByte(ContainerVersion)
+	b.Write([]byte{0, byte(len(header))})
+	b.Write(header)
+	b.Write([]byte{0, byte(len(share))})
+	b.Write(share)
+	digest := sha256.Sum256(b.Bytes())
+	b.Write(digest[:])
+	got, p, err := UnmarshalContainer(b.Bytes())
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(got, share) || string(p.Identifier) != "id" || p.Threshold != 3 {
+		failNow(t, fmt.Errorf("unexpected %x %+v", got, p))
+	}
+	if v, ok := p.Extension(0x7f); !ok || !bytes.Equal(v, []byte{0xca, 0xfe}) {
+		failNow(t, fmt.Errorf("unknown extension lost"))
+	}
+}
+
+func TestContainerExtensions(t *testing.T) {
+	shares, _ := CreateShares(randomBytes(32), 3, 2)
+	params := ShareParams{Threshold: 2, Extensions: []Extension{
+		{Type: ExtCustodian, Value: []byte("alice")},
+		{Type: ExtPolicyURI, Value: []byte("https://example.com/policy")},
+		{Type: ExtPrivate, Value: nil},
+	}}
+	data, err := MarshalContainer(shares[0], params)
+	if err != nil {
+		failNow(t, err)
+	}
+	_, p, err := UnmarshalContainer(data)
+	if err != nil {
+		failNow(t, err)
+	}
+	if v, _ := p.Extension(ExtCustodian); string(v) != "alice" {
+		failNow(t, fmt.Errorf("custodian %q", v))
+	}
+	if _, ok := p.Extension(ExtPrivate); !ok || len(p.Extensions) != 3 {
+		failNow(t, fmt.Errorf("unexpected extensions %+v", p.Extensions))
+	}
+	// extensions are covered by the digest
+	i := bytes.Index(data, []byte("alice"))
+	data[i] = 'A'
+	testCaseContainerExpect(t, data, ErrCorruptContainer)
+	params.Extensions = []Extension{{Type: ExtPurpose, Value: make([]byte, maxExtensionsBytes)}}
+	if _, err := MarshalContainer(shares[0], params); err != ErrInvalidExtension {
+		failNow(t, expected(ErrInvalidExtension, err))
+	}
+}
+
+func testCaseContainerExpect(t *testing.T, data []byte, expect error) {
+	_, _, err := UnmarshalContainer(data)
+	if err != expect {
+		failNow(t, expected(expect, err))
+	}
+}
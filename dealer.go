@@ -0,0 +1,94 @@
+package tss
+
+import "io"
+
+var (
+	ErrDealerDestroyed = validationError("dealer destroyed")
+)
+
+// Dealer keeps the polynomials of a split so shares can be issued for new
+// custodians later, without splitting again and redistributing to everyone.
+// It holds material equivalent to the secret, call Destroy as soon as no more
+// shares are needed.
+type Dealer struct {
+	threshold  int
+	secretSize int
+	// coefficients of the polynomial of every secret byte, threshold bytes
+	// per secret byte, the constant term being the secret byte
+	coefficients []byte
+}
+
+// NewDealer draws the polynomials of a split of secret with the given
+// threshold. WithMinSecretBytes, WithMaxSecretBytes, WithRand and
+// WithDeterministic apply.
+func NewDealer(secret []byte, threshold int, opts ...Option) (*Dealer, error) {
+	cfg := newConfig(opts)
+	if len(secret) == 0 {
+		return nil, ErrSecretRequired
+	}
+	if len(secret) < cfg.minSecretBytes {
+		return nil, ErrSecretTooShort
+	}
+	if len(secret) > cfg.secretLimit() {
+		return nil, ErrSecretTooLarge
+	}
+	if threshold < MinThreshold || threshold > MaxShares {
+		return nil, ErrInvalidThreshold
+	}
+	random, err := cfg.source(secret, threshold)
+	if err != nil {
+		return nil, err
+	}
+	d := &Dealer{threshold: threshold, secretSize: len(secret)}
+	d.coefficients = make([]byte, len(secret)*threshold)
+	if _, err := io.ReadFull(random, d.coefficients); err != nil {
+		erase(d.coefficients)
+		return nil, err
+	}
+	for i, b := range secret {
+		d.coefficients[i*threshold] = b
+	}
+	return d, nil
+}
+
+// Threshold returns the number of shares required to recover the secret
+func (d *Dealer) Threshold() int {
+	return d.threshold
+}
+
+// Share issues the share at index, issuing the same index twice gives the
+// same share
+func (d *Dealer) Share(index byte) (Share, error) {
+	if d.coefficients == nil {
+		return nil, ErrDealerDestroyed
+	}
+	if index == 0 {
+		return nil, ErrInvalidShareIndex
+	}
+	share := make(Share, d.secretSize+1)
+	share[0] = index
+	for i := 0; i < d.secretSize; i++ {
+		share[i+1] = eval(index, d.coefficients[i*d.threshold:(i+1)*d.threshold])
+	}
+	return share, nil
+}
+
+// Shares issues the shares at indexes
+func (d *Dealer) Shares(indexes ...byte) (ShareSet, error) {
+	shares := make(ShareSet, 0, len(indexes))
+	for _, x := range indexes {
+		s, err := d.Share(x)
+		if err != nil {
+			eraseShares(shares)
+			return nil, err
+		}
+		shares = append(shares, s)
+	}
+	return shares, nil
+}
+
+// Destroy erases the polynomials, no share can be issued afterwards
+func (d *Dealer) Destroy() {
+	erase(d.coefficients)
+	d.coefficients = nil
+}
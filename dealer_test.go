@@ -0,0 +1,46 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestDealer(t *testing.T) {
+	secret := randomBytes(32)
+	d, err := NewDealer(secret, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	shares, err := d.Shares(1, 2, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	testRecover(t, secret, shares)
+	// a custodian enrolled later
+	late, err := d.Share(200)
+	if err != nil {
+		failNow(t, err)
+	}
+	testRecover(t, secret, ShareSet{shares[0], late, shares[2]})
+	again, _ := d.Share(2)
+	if !bytes.Equal(again, shares[1]) {
+		failNow(t, fmt.Errorf("share 2 differs when issued again"))
+	}
+	if _, err := d.Share(0); err != ErrInvalidShareIndex {
+		failNow(t, expected(ErrInvalidShareIndex, err))
+	}
+	d.Destroy()
+	if _, err := d.Share(4); err != ErrDealerDestroyed {
+		failNow(t, expected(ErrDealerDestroyed, err))
+	}
+}
+
+func TestDealerErrors(t *testing.T) {
+	if _, err := NewDealer(nil, 2); err != ErrSecretRequired {
+		failNow(t, expected(ErrSecretRequired, err))
+	}
+	if _, err := NewDealer(randomBytes(32), 1); err != ErrInvalidThreshold {
+		failNow(t, expected(ErrInvalidThreshold, err))
+	}
+}
@@ -0,0 +1,187 @@
+// Package decrypt decrypts with a key shared by the tss and dkg packages
+// without reconstructing it. Messages are encrypted to the public key with
+// hashed ElGamal over P-256: every shareholder computes a partial decryption
+// from its share, with a proof it is right, and threshold partials combine
+// into the key of the message.
+package decrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	tss "github.com/antik10ud/go-tss"
+	"github.com/antik10ud/go-tss/internal/p256"
+)
+
+var (
+	ErrInvalidCiphertext = fmt.Errorf("invalid ciphertext: %w", tss.ErrValidation)
+	ErrInvalidPartial    = fmt.Errorf("invalid partial decryption: %w", tss.ErrIntegrity)
+	ErrDecryption        = fmt.Errorf("decryption failed: %w", tss.ErrIntegrity)
+)
+
+const (
+	// proofDomain separates the challenges of the proofs from other hashes
+	proofDomain = "go-tss decrypt dleq"
+	// keyInfo is the HKDF info of the message keys
+	keyInfo = "go-tss decrypt key"
+	// pointBytes is the size of a compressed point
+	pointBytes = 33
+)
+
+// messageKey derives the AES-256 key of a message from the shared point and
+// the ephemeral and public keys, and returns its AEAD
+func messageKey(shared p256.Point, ephemeral []byte, publicKey []byte) (cipher.AEAD, error) {
+	info := append(append([]byte(keyInfo), ephemeral...), publicKey...)
+	key, err := hkdf.Key(sha256.New, shared.Bytes(), nil, string(info), 32)
+	if err != nil {
+		return nil, err
+	}
+	defer tss.Wipe(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt encrypts plaintext to the compressed P-256 publicKey of a shared
+// key, such as dkg.Result holds, additionalData being authenticated but not
+// encrypted. The ciphertext is the ephemeral public key r*G followed by the
+// AES-256-GCM encryption of plaintext under a key derived from r times the
+// public key; the key is used once, so the nonce is zero.
+func Encrypt(publicKey []byte, plaintext []byte, additionalData []byte) ([]byte, error) {
+	pk, ok := p256.Decode(publicKey)
+	if !ok {
+		return nil, tss.ErrInvalidPoint
+	}
+	r, err := rand.Int(rand.Reader, p256.Order)
+	if err != nil {
+		return nil, err
+	}
+	defer r.SetInt64(0)
+	ephemeral := p256.BaseMul(r).Bytes()
+	aead, err := messageKey(pk.Mul(r), ephemeral, pk.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(ephemeral, make([]byte, aead.NonceSize()), plaintext, additionalData), nil
+}
+
+// ephemeral returns the ephemeral public key of ciphertext
+func ephemeral(ciphertext []byte) (p256.Point, error) {
+	if len(ciphertext) < pointBytes {
+		return p256.Point{}, ErrInvalidCiphertext
+	}
+	p, ok := p256.Decode(ciphertext[:pointBytes])
+	if !ok {
+		return p256.Point{}, ErrInvalidCiphertext
+	}
+	return p, nil
+}
+
+// Partial is the partial decryption of a ciphertext by a shareholder, its
+// share times the ephemeral key, with the proof it used its share
+type Partial struct {
+	Index byte
+	Point []byte
+	Proof []byte
+}
+
+// PartialDecrypt computes the partial decryption of ciphertext with share. It
+// can be sent in the clear, it tells nothing about the plaintext without
+// threshold-1 others.
+func PartialDecrypt(share tss.ScalarShare, ciphertext []byte) (*Partial, error) {
+	s, ok := p256.DecodeScalar(share.Value)
+	if !ok {
+		return nil, tss.ErrInvalidScalar
+	}
+	defer s.SetInt64(0)
+	if share.Index == 0 {
+		return nil, tss.ErrInvalidShareIndex
+	}
+	r, err := ephemeral(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	d := r.Mul(s)
+	proof, err := p256.ProveDLEQ(proofDomain, p256.Base(), p256.BaseMul(s), r, d, s, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Partial{Index: share.Index, Point: d.Bytes(), Proof: proof}, nil
+}
+
+// VerifyPartial checks partial was computed from the share of its index,
+// keyCommitments being the Feldman commitments of the shared key
+func VerifyPartial(ciphertext []byte, partial *Partial, keyCommitments tss.Commitments) error {
+	r, err := ephemeral(ciphertext)
+	if err != nil {
+		return err
+	}
+	public, ok := p256.VerificationShare(keyCommitments, partial.Index)
+	if !ok {
+		return tss.ErrInvalidCommitment
+	}
+	d, ok := p256.Decode(partial.Point)
+	if !ok || partial.Index == 0 || !p256.VerifyDLEQ(proofDomain, p256.Base(), public, r, d, partial.Proof) {
+		return ErrInvalidPartial
+	}
+	return nil
+}
+
+// Combine decrypts ciphertext from threshold partial decryptions. When the
+// commitments of the key are given every partial is checked first, a wrong
+// one giving a *tss.ShareError matching ErrInvalidPartial that names the
+// shareholder; otherwise a wrong partial gives ErrDecryption.
+func Combine(publicKey []byte, ciphertext []byte, additionalData []byte, partials []*Partial, keyCommitments tss.Commitments) ([]byte, error) {
+	pk, ok := p256.Decode(publicKey)
+	if !ok {
+		return nil, tss.ErrInvalidPoint
+	}
+	if _, err := ephemeral(ciphertext); err != nil {
+		return nil, err
+	}
+	if keyCommitments != nil && len(partials) < len(keyCommitments) {
+		return nil, &tss.ThresholdError{Need: len(keyCommitments), Have: len(partials)}
+	}
+	if len(partials) < tss.MinThreshold {
+		return nil, tss.ErrTooFewShares
+	}
+	var seen [256]bool
+	ids := make([]*big.Int, len(partials))
+	points := make([]p256.Point, len(partials))
+	for i, partial := range partials {
+		if keyCommitments != nil {
+			if err := VerifyPartial(ciphertext, partial, keyCommitments); err != nil {
+				return nil, &tss.ShareError{Position: i + 1, Index: partial.Index, Reason: err}
+			}
+		}
+		d, ok := p256.Decode(partial.Point)
+		if !ok || partial.Index == 0 {
+			return nil, &tss.ShareError{Position: i + 1, Index: partial.Index, Reason: ErrInvalidPartial}
+		}
+		if seen[partial.Index] {
+			return nil, &tss.ShareError{Position: i + 1, Index: partial.Index, Reason: tss.ErrDuplicateShare}
+		}
+		seen[partial.Index] = true
+		ids[i], points[i] = big.NewInt(int64(partial.Index)), d
+	}
+	shared := points[0].Mul(p256.Lagrange(0, ids))
+	for i := 1; i < len(points); i++ {
+		shared = shared.Add(points[i].Mul(p256.Lagrange(i, ids)))
+	}
+	aead, err := messageKey(shared, ciphertext[:pointBytes], pk.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, make([]byte, aead.NonceSize()), ciphertext[pointBytes:], additionalData)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	return plaintext, nil
+}
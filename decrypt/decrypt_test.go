@@ -0,0 +1,74 @@
+package decrypt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	tss "github.com/antik10ud/go-tss"
+)
+
+// sharedKey splits a random P-256 key, returning its shares, public key and
+// commitments
+func sharedKey(t *testing.T, n int, threshold int) ([]tss.ScalarShare, []byte, tss.Commitments) {
+	key := make([]byte, 32)
+	key[0] = 0x7f
+	copy(key[1:], []byte("a not so random key, test only"))
+	shares, commitments, err := tss.SplitFeldman(key, n, threshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return shares, commitments[0], commitments
+}
+
+func TestThresholdDecryption(t *testing.T) {
+	shares, publicKey, commitments := sharedKey(t, 5, 3)
+	plaintext, aad := []byte("launch codes"), []byte("header")
+	ciphertext, err := Encrypt(publicKey, plaintext, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var partials []*Partial
+	for _, s := range []tss.ScalarShare{shares[4], shares[1], shares[3]} {
+		p, err := PartialDecrypt(s, ciphertext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyPartial(ciphertext, p, commitments); err != nil {
+			t.Fatal(err)
+		}
+		partials = append(partials, p)
+	}
+	got, err := Combine(publicKey, ciphertext, aad, partials, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("plaintext %q", got)
+	}
+	if _, err := Combine(publicKey, ciphertext, []byte("other"), partials, nil); err != ErrDecryption {
+		t.Fatal(err)
+	}
+	if _, err := Combine(publicKey, ciphertext, aad, partials[:2], commitments); !errors.Is(err, tss.ErrThresholdNotMet) {
+		t.Fatal(err)
+	}
+}
+
+func TestInvalidPartial(t *testing.T) {
+	shares, publicKey, commitments := sharedKey(t, 3, 2)
+	ciphertext, _ := Encrypt(publicKey, []byte("secret"), nil)
+	good, _ := PartialDecrypt(shares[0], ciphertext)
+	bad, _ := PartialDecrypt(shares[1], ciphertext)
+	bad.Point = good.Point
+	if err := VerifyPartial(ciphertext, bad, commitments); err != ErrInvalidPartial {
+		t.Fatal(err)
+	}
+	_, err := Combine(publicKey, ciphertext, nil, []*Partial{good, bad}, commitments)
+	var shareErr *tss.ShareError
+	if !errors.As(err, &shareErr) || shareErr.Index != 2 || !errors.Is(err, ErrInvalidPartial) {
+		t.Fatal(err)
+	}
+	if _, err := PartialDecrypt(shares[0], ciphertext[:10]); err != ErrInvalidCiphertext {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,87 @@
+package tss
+
+import "encoding/asn1"
+
+// The DER encoding of a share follows this ASN.1 module:
+//
+//	TSS-Share DEFINITIONS ::= BEGIN
+//	TSSShare ::= SEQUENCE {
+//	    version     INTEGER { v1(1) },
+//	    identifier  OCTET STRING (SIZE(0..16)),
+//	    params      TSSParams,
+//	    payload     OCTET STRING (SIZE(2..65535)), -- share index followed by the share bytes
+//	    extensions  [0] EXPLICIT SEQUENCE OF TSSExtension OPTIONAL,
+//	    ...
+//	}
+//	TSSExtension ::= SEQUENCE {
+//	    type        INTEGER (0..255),
+//	    value       OCTET STRING
+//	}
+//	TSSParams ::= SEQUENCE {
+//	    threshold   INTEGER (1..255),
+//	    ...
+//	}
+//	END
+
+// derVersion is the version of the TSSShare structure
+const derVersion = 1
+
+type derShare struct {
+	Version    int
+	Identifier []byte
+	Params     derParams
+	Payload    []byte
+	Extensions []derExtension `asn1:"optional,explicit,tag:0"`
+}
+
+type derExtension struct {
+	Type  int
+	Value []byte
+}
+
+type derParams struct {
+	Threshold int
+}
+
+// MarshalShareDER encodes a share and its params as a DER TSSShare structure
+func MarshalShareDER(share Share, params ShareParams) ([]byte, error) {
+	if len(share) < MinShareBytes || len(share) > MaxShareBytes {
+		return nil, ErrInvalidShare
+	}
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	d := derShare{
+		Version:    derVersion,
+		Identifier: params.Identifier,
+		Params:     derParams{Threshold: params.Threshold},
+		Payload:    share,
+	}
+	for _, e := range params.Extensions {
+		d.Extensions = append(d.Extensions, derExtension{Type: int(e.Type), Value: e.Value})
+	}
+	return asn1.Marshal(d)
+}
+
+// UnmarshalShareDER decodes a DER TSSShare structure written by MarshalShareDER
+func UnmarshalShareDER(der []byte) (Share, ShareParams, error) {
+	var d derShare
+	rest, err := asn1.Unmarshal(der, &d)
+	if err != nil || len(rest) != 0 || d.Version != derVersion {
+		return nil, ShareParams{}, ErrInvalidShare
+	}
+	if len(d.Payload) < MinShareBytes || len(d.Payload) > MaxShareBytes {
+		return nil, ShareParams{}, ErrInvalidShare
+	}
+	params := ShareParams{Identifier: d.Identifier, Threshold: d.Params.Threshold}
+	for _, e := range d.Extensions {
+		if e.Type < 0 || e.Type > 0xff {
+			return nil, ShareParams{}, ErrInvalidExtension
+		}
+		params.Extensions = append(params.Extensions, Extension{Type: byte(e.Type), Value: e.Value})
+	}
+	if err := params.validate(); err != nil {
+		return nil, ShareParams{}, err
+	}
+	return d.Payload, params, nil
+}
@@ -0,0 +1,43 @@
+package tss
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"fmt"
+	"testing"
+)
+
+func TestShareDER(t *testing.T) {
+	shares, err := CreateShares(randomBytes(32), 3, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	params := ShareParams{Identifier: []byte("backup-2018"), Threshold: 2, Extensions: []Extension{{Type: ExtPurpose, Value: []byte("root ca")}}}
+	der, err := MarshalShareDER(shares[1], params)
+	if err != nil {
+		failNow(t, err)
+	}
+	share, p, err := UnmarshalShareDER(der)
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(share, shares[1]) || !bytes.Equal(p.Identifier, params.Identifier) || p.Threshold != params.Threshold {
+		failNow(t, fmt.Errorf("roundtrip mismatch"))
+	}
+	if v, _ := p.Extension(ExtPurpose); string(v) != "root ca" {
+		failNow(t, fmt.Errorf("roundtrip mismatch"))
+	}
+	if _, _, err := UnmarshalShareDER(append(der, 0)); err != ErrInvalidShare {
+		failNow(t, expected(ErrInvalidShare, err))
+	}
+	if _, err := MarshalShareDER(shares[1], ShareParams{Threshold: 0}); err != ErrInvalidParams {
+		failNow(t, expected(ErrInvalidParams, err))
+	}
+	if _, err := MarshalShareDER(shares[1], ShareParams{Identifier: randomBytes(MaxIdentifierBytes + 1), Threshold: 2}); err != ErrInvalidParams {
+		failNow(t, expected(ErrInvalidParams, err))
+	}
+	v2, _ := asn1.Marshal(derShare{Version: 2, Params: derParams{Threshold: 2}, Payload: shares[1]})
+	if _, _, err := UnmarshalShareDER(v2); err != ErrInvalidShare {
+		failNow(t, expected(ErrInvalidShare, err))
+	}
+}
@@ -0,0 +1,60 @@
+package tss
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"io"
+)
+
+// WithDeterministic derives the polynomial coefficients, and the indexes
+// picked by WithRandomIndexes, from HKDF-SHA256(secret, salt, info) instead of
+// fresh randomness: splitting the same secret with the same salt, info and
+// threshold yields the same shares. The threshold is bound into the
+// derivation so splits with different thresholds do not share coefficients.
+// The salt should be random and kept with the provisioning record, a fixed or
+// empty salt makes the shares of a low entropy secret easier to guess.
+// It takes precedence over WithRand.
+func WithDeterministic(salt []byte, info string) Option {
+	return func(c *config) {
+		c.deterministic = true
+		c.salt = append([]byte{}, salt...)
+		c.info = info
+	}
+}
+
+// source returns the reader the coefficients of a split of secret are drawn
+// from: the HKDF derived stream in deterministic mode, else the random source.
+// In strict mode it also refuses the split when checkStrict does.
+func (c *config) source(secret []byte, threshold int) (io.Reader, error) {
+	if err := c.checkStrict(true); err != nil {
+		return nil, err
+	}
+	if !c.deterministic {
+		return c.random(), nil
+	}
+	key, err := hkdf.Key(sha256.New, secret, c.salt, c.info+string([]byte{0, byte(threshold)}), 32)
+	if err != nil {
+		return nil, err
+	}
+	defer erase(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	// the key is used for a single stream, a zero iv is fine
+	iv := make([]byte, aes.BlockSize)
+	return &cipher.StreamReader{S: cipher.NewCTR(block, iv), R: zeroReader{}}, nil
+}
+
+// zeroReader is an endless stream of zeros, turned into the key stream by a
+// cipher.StreamReader
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
@@ -0,0 +1,56 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestDeterministic(t *testing.T) {
+	secret := randomBytes(32)
+	salt := randomBytes(16)
+	a, err := CreateShares(secret, 5, 3, WithDeterministic(salt, "vault"), WithRandomIndexes())
+	if err != nil {
+		failNow(t, err)
+	}
+	b, _ := CreateShares(secret, 5, 3, WithDeterministic(salt, "vault"), WithRandomIndexes())
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			failNow(t, fmt.Errorf("share %d differs with the same salt", i))
+		}
+	}
+	testRecover(t, secret, a[:3])
+	for _, opt := range []Option{WithDeterministic(randomBytes(16), "vault"), WithDeterministic(salt, "other")} {
+		c, _ := CreateShares(secret, 5, 3, opt, WithRandomIndexes())
+		if bytes.Equal(a[0], c[0]) {
+			failNow(t, fmt.Errorf("same shares with different derivation input"))
+		}
+	}
+	c, _ := CreateShares(secret, 5, 2, WithDeterministic(salt, "vault"))
+	d, _ := CreateShares(secret, 5, 3, WithDeterministic(salt, "vault"))
+	if bytes.Equal(c[0], d[0]) {
+		failNow(t, fmt.Errorf("same first share with different thresholds"))
+	}
+}
+
+func TestDeterministicChunked(t *testing.T) {
+	secret := append(make([]byte, ChunkBytes), make([]byte, ChunkBytes)...)
+	salt := randomBytes(16)
+	a, err := CreateChunkedShares(secret, 3, 2, WithDeterministic(salt, ""))
+	if err != nil {
+		failNow(t, err)
+	}
+	b, _ := CreateChunkedShares(secret, 3, 2, WithDeterministic(salt, ""))
+	da, _ := a[1].MarshalBinary()
+	db, _ := b[1].MarshalBinary()
+	if !bytes.Equal(da, db) {
+		failNow(t, fmt.Errorf("chunked shares differ with the same salt"))
+	}
+	if bytes.Equal(a[1].Chunks[0], a[1].Chunks[1]) {
+		failNow(t, fmt.Errorf("equal chunks got equal shares"))
+	}
+	recovered, err := RecoverChunkedSecret(a[:2])
+	if err != nil || !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("secret mismatch %v", err))
+	}
+}
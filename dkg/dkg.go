@@ -0,0 +1,341 @@
+// Package dkg generates a P-256 key shared among n parties without a trusted
+// dealer, following the joint Feldman protocol of Pedersen with the complaint
+// handling of Gennaro, Jarecki, Krawczyk and Rabin.
+//
+// Every party deals a Feldman split of a random scalar: it broadcasts the
+// Deal and sends every other party its PrivateShare over a private
+// authenticated channel. A party whose share does not match the commitments
+// broadcasts a Complaint, which the dealer answers by broadcasting the share
+// as a Justification. Dealers failing to justify are disqualified. The key is
+// the sum of the scalars of the qualified dealers, nobody ever holds it: the
+// parties end with tss.ScalarShare shares of it, threshold of them recover it
+// with tss.RecoverFeldman, or use it in threshold signing and decryption.
+//
+// Broadcast messages must reach every party alike, the package does not
+// provide the channels.
+package dkg
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	tss "github.com/antik10ud/go-tss"
+)
+
+var (
+	ErrWrongPhase       = fmt.Errorf("message out of phase: %w", tss.ErrValidation)
+	ErrUnknownParty     = fmt.Errorf("unknown party: %w", tss.ErrValidation)
+	ErrDuplicateMessage = fmt.Errorf("duplicate message: %w", tss.ErrValidation)
+	ErrMissingMessages  = fmt.Errorf("messages missing: %w", tss.ErrValidation)
+	ErrNotEnoughDealers = fmt.Errorf("not enough qualified dealers: %w", tss.ErrIntegrity)
+)
+
+// Deal is the broadcast message of a dealer, the Feldman commitments to its
+// polynomial
+type Deal struct {
+	Dealer      byte
+	Commitments tss.Commitments
+}
+
+// PrivateShare is the share a dealer sends privately to one party
+type PrivateShare struct {
+	Dealer    byte
+	Recipient byte
+	Share     tss.ScalarShare
+}
+
+// Complaint is broadcast by a party that got no valid share from a dealer
+type Complaint struct {
+	From    byte
+	Against byte
+}
+
+// Justification is the answer of a dealer to a complaint, the share of the
+// complaining party made public
+type Justification struct {
+	Dealer byte
+	Share  tss.ScalarShare
+}
+
+// Result is the outcome of the protocol for a party
+type Result struct {
+	// Share is the share of the key held by the party
+	Share tss.ScalarShare
+	// PublicKey is the compressed P-256 point of the key
+	PublicKey []byte
+	// Commitments are the commitments to the joint polynomial, every share
+	// of the key verifies against them
+	Commitments tss.Commitments
+	// Qualified are the indexes of the dealers whose scalar is in the key
+	Qualified []byte
+}
+
+type phase int
+
+const (
+	phaseDeal phase = iota
+	phaseComplaints
+	phaseJustifications
+	phaseDone
+)
+
+// Party runs the protocol for the party at index among the parties 1 to n
+type Party struct {
+	index     byte
+	n         int
+	threshold int
+	opts      []tss.Option
+	phase     phase
+	// dealt holds the shares of the own split, to answer complaints
+	dealt       []tss.ScalarShare
+	commitments map[byte]tss.Commitments
+	shares      map[byte]tss.ScalarShare
+	complaints  map[byte][]byte
+	justified   map[byte]map[byte]bool
+	// disqualified dealers, by index
+	disqualified map[byte]bool
+}
+
+// NewParty sets up the party at index, threshold of the n parties being
+// needed to use the key. WithRand sets the source of its random scalar.
+func NewParty(index byte, n int, threshold int, opts ...tss.Option) (*Party, error) {
+	if n < tss.MinShares || n > tss.MaxShares {
+		return nil, tss.ErrTooManyShares
+	}
+	if threshold < tss.MinThreshold || threshold > n {
+		return nil, tss.ErrInvalidThreshold
+	}
+	if index == 0 || int(index) > n {
+		return nil, ErrUnknownParty
+	}
+	return &Party{
+		index:        index,
+		n:            n,
+		threshold:    threshold,
+		opts:         opts,
+		commitments:  map[byte]tss.Commitments{},
+		shares:       map[byte]tss.ScalarShare{},
+		complaints:   map[byte][]byte{},
+		justified:    map[byte]map[byte]bool{},
+		disqualified: map[byte]bool{},
+	}, nil
+}
+
+func (p *Party) isParty(x byte) bool {
+	return x != 0 && int(x) <= p.n
+}
+
+// Deal draws the random scalar of the party and splits it: the Deal goes to
+// every party, each PrivateShare to its recipient, including the party's
+// own. The scalar itself is not kept.
+func (p *Party) Deal() (*Deal, []PrivateShare, error) {
+	if p.phase != phaseDeal || p.dealt != nil {
+		return nil, nil, ErrWrongPhase
+	}
+	scalar, err := rand.Int(rand.Reader, elliptic.P256().Params().N)
+	if err != nil {
+		return nil, nil, err
+	}
+	secret := scalar.FillBytes(make([]byte, tss.ScalarBytes))
+	defer tss.Wipe(secret)
+	shares, commitments, err := tss.SplitFeldman(secret, p.n, p.threshold, p.opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	p.dealt = shares
+	private := make([]PrivateShare, len(shares))
+	for i, s := range shares {
+		private[i] = PrivateShare{Dealer: p.index, Recipient: s.Index, Share: s}
+	}
+	return &Deal{Dealer: p.index, Commitments: commitments}, private, nil
+}
+
+// ReceiveDeal records the commitments of a dealer
+func (p *Party) ReceiveDeal(d *Deal) error {
+	if p.phase != phaseDeal {
+		return ErrWrongPhase
+	}
+	if !p.isParty(d.Dealer) {
+		return ErrUnknownParty
+	}
+	if _, ok := p.commitments[d.Dealer]; ok {
+		return ErrDuplicateMessage
+	}
+	p.commitments[d.Dealer] = d.Commitments
+	return nil
+}
+
+// ReceiveShare records the share a dealer sent to the party, it is checked
+// when the complaints are made
+func (p *Party) ReceiveShare(s PrivateShare) error {
+	if p.phase != phaseDeal {
+		return ErrWrongPhase
+	}
+	if !p.isParty(s.Dealer) || s.Recipient != p.index || s.Share.Index != p.index {
+		return ErrUnknownParty
+	}
+	if _, ok := p.shares[s.Dealer]; ok {
+		return ErrDuplicateMessage
+	}
+	p.shares[s.Dealer] = s.Share
+	return nil
+}
+
+// valid tells whether share matches the commitments of dealer, which must
+// have as many commitments as the threshold
+func (p *Party) valid(dealer byte, share tss.ScalarShare) bool {
+	c, ok := p.commitments[dealer]
+	return ok && len(c) == p.threshold && c.Verify(share) == nil
+}
+
+// Complaints ends the dealing phase and returns the complaints of the party,
+// to broadcast, against the dealers whose share is missing or wrong. Dealers
+// that did not broadcast a Deal are disqualified.
+func (p *Party) Complaints() ([]Complaint, error) {
+	if p.phase != phaseDeal {
+		return nil, ErrWrongPhase
+	}
+	p.phase = phaseComplaints
+	var complaints []Complaint
+	for dealer := byte(1); int(dealer) <= p.n; dealer++ {
+		c, ok := p.commitments[dealer]
+		if !ok || len(c) != p.threshold {
+			p.disqualified[dealer] = true
+			continue
+		}
+		if share, ok := p.shares[dealer]; !ok || !p.valid(dealer, share) {
+			delete(p.shares, dealer)
+			complaints = append(complaints, Complaint{From: p.index, Against: dealer})
+		}
+	}
+	return complaints, nil
+}
+
+// ReceiveComplaint records a complaint broadcast by any party, the own ones
+// included
+func (p *Party) ReceiveComplaint(c Complaint) error {
+	if p.phase != phaseComplaints {
+		return ErrWrongPhase
+	}
+	if !p.isParty(c.From) || !p.isParty(c.Against) {
+		return ErrUnknownParty
+	}
+	for _, from := range p.complaints[c.Against] {
+		if from == c.From {
+			return ErrDuplicateMessage
+		}
+	}
+	p.complaints[c.Against] = append(p.complaints[c.Against], c.From)
+	return nil
+}
+
+// Justifications ends the complaint phase and returns the answers of the
+// party to the complaints against it, to broadcast
+func (p *Party) Justifications() ([]Justification, error) {
+	if p.phase != phaseComplaints {
+		return nil, ErrWrongPhase
+	}
+	p.phase = phaseJustifications
+	var justifications []Justification
+	for _, from := range p.complaints[p.index] {
+		justifications = append(justifications, Justification{Dealer: p.index, Share: p.dealt[from-1]})
+	}
+	return justifications, nil
+}
+
+// ReceiveJustification checks the answer of a dealer to a complaint, a wrong
+// share disqualifying the dealer. A valid share for the party replaces the
+// one it complained about.
+func (p *Party) ReceiveJustification(j Justification) error {
+	if p.phase != phaseJustifications {
+		return ErrWrongPhase
+	}
+	if !p.isParty(j.Dealer) || !p.isParty(j.Share.Index) {
+		return ErrUnknownParty
+	}
+	complained := false
+	for _, from := range p.complaints[j.Dealer] {
+		complained = complained || from == j.Share.Index
+	}
+	if !complained {
+		return ErrUnknownParty
+	}
+	if p.justified[j.Dealer] == nil {
+		p.justified[j.Dealer] = map[byte]bool{}
+	}
+	if p.justified[j.Dealer][j.Share.Index] {
+		return ErrDuplicateMessage
+	}
+	p.justified[j.Dealer][j.Share.Index] = true
+	if !p.valid(j.Dealer, j.Share) {
+		p.disqualified[j.Dealer] = true
+		return nil
+	}
+	if j.Share.Index == p.index {
+		p.shares[j.Dealer] = j.Share
+	}
+	return nil
+}
+
+// Finish ends the protocol: dealers that left a complaint unanswered are
+// disqualified, and the share of the party is the sum of the shares of the
+// qualified dealers. Every honest party gets the same public key and
+// qualified set.
+func (p *Party) Finish() (*Result, error) {
+	if p.phase != phaseJustifications {
+		return nil, ErrWrongPhase
+	}
+	for dealer, froms := range p.complaints {
+		for _, from := range froms {
+			if !p.justified[dealer][from] {
+				p.disqualified[dealer] = true
+			}
+		}
+	}
+	var qualified []byte
+	for dealer := byte(1); int(dealer) <= p.n; dealer++ {
+		if !p.disqualified[dealer] {
+			qualified = append(qualified, dealer)
+		}
+	}
+	if len(qualified) < p.threshold {
+		return nil, ErrNotEnoughDealers
+	}
+	curve := elliptic.P256()
+	order := curve.Params().N
+	sum, v := new(big.Int), new(big.Int)
+	commitments := make([]struct{ x, y *big.Int }, p.threshold)
+	for _, dealer := range qualified {
+		share, ok := p.shares[dealer]
+		if !ok {
+			return nil, ErrMissingMessages
+		}
+		sum.Add(sum, v.SetBytes(share.Value))
+		for k, c := range p.commitments[dealer] {
+			x, y := elliptic.UnmarshalCompressed(curve, c)
+			if x == nil {
+				return nil, tss.ErrInvalidCommitment
+			}
+			if commitments[k].x == nil {
+				commitments[k].x, commitments[k].y = x, y
+				continue
+			}
+			commitments[k].x, commitments[k].y = curve.Add(commitments[k].x, commitments[k].y, x, y)
+		}
+	}
+	sum.Mod(sum, order)
+	result := &Result{
+		Share:       tss.ScalarShare{Index: p.index, Value: sum.FillBytes(make([]byte, tss.ScalarBytes))},
+		Commitments: make(tss.Commitments, p.threshold),
+		Qualified:   qualified,
+	}
+	for k, c := range commitments {
+		result.Commitments[k] = elliptic.MarshalCompressed(curve, c.x, c.y)
+	}
+	result.PublicKey = result.Commitments[0]
+	p.phase = phaseDone
+	p.dealt = nil
+	return result, nil
+}
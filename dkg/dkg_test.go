@@ -0,0 +1,147 @@
+package dkg
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"testing"
+
+	tss "github.com/antik10ud/go-tss"
+)
+
+// run plays the protocol among n parties, tamper may alter the private
+// shares before delivery and justify tells whether a dealer answers the
+// complaints against it
+func run(t *testing.T, n int, threshold int, tamper func(*PrivateShare), justify func(byte) bool) []*Result {
+	parties := make([]*Party, n)
+	for i := range parties {
+		var err error
+		if parties[i], err = NewParty(byte(i+1), n, threshold); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, dealer := range parties {
+		deal, shares, err := dealer.Deal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range parties {
+			if err := p.ReceiveDeal(deal); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, s := range shares {
+			tamper(&s)
+			if err := parties[s.Recipient-1].ReceiveShare(s); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	var complaints []Complaint
+	for _, p := range parties {
+		c, err := p.Complaints()
+		if err != nil {
+			t.Fatal(err)
+		}
+		complaints = append(complaints, c...)
+	}
+	for _, p := range parties {
+		for _, c := range complaints {
+			if err := p.ReceiveComplaint(c); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	var justifications []Justification
+	for _, p := range parties {
+		j, err := p.Justifications()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if justify(p.index) {
+			justifications = append(justifications, j...)
+		}
+	}
+	results := make([]*Result, n)
+	for i, p := range parties {
+		for _, j := range justifications {
+			if err := p.ReceiveJustification(j); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var err error
+		if results[i], err = p.Finish(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return results
+}
+
+// check verifies every party got a share of the same key
+func check(t *testing.T, results []*Result, threshold int, qualified int) {
+	for _, r := range results {
+		if !bytes.Equal(r.PublicKey, results[0].PublicKey) || len(r.Qualified) != qualified {
+			t.Fatalf("parties disagree: %x, %v", r.PublicKey, r.Qualified)
+		}
+		if err := results[0].Commitments.Verify(r.Share); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shares := make([]tss.ScalarShare, threshold)
+	for i := range shares {
+		shares[i] = results[len(results)-1-i].Share
+	}
+	key, err := tss.RecoverFeldman(shares, results[0].Commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	curve := elliptic.P256()
+	x, y := curve.ScalarBaseMult(key)
+	if !bytes.Equal(elliptic.MarshalCompressed(curve, x, y), results[0].PublicKey) {
+		t.Fatal("recovered key does not match the public key")
+	}
+}
+
+func TestDKG(t *testing.T) {
+	results := run(t, 5, 3, func(*PrivateShare) {}, func(byte) bool { return true })
+	check(t, results, 3, 5)
+}
+
+func TestDKGComplaints(t *testing.T) {
+	tamper := func(s *PrivateShare) {
+		if s.Dealer == 2 && s.Recipient == 4 || s.Dealer == 3 && s.Recipient == 1 {
+			s.Share.Value = append([]byte{}, s.Share.Value...)
+			s.Share.Value[31] ^= 1
+		}
+	}
+	// dealer 2 answers the complaint with the right share and stays
+	// qualified, dealer 3 does not answer and is disqualified
+	results := run(t, 5, 3, tamper, func(dealer byte) bool { return dealer != 3 })
+	check(t, results, 3, 4)
+	for _, r := range results {
+		for _, q := range r.Qualified {
+			if q == 3 {
+				t.Fatal("dealer 3 qualified")
+			}
+		}
+	}
+}
+
+func TestDKGErrors(t *testing.T) {
+	p, err := NewParty(1, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Finish(); err != ErrWrongPhase {
+		t.Fatal(err)
+	}
+	if _, err := NewParty(4, 3, 2); err != ErrUnknownParty {
+		t.Fatal(err)
+	}
+	deal, _, _ := p.Deal()
+	if err := p.ReceiveDeal(deal); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.ReceiveDeal(deal); err != ErrDuplicateMessage {
+		t.Fatal(err)
+	}
+}
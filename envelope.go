@@ -0,0 +1,97 @@
+package tss
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"io"
+)
+
+const (
+	// envelopeMagic starts the ciphertext of SplitEncrypt
+	envelopeMagic = "TSSENV"
+	// envelopeVersion is the version of the ciphertext of SplitEncrypt
+	envelopeVersion = 1
+	// KEKBytes is the size of the key encryption key split by SplitEncrypt
+	KEKBytes = 32
+)
+
+var (
+	ErrInvalidCiphertext = validationError("invalid ciphertext")
+	ErrDecryption        = integrityError("decryption failed")
+)
+
+// SplitEncrypt encrypts plaintext under a random key with AES-256-GCM and
+// splits only the key, so secrets of any size get shares of KEKBytes+1 bytes
+// and the ciphertext can be stored anywhere, it is useless without threshold
+// shares. The options apply to the split of the key, the key and the nonce
+// being drawn from the source of the options like the coefficients.
+func SplitEncrypt(plaintext []byte, sharesCount int, threshold int, opts ...Option) (ciphertext []byte, shares ShareSet, err error) {
+	cfg := newConfig(opts)
+	random, err := cfg.source(plaintext, threshold)
+	if err != nil {
+		return nil, nil, err
+	}
+	kek := make([]byte, KEKBytes)
+	defer erase(kek)
+	if _, err := io.ReadFull(random, kek); err != nil {
+		return nil, nil, err
+	}
+	aead, err := envelopeAEAD(kek)
+	if err != nil {
+		return nil, nil, err
+	}
+	header := append([]byte(envelopeMagic), envelopeVersion)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(random, nonce); err != nil {
+		return nil, nil, err
+	}
+	shares, err = CreateShares(kek, sharesCount, threshold, append(opts[:len(opts):len(opts)], WithConsumeSecret(false))...)
+	if err != nil {
+		return nil, nil, err
+	}
+	ciphertext = append(header, nonce...)
+	return aead.Seal(ciphertext, nonce, plaintext, header), shares, nil
+}
+
+// CombineDecrypt recovers the key of a SplitEncrypt from shares and decrypts
+// ciphertext with it. Shares of another split, or too few of them, give
+// ErrDecryption.
+func CombineDecrypt(ciphertext []byte, shares ShareSet, opts ...Option) ([]byte, error) {
+	header := append([]byte(envelopeMagic), envelopeVersion)
+	if !bytes.HasPrefix(ciphertext, []byte(envelopeMagic)) || len(ciphertext) < len(header) {
+		return nil, ErrInvalidCiphertext
+	}
+	if ciphertext[len(envelopeMagic)] != envelopeVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	kek, err := RecoverSecret(shares, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer erase(kek)
+	if len(kek) != KEKBytes {
+		return nil, ErrDecryption
+	}
+	aead, err := envelopeAEAD(kek)
+	if err != nil {
+		return nil, err
+	}
+	body := ciphertext[len(header):]
+	if len(body) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrInvalidCiphertext
+	}
+	plaintext, err := aead.Open(nil, body[:aead.NonceSize()], body[aead.NonceSize():], header)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	return plaintext, nil
+}
+
+func envelopeAEAD(kek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,54 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSplitEncrypt(t *testing.T) {
+	plaintext := randomBytes(100000)
+	ciphertext, shares, err := SplitEncrypt(plaintext, 5, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	if len(shares[0]) != KEKBytes+1 {
+		failNow(t, fmt.Errorf("share size %d", len(shares[0])))
+	}
+	recovered, err := CombineDecrypt(ciphertext, shares.Subset(4, 0, 2))
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, plaintext) {
+		failNow(t, fmt.Errorf("plaintext mismatch"))
+	}
+	if _, err := CombineDecrypt(ciphertext, shares[:2]); err != ErrDecryption {
+		failNow(t, expected(ErrDecryption, err))
+	}
+	_, other, _ := SplitEncrypt(plaintext, 5, 3)
+	if _, err := CombineDecrypt(ciphertext, other); err != ErrDecryption {
+		failNow(t, expected(ErrDecryption, err))
+	}
+	ciphertext[len(ciphertext)-1] ^= 1
+	if _, err := CombineDecrypt(ciphertext, shares); !errors.Is(err, ErrIntegrity) {
+		failNow(t, expected(ErrDecryption, err))
+	}
+	if _, err := CombineDecrypt(ciphertext[:5], shares); err != ErrInvalidCiphertext {
+		failNow(t, expected(ErrInvalidCiphertext, err))
+	}
+}
+
+func TestSplitEncryptOptions(t *testing.T) {
+	opts := make([]Option, 1, 2)
+	opts[0] = WithConsumeSecret(true)
+	if _, _, err := SplitEncrypt(randomBytes(10), 3, 2, opts...); err != nil {
+		failNow(t, err)
+	}
+	if opts[:2][1] != nil {
+		failNow(t, fmt.Errorf("options of the caller overwritten"))
+	}
+	if _, _, err := SplitEncrypt(randomBytes(10), 3, 2, WithRand(bytes.NewReader(randomBytes(100))), WithStrictMode()); err != ErrNotAllowed {
+		failNow(t, expected(ErrNotAllowed, err))
+	}
+}
@@ -0,0 +1,38 @@
+package tss
+
+import "errors"
+
+// The error categories, every error value of the package matches one of them
+// with errors.Is
+var (
+	ErrValidation = errors.New("validation failed")
+	ErrIntegrity  = errors.New("integrity check failed")
+	ErrLimits     = errors.New("limit exceeded")
+)
+
+// kindError is an error value belonging to a category, it keeps its own
+// message and unwraps to the category
+type kindError struct {
+	msg  string
+	kind error
+}
+
+func (e *kindError) Error() string {
+	return e.msg
+}
+
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
+func validationError(msg string) error {
+	return &kindError{msg: msg, kind: ErrValidation}
+}
+
+func integrityError(msg string) error {
+	return &kindError{msg: msg, kind: ErrIntegrity}
+}
+
+func limitsError(msg string) error {
+	return &kindError{msg: msg, kind: ErrLimits}
+}
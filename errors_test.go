@@ -0,0 +1,41 @@
+package tss
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorCategories(t *testing.T) {
+	for _, c := range []struct {
+		err  error
+		kind error
+	}{
+		{ErrSecretRequired, ErrValidation},
+		{ErrInvalidShare, ErrValidation},
+		{ErrMissingChunk, ErrValidation},
+		{ErrSecretTooLarge, ErrLimits},
+		{ErrTooManyShares, ErrLimits},
+		{ErrChecksum, ErrIntegrity},
+		{ErrCorruptContainer, ErrIntegrity},
+		{&ShareError{Position: 2, Index: 2, Reason: ErrDuplicateShare}, ErrValidation},
+		{&ChecksumError{Share: 1, Offset: 16}, ErrIntegrity},
+		{&PaperLineError{Line: 3}, ErrIntegrity},
+	} {
+		if !errors.Is(c.err, c.kind) {
+			failNow(t, fmt.Errorf("%v is not %v", c.err, c.kind))
+		}
+	}
+	if ErrSecretTooLarge.Error() != "secret too large" {
+		failNow(t, fmt.Errorf("unexpected message %q", ErrSecretTooLarge))
+	}
+}
+
+func TestShareErrorAs(t *testing.T) {
+	shares, _ := CreateShares(randomBytes(32), 3, 2)
+	_, err := RecoverSecret(ShareSet{shares[0], shares[0]})
+	var se *ShareError
+	if !errors.As(err, &se) || !errors.Is(err, ErrValidation) || se.Position != 2 {
+		failNow(t, expected(ErrDuplicateShare, err))
+	}
+}
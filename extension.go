@@ -0,0 +1,79 @@
+package tss
+
+// Extension types defined by the package, types from ExtPrivate up are free
+// for caller use
+const (
+	// ExtCustodian names the custodian the share was handed to
+	ExtCustodian byte = 1
+	// ExtPurpose describes what the secret is used for
+	ExtPurpose byte = 2
+	// ExtPolicyURI points to the policy governing the secret
+	ExtPolicyURI byte = 3
+	// ExtDealerSignature holds the Ed25519 signature of the dealer over the
+	// share header, see SignShare
+	ExtDealerSignature byte = 4
+	// ExtPrivate is the first type available to callers
+	ExtPrivate byte = 0x80
+)
+
+// maxExtensionsBytes keeps the encoded extensions and the rest of the
+// container header within its 2 bytes length
+const maxExtensionsBytes = 0xffff - 2 - MaxIdentifierBytes
+
+var (
+	ErrInvalidExtension = validationError("invalid extension")
+)
+
+// Extension is a type-length-value metadata field carried in the share
+// header. It is covered by the container digest, and signatures of the header,
+// but plays no part in reconstruction.
+type Extension struct {
+	Type  byte
+	Value []byte
+}
+
+// Extension returns the value of the first extension of type typ
+func (p ShareParams) Extension(typ byte) ([]byte, bool) {
+	for _, e := range p.Extensions {
+		if e.Type == typ {
+			return e.Value, true
+		}
+	}
+	return nil, false
+}
+
+func validateExtensions(exts []Extension) error {
+	n := 0
+	for _, e := range exts {
+		n += 3 + len(e.Value)
+		if len(e.Value) > 0xffff || n > maxExtensionsBytes {
+			return ErrInvalidExtension
+		}
+	}
+	return nil
+}
+
+// appendExtensions encodes exts as type (1 byte), big endian length (2 bytes), value
+func appendExtensions(b []byte, exts []Extension) []byte {
+	for _, e := range exts {
+		b = append(b, e.Type, byte(len(e.Value)>>8), byte(len(e.Value)))
+		b = append(b, e.Value...)
+	}
+	return b
+}
+
+// parseExtensions decodes the extensions written by appendExtensions, p must
+// hold nothing else
+func parseExtensions(p []byte) ([]Extension, error) {
+	var exts []Extension
+	for len(p) > 0 {
+		typ := p[0]
+		value, rest, ok := cut16(p[1:])
+		if !ok {
+			return nil, ErrInvalidExtension
+		}
+		exts = append(exts, Extension{Type: typ, Value: append([]byte{}, value...)})
+		p = rest
+	}
+	return exts, nil
+}
@@ -0,0 +1,129 @@
+package tss
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+var (
+	ErrInvalidCommitment = validationError("invalid commitment")
+	ErrShareNotCommitted = integrityError("share does not match the commitments")
+)
+
+// Commitments are the public commitments of a verifiable split, one
+// compressed P-256 point per coefficient of the polynomial. Their number is
+// the threshold.
+type Commitments [][]byte
+
+// SplitFeldman splits a P-256 scalar, 32 bytes big endian, with Feldman's
+// verifiable scheme: besides the shares, it returns the commitments a_k*G to
+// the coefficients of the polynomial, which anyone may hold. A custodian
+// checks its share against them with Verify, so a dealer handing out
+// inconsistent shares is caught. The first commitment is secret*G, the
+// public key of the scalar: the secret must have full entropy, a guessable
+// one is revealed by the commitments.
+func SplitFeldman(secret []byte, sharesCount int, threshold int, opts ...Option) ([]ScalarShare, Commitments, error) {
+	cfg := newConfig(opts)
+	s, err := P256.decode(secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer eraseInt(s)
+	if sharesCount < MinShares {
+		return nil, nil, ErrTooFewShares
+	}
+	if sharesCount > cfg.sharesLimit() {
+		return nil, nil, ErrTooManyShares
+	}
+	if threshold > sharesCount || threshold < cfg.minThreshold() {
+		return nil, nil, ErrInvalidThreshold
+	}
+	random, err := cfg.source(secret, threshold)
+	if err != nil {
+		return nil, nil, err
+	}
+	indexes, err := shareIndexes(random, sharesCount, cfg.randomIndexes)
+	if err != nil {
+		return nil, nil, err
+	}
+	curve := elliptic.P256()
+	order := curve.Params().N
+	a, err := primePolynomial(s, order, threshold, random)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer eraseInts(a)
+	commitments := make(Commitments, threshold)
+	for k, c := range a {
+		scalar := P256.encode(c)
+		x, y := curve.ScalarBaseMult(scalar)
+		commitments[k] = elliptic.MarshalCompressed(curve, x, y)
+		erase(scalar)
+	}
+	shares := make([]ScalarShare, sharesCount)
+	for i, x := range indexes {
+		y := primeEval(big.NewInt(int64(x)), a, order)
+		shares[i] = ScalarShare{Index: x, Value: P256.encode(y)}
+		eraseInt(y)
+	}
+	return shares, commitments, nil
+}
+
+// Verify checks share lies on the committed polynomial: share*G must equal
+// the sum of index^k times the k-th commitment
+func (c Commitments) Verify(share ScalarShare) error {
+	if share.Index == 0 {
+		return ErrInvalidShareIndex
+	}
+	value, err := P256.decode(share.Value)
+	if err != nil {
+		return err
+	}
+	eraseInt(value)
+	x, y, err := c.evalAt(share.Index)
+	if err != nil {
+		return err
+	}
+	sx, sy := elliptic.P256().ScalarBaseMult(share.Value)
+	if sx.Cmp(x) != 0 || sy.Cmp(y) != 0 {
+		return ErrShareNotCommitted
+	}
+	return nil
+}
+
+// evalAt returns the sum of index^k times the k-th commitment, computed with
+// Horner's rule in the group
+func (c Commitments) evalAt(index byte) (*big.Int, *big.Int, error) {
+	if len(c) < MinThreshold || len(c) > MaxShares {
+		return nil, nil, ErrInvalidCommitment
+	}
+	curve := elliptic.P256()
+	var x, y *big.Int
+	for k := len(c) - 1; k >= 0; k-- {
+		cx, cy := elliptic.UnmarshalCompressed(curve, c[k])
+		if cx == nil {
+			return nil, nil, ErrInvalidCommitment
+		}
+		if x == nil {
+			x, y = cx, cy
+			continue
+		}
+		x, y = curve.ScalarMult(x, y, []byte{index})
+		x, y = curve.Add(x, y, cx, cy)
+	}
+	return x, y, nil
+}
+
+// RecoverFeldman recovers a scalar split by SplitFeldman, checking every share
+// against the commitments first, so a custodian handing in a wrong share is
+// caught with a *ShareError matching ErrShareNotCommitted instead of
+// corrupting the secret
+func RecoverFeldman(shares []ScalarShare, commitments Commitments, opts ...Option) ([]byte, error) {
+	opts = append([]Option{WithThreshold(len(commitments))}, opts...)
+	for i, s := range shares {
+		if err := commitments.Verify(s); err != nil {
+			return nil, &ShareError{Position: i + 1, Index: s.Index, Reason: err}
+		}
+	}
+	return RecoverScalar(P256, shares, opts...)
+}
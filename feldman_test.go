@@ -0,0 +1,54 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestFeldman(t *testing.T) {
+	secret := P256.encode(new(big.Int).SetBytes(randomBytes(31)))
+	shares, commitments, err := SplitFeldman(secret, 5, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	if len(commitments) != 3 {
+		failNow(t, fmt.Errorf("%d commitments, want 3", len(commitments)))
+	}
+	for _, s := range shares {
+		if err := commitments.Verify(s); err != nil {
+			failNow(t, err)
+		}
+	}
+	recovered, err := RecoverFeldman([]ScalarShare{shares[4], shares[1], shares[2]}, commitments)
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+	if _, err := RecoverFeldman(shares[:2], commitments); !errors.Is(err, ErrThresholdNotMet) {
+		failNow(t, expected(ErrThresholdNotMet, err))
+	}
+}
+
+func TestFeldmanCheating(t *testing.T) {
+	secret := P256.encode(new(big.Int).SetBytes(randomBytes(31)))
+	shares, commitments, _ := SplitFeldman(secret, 5, 3)
+	forged := ScalarShare{Index: shares[1].Index, Value: append([]byte{}, shares[1].Value...)}
+	forged.Value[31] ^= 1
+	if err := commitments.Verify(forged); err != ErrShareNotCommitted {
+		failNow(t, expected(ErrShareNotCommitted, err))
+	}
+	_, err := RecoverFeldman([]ScalarShare{shares[0], forged, shares[2]}, commitments)
+	var shareErr *ShareError
+	if !errors.As(err, &shareErr) || shareErr.Position != 2 || !errors.Is(err, ErrShareNotCommitted) {
+		failNow(t, expected(ErrShareNotCommitted, err))
+	}
+	commitments[0] = commitments[0][1:]
+	if err := commitments.Verify(shares[0]); err != ErrInvalidCommitment {
+		failNow(t, expected(ErrInvalidCommitment, err))
+	}
+}
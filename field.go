@@ -0,0 +1,334 @@
+package tss
+
+import (
+	"crypto/subtle"
+	"io"
+)
+
+var (
+	ErrDivisionByZero = validationError("division by zero")
+)
+
+// Field is the arithmetic shares are computed in. Elements are ElementBytes
+// bytes, big endian, and vectors are elements one after the other; the
+// operations apply element by element and dst may alias their inputs.
+// Indexes of shares are the elements encoding the integers 1, 2 and so on.
+type Field interface {
+	// ElementBytes returns the size of an element
+	ElementBytes() int
+	// Add sets dst to a+b
+	Add(dst []byte, a []byte, b []byte)
+	// Mul sets dst to a*b
+	Mul(dst []byte, a []byte, b []byte)
+	// Div sets dst to a/b, or fails with ErrDivisionByZero
+	Div(dst []byte, a []byte, b []byte) error
+	// Eval sets dst to the polynomials of the given coefficient vectors,
+	// lowest degree first, evaluated at the element x
+	Eval(dst []byte, x []byte, coefficients [][]byte)
+	// Interp sets dst to the polynomials going through the vectors ys at the
+	// distinct elements of the vector xs, evaluated at the element x
+	Interp(dst []byte, x []byte, xs []byte, ys [][]byte) error
+	// Random fills dst with uniformly random elements read from random
+	Random(dst []byte, random io.Reader) error
+}
+
+// PartialField is a Field whose elements are not every byte string of
+// ElementBytes bytes, such as the fields of NewPrimeField. CreateFieldShares
+// spreads the secret PayloadBytes bytes per element, in its low bytes, and
+// checks the elements it makes with Valid.
+type PartialField interface {
+	Field
+	// PayloadBytes returns the bytes of secret an element carries
+	PayloadBytes() int
+	// Valid tells whether every element of the vector v is an element
+	Valid(v []byte) bool
+}
+
+// GF256 is GF(2^8) with the polynomial of AES, the field of Share and of
+// CreateShares and RecoverSecret
+var GF256 Field = gf256{}
+
+// gf256 is GF256, hardened selecting the table scans of WithHardenedLookups.
+// The vector operations go through mulAdd, so the vector kernels apply.
+type gf256 struct {
+	hardened bool
+}
+
+// field returns the GF256 implementation of the options
+func (c *config) field() gf256 {
+	return gf256{hardened: c.hardened}
+}
+
+func (gf256) ElementBytes() int {
+	return 1
+}
+
+func (gf256) Add(dst []byte, a []byte, b []byte) {
+	subtle.XORBytes(dst[:len(a)], a, b)
+}
+
+func (gf256) Mul(dst []byte, a []byte, b []byte) {
+	for i := range a {
+		dst[i] = mul(a[i], b[i])
+	}
+}
+
+func (gf256) Div(dst []byte, a []byte, b []byte) error {
+	for i := range b {
+		if b[i] == 0 {
+			return ErrDivisionByZero
+		}
+	}
+	for i := range a {
+		dst[i] = div(a[i], b[i])
+	}
+	return nil
+}
+
+func (f gf256) Eval(dst []byte, x []byte, coefficients [][]byte) {
+	copy(dst, coefficients[0])
+	var xk byte = 1
+	for _, c := range coefficients[1:] {
+		xk = mul(xk, x[0])
+		mulAdd(xk, c, dst, f.hardened)
+	}
+}
+
+func (f gf256) Interp(dst []byte, x []byte, xs []byte, ys [][]byte) error {
+	var seen [256]bool
+	for _, u := range xs {
+		if seen[u] {
+			return ErrDivisionByZero
+		}
+		seen[u] = true
+	}
+	var wa [256]byte
+	w := wa[:len(xs)]
+	f.weights(w, xs, x[0])
+	f.combine(dst, w, ys)
+	return nil
+}
+
+// weights sets w to the Lagrange weights at x of the distinct indexes xs
+func (gf256) weights(w []byte, xs []byte, x byte) {
+	for i := range xs {
+		w[i] = polyAt(i, xs, x)
+	}
+}
+
+// combine sets dst to the sum of the vectors ys scaled by the weights w
+func (f gf256) combine(dst []byte, w []byte, ys [][]byte) {
+	fill(dst, 0)
+	for i, y := range ys {
+		mulAdd(w[i], y, dst, f.hardened)
+	}
+}
+
+func (gf256) Random(dst []byte, random io.Reader) error {
+	_, err := io.ReadFull(random, dst)
+	return err
+}
+
+// fieldSharesLimit is the largest number of shares over field, the indexes
+// having to fit in an element
+func fieldSharesLimit(field Field, cfg *config) int {
+	limit := MaxWideShares
+	if bits := 8 * field.ElementBytes(); bits < 16 {
+		limit = 1<<bits - 1
+	}
+	if cfg.maxShares > 0 && cfg.maxShares < limit {
+		limit = cfg.maxShares
+	}
+	return limit
+}
+
+// CreateFieldShares is CreateShares over any field: every share is its index
+// followed by the value of the polynomials at it, one element per element of
+// the secret. Fields with elements of several bytes get the secret padded,
+// 0x80 then zeros, to a whole number of elements; prime fields carry the
+// secret in the low bytes of each element only, so elements stay below the
+// prime, as does any PartialField.
+func CreateFieldShares(field Field, secret []byte, sharesCount int, threshold int, opts ...Option) ([][]byte, error) {
+	cfg := newConfig(opts)
+	if len(secret) == 0 {
+		return nil, ErrSecretRequired
+	}
+	if len(secret) < cfg.minSecretBytes {
+		return nil, ErrSecretTooShort
+	}
+	if len(secret) > cfg.secretLimit() {
+		return nil, ErrSecretTooLarge
+	}
+	if sharesCount < MinShares {
+		return nil, ErrTooFewShares
+	}
+	if sharesCount > fieldSharesLimit(field, cfg) {
+		return nil, ErrTooManyShares
+	}
+	if threshold > sharesCount || threshold < cfg.minThreshold() {
+		return nil, ErrInvalidThreshold
+	}
+	e := field.ElementBytes()
+	padded := padElements(secret, e, elementPayload(field))
+	defer erase(padded)
+	if p, ok := field.(PartialField); ok && !p.Valid(padded) {
+		return nil, ErrSecretTooLarge
+	}
+
+	random, err := cfg.source(secret, threshold)
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := fieldIndexes(field, random, sharesCount, cfg.randomIndexes)
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := field.(PartialField); ok && !p.Valid(indexes) {
+		return nil, ErrTooManyShares
+	}
+	coefficients := make([][]byte, threshold)
+	coefficients[0] = padded
+	for k := 1; k < threshold; k++ {
+		coefficients[k] = make([]byte, len(padded))
+		defer erase(coefficients[k])
+		if err := field.Random(coefficients[k], random); err != nil {
+			return nil, err
+		}
+	}
+	shares := make([][]byte, sharesCount)
+	for j := range shares {
+		shares[j] = make([]byte, e+len(padded))
+		x := shares[j][:e]
+		copy(x, indexes[j*e:(j+1)*e])
+		field.Eval(shares[j][e:], x, coefficients)
+	}
+	return shares, nil
+}
+
+// fieldIndexes returns n indexes, the elements 1 to n or random distinct non
+// zero elements
+func fieldIndexes(field Field, random io.Reader, n int, randomIndexes bool) ([]byte, error) {
+	e := field.ElementBytes()
+	indexes := make([]byte, n*e)
+	if !randomIndexes {
+		for i := 0; i < n; i++ {
+			for b, v := 0, i+1; b < e && v > 0; b, v = b+1, v>>8 {
+				indexes[(i+1)*e-1-b] = byte(v)
+			}
+		}
+		return indexes, nil
+	}
+	seen := make(map[string]bool, n)
+	for i := 0; i < n; {
+		x := indexes[i*e : (i+1)*e]
+		if err := field.Random(x, random); err != nil {
+			return nil, err
+		}
+		if allZero(x) || seen[string(x)] {
+			continue
+		}
+		seen[string(x)] = true
+		i++
+	}
+	return indexes, nil
+}
+
+// RecoverFieldSecret recovers a secret split by CreateFieldShares over the
+// same field
+func RecoverFieldSecret(field Field, shares [][]byte, opts ...Option) ([]byte, error) {
+	cfg := newConfig(opts)
+	n := len(shares)
+	if n < cfg.threshold {
+		return nil, &ThresholdError{Need: cfg.threshold, Have: n}
+	}
+	if n < cfg.minThreshold() {
+		return nil, ErrTooFewShares
+	}
+	if n > fieldSharesLimit(field, cfg) {
+		return nil, ErrTooManyShares
+	}
+	e := field.ElementBytes()
+	payload := elementPayload(field)
+	size := len(shares[0])
+	if size < 2*e || size%e != 0 || (size-e)/e > cfg.secretLimit()/payload+1 {
+		return nil, &ShareError{Position: 1, Reason: ErrShareSize}
+	}
+	xs := make([]byte, n*e)
+	ys := make([][]byte, n)
+	seen := make(map[string]bool, n)
+	for i, s := range shares {
+		if len(s) != size {
+			return nil, &ShareError{Position: i + 1, Reason: ErrShareSize}
+		}
+		if allZero(s[:e]) {
+			return nil, &ShareError{Position: i + 1, Reason: ErrInvalidShareIndex}
+		}
+		if seen[string(s[:e])] {
+			return nil, &ShareError{Position: i + 1, Reason: ErrDuplicateShare}
+		}
+		seen[string(s[:e])] = true
+		copy(xs[i*e:], s[:e])
+		ys[i] = s[e:]
+	}
+	padded := make([]byte, size-e)
+	if err := field.Interp(padded, make([]byte, e), xs, ys); err != nil {
+		return nil, err
+	}
+	if e == 1 {
+		return padded, nil
+	}
+	return unpadElements(padded, e, payload)
+}
+
+// elementPayload returns the bytes of secret an element of field carries,
+// fewer than ElementBytes for a PartialField
+func elementPayload(field Field) int {
+	if p, ok := field.(PartialField); ok {
+		return p.PayloadBytes()
+	}
+	return field.ElementBytes()
+}
+
+// padElements returns secret padded, 0x80 then zeros, to a multiple of
+// payload bytes, spread payload bytes per element in the low bytes of
+// elements of e bytes. It is a copy of secret when e is 1.
+func padElements(secret []byte, e int, payload int) []byte {
+	if e == 1 {
+		return append([]byte{}, secret...)
+	}
+	n := len(secret)/payload + 1
+	padded := make([]byte, n*e)
+	for i := 0; i < n; i++ {
+		copy(padded[i*e+e-payload:(i+1)*e], secret[min(i*payload, len(secret)):min((i+1)*payload, len(secret))])
+	}
+	padded[len(secret)/payload*e+e-payload+len(secret)%payload] = 0x80
+	return padded
+}
+
+// unpadElements gathers the payload bytes of the elements of padded and
+// strips the padding of padElements, erasing padded
+func unpadElements(padded []byte, e int, payload int) ([]byte, error) {
+	defer erase(padded)
+	data := make([]byte, 0, len(padded)/e*payload)
+	for i := 0; i < len(padded); i += e {
+		if !allZero(padded[i : i+e-payload]) {
+			erase(data)
+			return nil, ErrInvalidShare
+		}
+		data = append(data, padded[i+e-payload:i+e]...)
+	}
+	return unpad(data)
+}
+
+// unpad strips the padding of padElements, erasing padded when it is invalid
+func unpad(padded []byte) ([]byte, error) {
+	end := len(padded) - 1
+	for end >= 0 && padded[end] == 0 {
+		end--
+	}
+	if end < 0 || padded[end] != 0x80 {
+		erase(padded)
+		return nil, ErrInvalidShare
+	}
+	return padded[:end], nil
+}
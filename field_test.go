@@ -0,0 +1,72 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestFieldArithmetic(t *testing.T) {
+	for _, field := range []Field{GF256, GF65536} {
+		a, b := randomBytes(64), randomBytes(64)
+		for i := range b {
+			b[i] |= 1
+		}
+		product, quotient, sum := make([]byte, 64), make([]byte, 64), make([]byte, 64)
+		field.Mul(product, a, b)
+		if err := field.Div(quotient, product, b); err != nil {
+			failNow(t, err)
+		}
+		if !bytes.Equal(quotient, a) {
+			failNow(t, fmt.Errorf("%d bytes field: a*b/b != a", field.ElementBytes()))
+		}
+		field.Add(sum, a, b)
+		field.Add(sum, sum, b)
+		if !bytes.Equal(sum, a) {
+			failNow(t, fmt.Errorf("%d bytes field: a+b+b != a", field.ElementBytes()))
+		}
+		if err := field.Div(quotient, a, make([]byte, 64)); err != ErrDivisionByZero {
+			failNow(t, expected(ErrDivisionByZero, err))
+		}
+	}
+}
+
+func TestFieldInterp(t *testing.T) {
+	secret := randomBytes(32)
+	shares, err := CreateShares(secret, 5, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	xs := []byte{shares[4][0], shares[1][0], shares[2][0]}
+	ys := [][]byte{shares[4][1:], shares[1][1:], shares[2][1:]}
+	recovered := make([]byte, 32)
+	if err := GF256.Interp(recovered, []byte{0}, xs, ys); err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+	if err := GF256.Interp(recovered, []byte{0}, []byte{1, 1}, ys[:2]); err != ErrDivisionByZero {
+		failNow(t, expected(ErrDivisionByZero, err))
+	}
+}
+
+func TestFieldShares(t *testing.T) {
+	for _, field := range []Field{GF256, GF65536} {
+		secret := randomBytes(33)
+		shares, err := CreateFieldShares(field, secret, 6, 4, WithRandomIndexes())
+		if err != nil {
+			failNow(t, err)
+		}
+		recovered, err := RecoverFieldSecret(field, shares[2:])
+		if err != nil {
+			failNow(t, err)
+		}
+		if !bytes.Equal(recovered, secret) {
+			failNow(t, fmt.Errorf("%d bytes field: secret mismatch", field.ElementBytes()))
+		}
+	}
+	if _, err := CreateFieldShares(GF256, randomBytes(32), 256, 3); err != ErrTooManyShares {
+		failNow(t, expected(ErrTooManyShares, err))
+	}
+}
@@ -0,0 +1,198 @@
+package tss
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// ShareFileMode is the permission of the files written by SplitFile and
+// RecoverFile
+const ShareFileMode = 0600
+
+var (
+	ErrShareFileDigest = integrityError("share file does not match the manifest")
+)
+
+// FileManifest is written by SplitFile next to the share files, it lists them
+// so custodians can check the file they hold and RecoverFile can find them
+type FileManifest struct {
+	// Name is the base name of the split file
+	Name string `json:"name"`
+	// Size is the size of the split file
+	Size int64 `json:"size"`
+	// Threshold is the number of share files required to recover the file
+	Threshold int `json:"threshold"`
+	// Shares lists the share files
+	Shares []ShareFile `json:"shares"`
+}
+
+// ShareFile is the entry of a share file in a FileManifest
+type ShareFile struct {
+	// Index is the share index of the custodian holding the file, 0 for a
+	// blob of a DirStore
+	Index byte `json:"index"`
+	// Path is the name of the share file, relative to the manifest
+	Path string `json:"path"`
+	// SHA256 is the hex digest of the share file
+	SHA256 string `json:"sha256"`
+}
+
+// SplitFile splits the file at src into share files written to dir, one per
+// custodian, and a manifest listing them. The share files hold a ChunkedShare
+// in binary form and are named after src and the share index. Every file is
+// written with ShareFileMode through a temporary file renamed in place, so a
+// crash never leaves a truncated share. It returns the path of the manifest.
+func SplitFile(src string, dir string, sharesCount int, threshold int, opts ...Option) (string, error) {
+	secret, err := os.ReadFile(src)
+	if err != nil {
+		return "", err
+	}
+	defer erase(secret)
+	shares, err := CreateChunkedShares(secret, sharesCount, threshold, opts...)
+	if err != nil {
+		return "", err
+	}
+	name := filepath.Base(src)
+	m := FileManifest{Name: name, Size: int64(len(secret)), Threshold: threshold}
+	for _, s := range shares {
+		data, err := s.MarshalBinary()
+		if err != nil {
+			return "", err
+		}
+		path := fmt.Sprintf("%s.%03d.share", name, s.Manifest.Index)
+		err = writeFileAtomic(filepath.Join(dir, path), data)
+		digest := sha256.Sum256(data)
+		erase(data)
+		if err != nil {
+			return "", err
+		}
+		m.Shares = append(m.Shares, ShareFile{Index: s.Manifest.Index, Path: path, SHA256: hex.EncodeToString(digest[:])})
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	manifest := filepath.Join(dir, name+".manifest")
+	if err := writeFileAtomic(manifest, append(data, '\n')); err != nil {
+		return "", err
+	}
+	return manifest, nil
+}
+
+// RecoverFile recovers a file split by SplitFile into dst, from the share
+// files listed in the manifest that are found next to it. Missing share files
+// are skipped, a share file not matching its digest is an error.
+func RecoverFile(manifest string, dst string, opts ...Option) error {
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		return err
+	}
+	var m FileManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return ErrInvalidShare
+	}
+	dir := filepath.Dir(manifest)
+	var shares []ChunkedShare
+	for _, sf := range m.Shares {
+		if filepath.Base(sf.Path) != sf.Path {
+			return ErrInvalidShare
+		}
+		data, err := os.ReadFile(filepath.Join(dir, sf.Path))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		digest := sha256.Sum256(data)
+		if hex.EncodeToString(digest[:]) != sf.SHA256 {
+			erase(data)
+			return fmt.Errorf("%s: %w", sf.Path, ErrShareFileDigest)
+		}
+		var s ChunkedShare
+		err = s.UnmarshalBinary(data)
+		erase(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", sf.Path, err)
+		}
+		shares = append(shares, s)
+	}
+	if len(shares) < m.Threshold {
+		return &ThresholdError{Need: m.Threshold, Have: len(shares)}
+	}
+	secret, err := RecoverChunkedSecret(shares, opts...)
+	for _, s := range shares {
+		for _, chunk := range s.Chunks {
+			erase(chunk)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	defer erase(secret)
+	if int64(len(secret)) != m.Size {
+		return ErrManifestMismatch
+	}
+	return writeFileAtomic(dst, secret)
+}
+
+// writeFileAtomic writes data to a temporary file next to path, synced, then
+// renames it to path and syncs the directory, so path holds either the old
+// or the new content after a crash
+func writeFileAtomic(path string, data []byte) error {
+	dir, prefix := filepath.Dir(path), "."+filepath.Base(path)+".tmp"
+	f, linked, err := openTemp(dir, prefix)
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if linked {
+		defer os.Remove(tmp)
+	}
+	if err := f.Chmod(ShareFileMode); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if !linked {
+		if tmp, err = linkTemp(f, dir, prefix); err != nil {
+			f.Close()
+			return err
+		}
+		defer os.Remove(tmp)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir syncs the directory dir so the renames in it survive a crash,
+// Windows has no directory sync
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	d.Close()
+	return err
+}
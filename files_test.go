@@ -0,0 +1,70 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSplitFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "key.pem")
+	secret := randomBytes(ChunkBytes + 100)
+	if err := os.WriteFile(src, secret, 0600); err != nil {
+		failNow(t, err)
+	}
+	out := filepath.Join(dir, "out")
+	if err := os.Mkdir(out, 0700); err != nil {
+		failNow(t, err)
+	}
+	manifest, err := SplitFile(src, out, 5, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	files, _ := filepath.Glob(filepath.Join(out, "*"))
+	if len(files) != 6 {
+		failNow(t, fmt.Errorf("unexpected files %v", files))
+	}
+	for _, f := range files {
+		info, _ := os.Stat(f)
+		if info.Mode().Perm() != ShareFileMode {
+			failNow(t, fmt.Errorf("%s has mode %v", f, info.Mode()))
+		}
+	}
+	// two custodians did not show up
+	os.Remove(filepath.Join(out, "key.pem.002.share"))
+	os.Remove(filepath.Join(out, "key.pem.005.share"))
+	dst := filepath.Join(dir, "recovered.pem")
+	if err := RecoverFile(manifest, dst); err != nil {
+		failNow(t, err)
+	}
+	got, _ := os.ReadFile(dst)
+	if !bytes.Equal(got, secret) {
+		failNow(t, fmt.Errorf("file mismatch"))
+	}
+
+	os.Remove(filepath.Join(out, "key.pem.001.share"))
+	if err := RecoverFile(manifest, dst); !errors.Is(err, ErrThresholdNotMet) {
+		failNow(t, expected(ErrThresholdNotMet, err))
+	}
+}
+
+func TestRecoverFileDigest(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "secret")
+	os.WriteFile(src, randomBytes(100), 0600)
+	manifest, err := SplitFile(src, dir, 2, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	share := filepath.Join(dir, "secret.001.share")
+	data, _ := os.ReadFile(share)
+	data[len(data)-1] ^= 1
+	os.WriteFile(share, data, ShareFileMode)
+	if err := RecoverFile(manifest, filepath.Join(dir, "out")); !errors.Is(err, ErrShareFileDigest) {
+		failNow(t, expected(ErrShareFileDigest, err))
+	}
+}
@@ -0,0 +1,49 @@
+package tss
+
+import (
+	"crypto/sha256"
+	"sort"
+)
+
+const (
+	// FingerprintWords is the number of PGP words of a fingerprint
+	FingerprintWords = 4
+	// fingerprintDomain separates the fingerprint hashes from other uses
+	fingerprintDomain = "go-tss fingerprint"
+)
+
+// Fingerprint returns a short PGP word fingerprint of the share, for
+// custodians to read aloud and cross-check during a key ceremony. It is
+// derived from the share, keep it within the ceremony.
+func (s Share) Fingerprint() string {
+	digest := s.fingerprintDigest()
+	return pgpWords(digest[:FingerprintWords])
+}
+
+func (s Share) fingerprintDigest() []byte {
+	h := sha256.New()
+	h.Write([]byte(fingerprintDomain))
+	h.Write([]byte{0, byte(len(s))})
+	h.Write(s)
+	return h.Sum(nil)
+}
+
+// Fingerprint returns a short PGP word fingerprint of the share set. It does
+// not depend on the order of the shares, so custodians holding all of them can
+// agree on a single fingerprint for the dealing.
+func (ss ShareSet) Fingerprint() string {
+	digests := make([][]byte, len(ss))
+	for i, s := range ss {
+		digests[i] = s.fingerprintDigest()
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		return string(digests[i]) < string(digests[j])
+	})
+	h := sha256.New()
+	h.Write([]byte(fingerprintDomain))
+	h.Write([]byte{1, byte(len(ss))})
+	for _, d := range digests {
+		h.Write(d)
+	}
+	return pgpWords(h.Sum(nil)[:FingerprintWords])
+}
@@ -0,0 +1,34 @@
+package tss
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	shares, _ := CreateShares(randomBytes(16), 5, 3)
+	fingerprints := map[string]bool{}
+	for _, s := range shares {
+		f := s.Fingerprint()
+		if len(strings.Fields(f)) != FingerprintWords {
+			failNow(t, fmt.Errorf("fingerprint %q", f))
+		}
+		fingerprints[f] = true
+	}
+	if len(fingerprints) != len(shares) {
+		failNow(t, fmt.Errorf("%d distinct fingerprints for %d shares", len(fingerprints), len(shares)))
+	}
+	reversed := ShareSet{shares[4], shares[3], shares[2], shares[1], shares[0]}
+	if shares.Fingerprint() != reversed.Fingerprint() {
+		failNow(t, fmt.Errorf("set fingerprint depends on order"))
+	}
+	if shares.Fingerprint() == shares[:4].Fingerprint() {
+		failNow(t, fmt.Errorf("set fingerprint ignores a missing share"))
+	}
+	before := shares[0].Fingerprint()
+	shares[0][1] ^= 1
+	if shares[0].Fingerprint() == before {
+		failNow(t, fmt.Errorf("fingerprint ignores a modified share"))
+	}
+}
@@ -0,0 +1,270 @@
+package tss
+
+import (
+	"math"
+)
+
+// FountainHeaderBytes is the size of the header prepended to every fountain
+// frame: share length, block size and frame sequence number
+const FountainHeaderBytes = 8
+
+var (
+	ErrInvalidBlockSize = validationError("invalid block size")
+	ErrInvalidFrame     = validationError("invalid frame")
+	ErrIncomplete       = validationError("not enough frames")
+)
+
+// FountainEncoder produces an endless stream of Luby transform frames for a
+// share. The first frames carry the share blocks verbatim, the following ones
+// are random combinations of blocks, so a receiver can rebuild the share from
+// any sufficient subset of frames, whatever frames were lost.
+type FountainEncoder struct {
+	blocks [][]byte
+	size   int
+	length int
+	cdf    []float64
+}
+
+// NewFountainEncoder creates an encoder for share, cutting it in blocks of
+// blockSize bytes. Use FountainBlockSize to fit every frame in a QR code.
+func NewFountainEncoder(share Share, blockSize int) (*FountainEncoder, error) {
+	if len(share) < MinShareBytes || len(share) > MaxShareBytes {
+		return nil, ErrInvalidShare
+	}
+	if blockSize < 1 || blockSize > 0xffff {
+		return nil, ErrInvalidBlockSize
+	}
+	k := (len(share) + blockSize - 1) / blockSize
+	blocks := make([][]byte, k)
+	for i := range blocks {
+		blocks[i] = make([]byte, blockSize)
+		copy(blocks[i], share[i*blockSize:])
+	}
+	return &FountainEncoder{blocks: blocks, size: blockSize, length: len(share), cdf: solitonCDF(k)}, nil
+}
+
+// FountainBlockSize returns the largest block size whose frames fit in a QR
+// code of the given version and level
+func FountainBlockSize(version int, level QRLevel) (int, error) {
+	capacity, err := QRCapacity(version, level)
+	if err != nil {
+		return 0, err
+	}
+	if capacity <= FountainHeaderBytes {
+		return 0, ErrQRTooSmall
+	}
+	return capacity - FountainHeaderBytes, nil
+}
+
+// Blocks returns the number of source blocks, the minimum number of frames
+// a receiver needs
+func (e *FountainEncoder) Blocks() int {
+	return len(e.blocks)
+}
+
+// Frame returns the frame with sequence number seq. The same sequence number
+// always produces the same frame.
+func (e *FountainEncoder) Frame(seq uint32) []byte {
+	frame := make([]byte, FountainHeaderBytes+e.size)
+	frame[0], frame[1] = byte(e.length>>8), byte(e.length)
+	frame[2], frame[3] = byte(e.size>>8), byte(e.size)
+	frame[4], frame[5], frame[6], frame[7] = byte(seq>>24), byte(seq>>16), byte(seq>>8), byte(seq)
+	payload := frame[FountainHeaderBytes:]
+	for _, i := range ltIndexes(seq, len(e.blocks), e.cdf) {
+		xorBytes(payload, e.blocks[i])
+	}
+	return frame
+}
+
+// FountainDecoder rebuilds a share from the frames of a FountainEncoder
+type FountainDecoder struct {
+	blocks  [][]byte
+	known   int
+	pending []ltEquation
+	size    int
+	length  int
+	cdf     []float64
+}
+
+type ltEquation struct {
+	indexes []int
+	data    []byte
+}
+
+// NewFountainDecoder creates an empty decoder, parameters are taken from the
+// first frame added
+func NewFountainDecoder() *FountainDecoder {
+	return &FountainDecoder{}
+}
+
+// Add feeds a frame to the decoder and reports whether the share is complete.
+// Frames may arrive in any order, duplicates are harmless.
+func (d *FountainDecoder) Add(frame []byte) (bool, error) {
+	if len(frame) <= FountainHeaderBytes {
+		return false, ErrInvalidFrame
+	}
+	length := int(frame[0])<<8 | int(frame[1])
+	size := int(frame[2])<<8 | int(frame[3])
+	seq := uint32(frame[4])<<24 | uint32(frame[5])<<16 | uint32(frame[6])<<8 | uint32(frame[7])
+	if size == 0 || len(frame) != FountainHeaderBytes+size || length < MinShareBytes {
+		return false, ErrInvalidFrame
+	}
+	if d.blocks == nil {
+		d.length, d.size = length, size
+		d.blocks = make([][]byte, (length+size-1)/size)
+		d.cdf = solitonCDF(len(d.blocks))
+	}
+	if length != d.length || size != d.size {
+		return false, ErrInvalidFrame
+	}
+	if d.Done() {
+		return true, nil
+	}
+	data := make([]byte, size)
+	copy(data, frame[FountainHeaderBytes:])
+	d.pending = append(d.pending, ltEquation{indexes: ltIndexes(seq, len(d.blocks), d.cdf), data: data})
+	d.peel()
+	return d.Done(), nil
+}
+
+// peel reduces the pending equations with the known blocks until no new block
+// is learnt
+func (d *FountainDecoder) peel() {
+	for progress := true; progress; {
+		progress = false
+		pending := d.pending[:0]
+		for _, eq := range d.pending {
+			indexes := eq.indexes[:0]
+			for _, i := range eq.indexes {
+				if d.blocks[i] != nil {
+					xorBytes(eq.data, d.blocks[i])
+				} else {
+					indexes = append(indexes, i)
+				}
+			}
+			eq.indexes = indexes
+			switch len(indexes) {
+			case 0:
+			case 1:
+				if d.blocks[indexes[0]] == nil {
+					d.blocks[indexes[0]] = eq.data
+					d.known++
+					progress = true
+				}
+			default:
+				pending = append(pending, eq)
+			}
+		}
+		d.pending = pending
+	}
+}
+
+// Done reports whether every block of the share is known
+func (d *FountainDecoder) Done() bool {
+	return d.blocks != nil && d.known == len(d.blocks)
+}
+
+// Share returns the decoded share
+func (d *FountainDecoder) Share() (Share, error) {
+	if !d.Done() {
+		return nil, ErrIncomplete
+	}
+	share := make(Share, 0, len(d.blocks)*d.size)
+	for _, b := range d.blocks {
+		share = append(share, b...)
+	}
+	return share[:d.length], nil
+}
+
+// ltIndexes returns the source blocks combined in frame seq. The first k
+// frames are the source blocks themselves.
+func ltIndexes(seq uint32, k int, cdf []float64) []int {
+	if int64(seq) < int64(k) {
+		return []int{int(seq)}
+	}
+	rng := xorshift32(seq*0x9e3779b9 ^ 0x5bd1e995)
+	u := float64(rng.next()) / (1 << 32)
+	degree := 1
+	for degree < k && cdf[degree-1] < u {
+		degree++
+	}
+	indexes := make([]int, 0, degree)
+	for len(indexes) < degree {
+		i := int(rng.next() % uint32(k))
+		dup := false
+		for _, j := range indexes {
+			if i == j {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// solitonCDF returns the cumulative robust soliton distribution for k blocks,
+// cdf[d-1] being the probability of a degree less or equal than d
+func solitonCDF(k int) []float64 {
+	const c, delta = 0.1, 0.5
+	r := c * math.Log(float64(k)/delta) * math.Sqrt(float64(k))
+	pivot := k
+	if r > 0 {
+		pivot = int(float64(k) / r)
+	}
+	if pivot < 1 {
+		pivot = 1
+	}
+	if pivot > k {
+		pivot = k
+	}
+	p := make([]float64, k)
+	var sum float64
+	for d := 1; d <= k; d++ {
+		rho := 1 / float64(k)
+		if d > 1 {
+			rho = 1 / float64(d*(d-1))
+		}
+		var tau float64
+		if d < pivot {
+			tau = r / float64(d*k)
+		} else if d == pivot && r > 0 {
+			tau = r * math.Log(r/delta) / float64(k)
+		}
+		if tau < 0 {
+			tau = 0
+		}
+		p[d-1] = rho + tau
+		sum += p[d-1]
+	}
+	var acc float64
+	for i := range p {
+		acc += p[i] / sum
+		p[i] = acc
+	}
+	return p
+}
+
+// xorshift32 is a small deterministic generator so encoder and decoder agree
+// on the block combinations whatever platform they run on
+type xorshift32 uint32
+
+func (x *xorshift32) next() uint32 {
+	if *x == 0 {
+		*x = 0x6b8b4567
+	}
+	v := uint32(*x)
+	v ^= v << 13
+	v ^= v >> 17
+	v ^= v << 5
+	*x = xorshift32(v)
+	return v
+}
+
+func xorBytes(dst []byte, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
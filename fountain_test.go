@@ -0,0 +1,66 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestFountain(t *testing.T) {
+	shares, err := CreateShares(randomBytes(MaxSecretBytes), 2, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	size, err := FountainBlockSize(20, QRLevelM)
+	if err != nil {
+		failNow(t, err)
+	}
+	enc, err := NewFountainEncoder(shares[0], size)
+	if err != nil {
+		failNow(t, err)
+	}
+	dec := NewFountainDecoder()
+	// lose every third frame
+	sent := 0
+	for seq := uint32(0); !dec.Done(); seq++ {
+		if seq%3 == 0 {
+			continue
+		}
+		if _, err := dec.Add(enc.Frame(seq)); err != nil {
+			failNow(t, err)
+		}
+		sent++
+		if sent > 3*enc.Blocks() {
+			failNow(t, fmt.Errorf("not decoded after %d frames of %d blocks", sent, enc.Blocks()))
+		}
+	}
+	share, err := dec.Share()
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(share, shares[0]) {
+		failNow(t, fmt.Errorf("share mismatch"))
+	}
+}
+
+func TestFountainErrors(t *testing.T) {
+	if _, err := NewFountainEncoder(Share{1}, 10); err != ErrInvalidShare {
+		failNow(t, expected(ErrInvalidShare, err))
+	}
+	if _, err := NewFountainEncoder(randomBytes(10), 0); err != ErrInvalidBlockSize {
+		failNow(t, expected(ErrInvalidBlockSize, err))
+	}
+	dec := NewFountainDecoder()
+	if _, err := dec.Share(); err != ErrIncomplete {
+		failNow(t, expected(ErrIncomplete, err))
+	}
+	if _, err := dec.Add(randomBytes(FountainHeaderBytes)); err != ErrInvalidFrame {
+		failNow(t, expected(ErrInvalidFrame, err))
+	}
+	a, _ := NewFountainEncoder(randomBytes(100), 10)
+	b, _ := NewFountainEncoder(randomBytes(100), 20)
+	dec.Add(a.Frame(0))
+	if _, err := dec.Add(b.Frame(0)); err != ErrInvalidFrame {
+		failNow(t, expected(ErrInvalidFrame, err))
+	}
+}
@@ -0,0 +1,578 @@
+// Package frost signs with a key shared by the tss and dkg packages without
+// reconstructing it, with the two round FROST protocol of RFC 9591 and its
+// FROST(P-256, SHA-256) and FROST(Ed25519, SHA-512) ciphersuites.
+//
+// In the first round every signer draws nonces with Commit and sends its
+// Commitment to the coordinator. In the second the coordinator sends the
+// message and the commitments of the signers to each of them, they answer
+// with Sign, and the coordinator combines the signature shares with
+// Aggregate into a Schnorr signature Verify checks against the public key.
+// The functions of the package use P256SHA256, the methods of Ed25519SHA512
+// give signatures crypto/ed25519 verifies, for keys split with
+// tss.SplitScalar(tss.Ed25519, ...).
+package frost
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+
+	"filippo.io/edwards25519"
+	tss "github.com/antik10ud/go-tss"
+	"github.com/antik10ud/go-tss/internal/p256"
+)
+
+var (
+	ErrNoncesUsed            = fmt.Errorf("nonces already used: %w", tss.ErrValidation)
+	ErrNoncesRequired        = fmt.Errorf("nonces required: %w", tss.ErrValidation)
+	ErrInvalidCommitments    = fmt.Errorf("invalid signing commitments: %w", tss.ErrValidation)
+	ErrInvalidSignatureShare = fmt.Errorf("invalid signature share: %w", tss.ErrIntegrity)
+)
+
+// SignatureBytes is the size of a P256SHA256 signature, a compressed point
+// and a scalar
+const SignatureBytes = 33 + tss.ScalarBytes
+
+// element is a point of the group of a ciphersuite
+type element interface {
+	add(element) element
+	mul(*big.Int) element
+	equal(element) bool
+	bytes() []byte
+}
+
+type p256Element struct {
+	p256.Point
+}
+
+func (a p256Element) add(b element) element {
+	return p256Element{a.Add(b.(p256Element).Point)}
+}
+
+func (a p256Element) mul(k *big.Int) element {
+	return p256Element{a.Mul(k)}
+}
+
+func (a p256Element) equal(b element) bool {
+	return a.Equal(b.(p256Element).Point)
+}
+
+func (a p256Element) bytes() []byte {
+	return a.Bytes()
+}
+
+type ed25519Element struct {
+	*edwards25519.Point
+}
+
+func (a ed25519Element) add(b element) element {
+	return ed25519Element{new(edwards25519.Point).Add(a.Point, b.(ed25519Element).Point)}
+}
+
+func (a ed25519Element) mul(k *big.Int) element {
+	return ed25519Element{new(edwards25519.Point).ScalarMult(ed25519Scalar(k), a.Point)}
+}
+
+func (a ed25519Element) equal(b element) bool {
+	return a.Equal(b.(ed25519Element).Point) == 1
+}
+
+func (a ed25519Element) bytes() []byte {
+	return a.Bytes()
+}
+
+var (
+	ed25519Order, _ = new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	// ed25519OrderMinusOne multiplies a point into its inverse when it is in
+	// the prime order subgroup
+	ed25519OrderMinusOne = ed25519Scalar(new(big.Int).Sub(ed25519Order, big.NewInt(1)))
+)
+
+// ed25519Scalar converts k, reduced modulo the order, to a scalar
+func ed25519Scalar(k *big.Int) *edwards25519.Scalar {
+	s, err := new(edwards25519.Scalar).SetCanonicalBytes(ed25519Encode(new(big.Int).Mod(k, ed25519Order)))
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// ed25519Encode returns the little endian encoding of a scalar
+func ed25519Encode(k *big.Int) []byte {
+	return reverse(k.FillBytes(make([]byte, 32)))
+}
+
+// ed25519Decode decodes a little endian scalar, ok being false unless it is
+// below the order
+func ed25519Decode(b []byte) (*big.Int, bool) {
+	if _, err := new(edwards25519.Scalar).SetCanonicalBytes(b); err != nil {
+		return nil, false
+	}
+	return new(big.Int).SetBytes(reverse(b)), true
+}
+
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i, c := range b {
+		r[len(b)-1-i] = c
+	}
+	return r
+}
+
+// ed25519Point decodes a point as RFC 9591 does: canonical, not the identity
+// and in the prime order subgroup
+func ed25519Point(b []byte) (element, bool) {
+	p, err := new(edwards25519.Point).SetBytes(b)
+	if err != nil || !bytes.Equal(p.Bytes(), b) || p.Equal(edwards25519.NewIdentityPoint()) == 1 {
+		return nil, false
+	}
+	q := new(edwards25519.Point).ScalarMult(ed25519OrderMinusOne, p)
+	if q.Add(q, p).Equal(edwards25519.NewIdentityPoint()) != 1 {
+		return nil, false
+	}
+	return ed25519Element{p}, true
+}
+
+// Ciphersuite is a ciphersuite of RFC 9591: the group, its encodings and the
+// hash functions H1 to H5
+type Ciphersuite struct {
+	name         string
+	order        *big.Int
+	cofactor     *big.Int
+	elementBytes int
+	decodePoint  func([]byte) (element, bool)
+	baseMul      func(*big.Int) element
+	scalar       func(*big.Int) []byte
+	decodeScalar func([]byte) (*big.Int, bool)
+	// hashToScalar is H1, H2 and H3 for the tags rho, chal and nonce
+	hashToScalar func(tag string, msg []byte) *big.Int
+	// hash is H4 and H5 for the tags msg and com
+	hash func(tag string, msg []byte) []byte
+}
+
+var (
+	// P256SHA256 is FROST(P-256, SHA-256), the ciphersuite of the keys of the
+	// dkg package
+	P256SHA256 = &Ciphersuite{
+		name:         "FROST-P256-SHA256-v1",
+		order:        p256.Order,
+		elementBytes: 33,
+		decodePoint: func(b []byte) (element, bool) {
+			p, ok := p256.Decode(b)
+			return p256Element{p}, ok
+		},
+		baseMul: func(k *big.Int) element {
+			return p256Element{p256.BaseMul(k)}
+		},
+		scalar:       p256.Scalar,
+		decodeScalar: p256.DecodeScalar,
+		hashToScalar: func(tag string, msg []byte) *big.Int {
+			k := new(big.Int).SetBytes(expandMessage(msg, []byte("FROST-P256-SHA256-v1"+tag), 48))
+			return k.Mod(k, p256.Order)
+		},
+		hash: func(tag string, msg []byte) []byte {
+			h := sha256.Sum256(append([]byte("FROST-P256-SHA256-v1"+tag), msg...))
+			return h[:]
+		},
+	}
+	// Ed25519SHA512 is FROST(Ed25519, SHA-512), whose signatures are Ed25519
+	// signatures
+	Ed25519SHA512 = &Ciphersuite{
+		name:         "FROST-ED25519-SHA512-v1",
+		order:        ed25519Order,
+		cofactor:     big.NewInt(8),
+		elementBytes: 32,
+		decodePoint:  ed25519Point,
+		baseMul: func(k *big.Int) element {
+			return ed25519Element{new(edwards25519.Point).ScalarBaseMult(ed25519Scalar(k))}
+		},
+		scalar:       ed25519Encode,
+		decodeScalar: ed25519Decode,
+		hashToScalar: func(tag string, msg []byte) *big.Int {
+			// H2 is the challenge of Ed25519, without the context
+			prefix := "FROST-ED25519-SHA512-v1" + tag
+			if tag == "chal" {
+				prefix = ""
+			}
+			h := sha512.Sum512(append([]byte(prefix), msg...))
+			k, err := new(edwards25519.Scalar).SetUniformBytes(h[:])
+			if err != nil {
+				panic(err)
+			}
+			return new(big.Int).SetBytes(reverse(k.Bytes()))
+		},
+		hash: func(tag string, msg []byte) []byte {
+			h := sha512.Sum512(append([]byte("FROST-ED25519-SHA512-v1"+tag), msg...))
+			return h[:]
+		},
+	}
+)
+
+// String returns the context string of the ciphersuite
+func (cs *Ciphersuite) String() string {
+	return cs.name
+}
+
+// SignatureBytes is the size of a signature, an encoded point and a scalar
+func (cs *Ciphersuite) SignatureBytes() int {
+	return cs.elementBytes + tss.ScalarBytes
+}
+
+func (cs *Ciphersuite) point(b []byte) (element, error) {
+	p, ok := cs.decodePoint(b)
+	if !ok {
+		return nil, tss.ErrInvalidPoint
+	}
+	return p, nil
+}
+
+func (cs *Ciphersuite) scalarOf(b []byte) (*big.Int, error) {
+	k, ok := cs.decodeScalar(b)
+	if !ok {
+		return nil, tss.ErrInvalidScalar
+	}
+	return k, nil
+}
+
+// h hashes parts into a scalar with hashToScalar
+func (cs *Ciphersuite) h(tag string, parts ...[]byte) *big.Int {
+	var msg []byte
+	for _, p := range parts {
+		msg = append(msg, p...)
+	}
+	return cs.hashToScalar(tag, msg)
+}
+
+// lagrange returns the Lagrange coefficient at zero of the i-th of the
+// distinct ids, the product of x_j / (x_j - x_i)
+func (cs *Ciphersuite) lagrange(i int, ids []*big.Int) *big.Int {
+	num, den, t := big.NewInt(1), big.NewInt(1), new(big.Int)
+	for j, x := range ids {
+		if j == i {
+			continue
+		}
+		num.Mul(num, x)
+		den.Mul(den, t.Sub(x, ids[i]))
+	}
+	den.Mod(den, cs.order)
+	num.Mul(num, den.ModInverse(den, cs.order))
+	return num.Mod(num, cs.order)
+}
+
+// verificationShare returns the public share index of the polynomial the
+// Feldman commitments commit to
+func (cs *Ciphersuite) verificationShare(commitments [][]byte, index byte) (element, bool) {
+	var p element
+	for k := len(commitments) - 1; k >= 0; k-- {
+		c, ok := cs.decodePoint(commitments[k])
+		if !ok {
+			return nil, false
+		}
+		if p == nil {
+			p = c
+			continue
+		}
+		p = p.mul(big.NewInt(int64(index))).add(c)
+	}
+	return p, p != nil
+}
+
+// expandMessage is expand_message_xmd of RFC 9380 with SHA-256
+func expandMessage(msg []byte, dst []byte, n int) []byte {
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+	h := sha256.New()
+	h.Write(make([]byte, h.BlockSize()))
+	h.Write(msg)
+	h.Write([]byte{byte(n >> 8), byte(n), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+	var out, b []byte
+	for i := 1; len(out) < n; i++ {
+		h.Reset()
+		if b == nil {
+			h.Write(b0)
+		} else {
+			for j := range b {
+				b[j] ^= b0[j]
+			}
+			h.Write(b)
+		}
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		b = h.Sum(nil)
+		out = append(out, b...)
+	}
+	return out[:n]
+}
+
+// Commitment is what a signer publishes in the first round, the points of
+// its hiding and binding nonces
+type Commitment struct {
+	Identifier byte
+	Hiding     []byte
+	Binding    []byte
+}
+
+// Nonces are the secret nonces of a signer for one signature, they must
+// never be used twice
+type Nonces struct {
+	hiding  *big.Int
+	binding *big.Int
+}
+
+// SignatureShare is the answer of a signer in the second round
+type SignatureShare struct {
+	Identifier byte
+	Z          []byte
+}
+
+// Signer holds a share of the signing key
+type Signer struct {
+	suite     *Ciphersuite
+	share     tss.ScalarShare
+	secret    *big.Int
+	publicKey element
+}
+
+// NewSigner returns the P256SHA256 signer holding share, a P-256 share of the
+// key whose compressed public key is publicKey, such as a dkg.Result gives
+func NewSigner(share tss.ScalarShare, publicKey []byte) (*Signer, error) {
+	return P256SHA256.NewSigner(share, publicKey)
+}
+
+// NewSigner returns the signer holding share, a share of the key whose
+// encoded public key is publicKey
+func (cs *Ciphersuite) NewSigner(share tss.ScalarShare, publicKey []byte) (*Signer, error) {
+	secret, err := cs.scalarOf(share.Value)
+	if err != nil {
+		return nil, err
+	}
+	if share.Index == 0 {
+		return nil, tss.ErrInvalidShareIndex
+	}
+	pk, err := cs.point(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{suite: cs, share: share, secret: secret, publicKey: pk}, nil
+}
+
+// nonce is nonce_generate of RFC 9591, drawing 32 bytes from random
+func (s *Signer) nonce(random io.Reader) (*big.Int, error) {
+	b := make([]byte, 32)
+	if _, err := io.ReadFull(random, b); err != nil {
+		return nil, err
+	}
+	return s.suite.h("nonce", b, s.suite.scalar(s.secret)), nil
+}
+
+// Commit draws the nonces of the first round. The commitment goes to the
+// coordinator, the nonces are kept for the Sign of the same signature.
+func (s *Signer) Commit() (*Nonces, Commitment, error) {
+	return s.CommitWithRand(rand.Reader)
+}
+
+// CommitWithRand is Commit drawing the randomness of the hiding and then of
+// the binding nonce from random, such as for test vectors
+func (s *Signer) CommitWithRand(random io.Reader) (*Nonces, Commitment, error) {
+	hiding, err := s.nonce(random)
+	if err != nil {
+		return nil, Commitment{}, err
+	}
+	binding, err := s.nonce(random)
+	if err != nil {
+		return nil, Commitment{}, err
+	}
+	return &Nonces{hiding: hiding, binding: binding}, Commitment{
+		Identifier: s.share.Index,
+		Hiding:     s.suite.baseMul(hiding).bytes(),
+		Binding:    s.suite.baseMul(binding).bytes(),
+	}, nil
+}
+
+// signing is the state of a signature shared by the signers and the
+// coordinator: the binding factors, the group commitment and the challenge
+type signing struct {
+	suite       *Ciphersuite
+	identifiers []*big.Int
+	hiding      []element
+	binding     []element
+	rho         []*big.Int
+	r           element
+	challenge   *big.Int
+}
+
+// newSigning checks the commitments, sorted by identifier, and derives the
+// binding factors, group commitment and challenge
+func (cs *Ciphersuite) newSigning(publicKey element, message []byte, commitments []Commitment) (*signing, error) {
+	if len(commitments) < tss.MinThreshold || !sort.SliceIsSorted(commitments, func(i, j int) bool {
+		return commitments[i].Identifier < commitments[j].Identifier
+	}) {
+		return nil, ErrInvalidCommitments
+	}
+	g := &signing{suite: cs}
+	var encoded []byte
+	for i, c := range commitments {
+		if c.Identifier == 0 || i > 0 && c.Identifier == commitments[i-1].Identifier {
+			return nil, ErrInvalidCommitments
+		}
+		hiding, err := cs.point(c.Hiding)
+		if err != nil {
+			return nil, ErrInvalidCommitments
+		}
+		binding, err := cs.point(c.Binding)
+		if err != nil {
+			return nil, ErrInvalidCommitments
+		}
+		id := big.NewInt(int64(c.Identifier))
+		g.identifiers = append(g.identifiers, id)
+		g.hiding = append(g.hiding, hiding)
+		g.binding = append(g.binding, binding)
+		encoded = append(encoded, cs.scalar(id)...)
+		encoded = append(encoded, c.Hiding...)
+		encoded = append(encoded, c.Binding...)
+	}
+	prefix := append(publicKey.bytes(), cs.hash("msg", message)...)
+	prefix = append(prefix, cs.hash("com", encoded)...)
+	for i, id := range g.identifiers {
+		rho := cs.h("rho", prefix, cs.scalar(id))
+		g.rho = append(g.rho, rho)
+		term := g.hiding[i].add(g.binding[i].mul(rho))
+		if i == 0 {
+			g.r = term
+		} else {
+			g.r = g.r.add(term)
+		}
+	}
+	g.challenge = cs.h("chal", g.r.bytes(), publicKey.bytes(), message)
+	return g, nil
+}
+
+// lambda returns the Lagrange coefficient at zero of the i-th signer
+func (g *signing) lambda(i int) *big.Int {
+	return g.suite.lagrange(i, g.identifiers)
+}
+
+// Sign computes the signature share of message, commitments being those of
+// all the signers, this one included, sorted by identifier. The nonces are
+// erased, signing again needs a new Commit.
+func (s *Signer) Sign(message []byte, nonces *Nonces, commitments []Commitment) (*SignatureShare, error) {
+	if nonces == nil {
+		return nil, ErrNoncesRequired
+	}
+	if nonces.hiding == nil {
+		return nil, ErrNoncesUsed
+	}
+	defer func() {
+		nonces.hiding.SetInt64(0)
+		nonces.binding.SetInt64(0)
+		nonces.hiding, nonces.binding = nil, nil
+	}()
+	cs := s.suite
+	g, err := cs.newSigning(s.publicKey, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+	self := -1
+	for i, c := range commitments {
+		if c.Identifier == s.share.Index {
+			self = i
+		}
+	}
+	if self < 0 || !g.hiding[self].equal(cs.baseMul(nonces.hiding)) || !g.binding[self].equal(cs.baseMul(nonces.binding)) {
+		return nil, ErrInvalidCommitments
+	}
+	// z = d + e*rho + lambda*s*c
+	z := new(big.Int).Mul(nonces.binding, g.rho[self])
+	z.Add(z, nonces.hiding)
+	t := new(big.Int).Mul(g.lambda(self), s.secret)
+	t.Mul(t, g.challenge)
+	z.Add(z, t)
+	z.Mod(z, cs.order)
+	return &SignatureShare{Identifier: s.share.Index, Z: cs.scalar(z)}, nil
+}
+
+// Aggregate is Aggregate of P256SHA256
+func Aggregate(publicKey []byte, message []byte, commitments []Commitment, shares []*SignatureShare, keyCommitments tss.Commitments) ([]byte, error) {
+	return P256SHA256.Aggregate(publicKey, message, commitments, shares, keyCommitments)
+}
+
+// Aggregate combines the signature shares of the signers of commitments into
+// the signature of message. When the commitments of the key, such as
+// dkg.Result holds, are given every share is checked first, a wrong one
+// giving a *tss.ShareError matching ErrInvalidSignatureShare that names the
+// signer.
+func (cs *Ciphersuite) Aggregate(publicKey []byte, message []byte, commitments []Commitment, shares []*SignatureShare, keyCommitments tss.Commitments) ([]byte, error) {
+	pk, err := cs.point(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	g, err := cs.newSigning(pk, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+	if len(shares) != len(commitments) {
+		return nil, ErrInvalidCommitments
+	}
+	z := new(big.Int)
+	for i, share := range shares {
+		if share == nil || share.Identifier != commitments[i].Identifier {
+			return nil, ErrInvalidCommitments
+		}
+		zi, err := cs.scalarOf(share.Z)
+		if err != nil {
+			return nil, &tss.ShareError{Position: i + 1, Index: share.Identifier, Reason: err}
+		}
+		if keyCommitments != nil {
+			public, ok := cs.verificationShare(keyCommitments, share.Identifier)
+			if !ok {
+				return nil, tss.ErrInvalidCommitment
+			}
+			want := g.hiding[i].add(g.binding[i].mul(g.rho[i]))
+			c := new(big.Int).Mul(g.challenge, g.lambda(i))
+			want = want.add(public.mul(c.Mod(c, cs.order)))
+			if !cs.baseMul(zi).equal(want) {
+				return nil, &tss.ShareError{Position: i + 1, Index: share.Identifier, Reason: ErrInvalidSignatureShare}
+			}
+		}
+		z.Add(z, zi)
+	}
+	z.Mod(z, cs.order)
+	return append(g.r.bytes(), cs.scalar(z)...), nil
+}
+
+// Verify is Verify of P256SHA256
+func Verify(publicKey []byte, message []byte, signature []byte) bool {
+	return P256SHA256.Verify(publicKey, message, signature)
+}
+
+// Verify checks signature is a signature of message by the key publicKey.
+// With a cofactor, as for Ed25519, the check is the cofactored one.
+func (cs *Ciphersuite) Verify(publicKey []byte, message []byte, signature []byte) bool {
+	if len(signature) != cs.SignatureBytes() {
+		return false
+	}
+	pk, err := cs.point(publicKey)
+	if err != nil {
+		return false
+	}
+	r, err := cs.point(signature[:cs.elementBytes])
+	if err != nil {
+		return false
+	}
+	z, err := cs.scalarOf(signature[cs.elementBytes:])
+	if err != nil {
+		return false
+	}
+	c := cs.h("chal", r.bytes(), pk.bytes(), message)
+	lhs, rhs := cs.baseMul(z), r.add(pk.mul(c))
+	if cs.cofactor != nil {
+		lhs, rhs = lhs.mul(cs.cofactor), rhs.mul(cs.cofactor)
+	}
+	return lhs.equal(rhs)
+}
@@ -0,0 +1,253 @@
+package frost
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	tss "github.com/antik10ud/go-tss"
+	"github.com/antik10ud/go-tss/dkg"
+)
+
+func TestExpandMessage(t *testing.T) {
+	// RFC 9380, appendix K.1
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	for msg, want := range map[string]string{
+		"":    "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235",
+		"abc": "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615",
+	} {
+		if got := hex.EncodeToString(expandMessage([]byte(msg), dst, 32)); got != want {
+			t.Fatalf("expand %q: %s, want %s", msg, got, want)
+		}
+	}
+}
+
+// keygen shares a key among n parties with the dkg package
+func keygen(t *testing.T, n int, threshold int) []*dkg.Result {
+	parties := make([]*dkg.Party, n)
+	for i := range parties {
+		parties[i], _ = dkg.NewParty(byte(i+1), n, threshold)
+	}
+	for _, dealer := range parties {
+		deal, shares, err := dealer.Deal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, p := range parties {
+			p.ReceiveDeal(deal)
+			p.ReceiveShare(shares[i])
+		}
+	}
+	results := make([]*dkg.Result, n)
+	for i, p := range parties {
+		p.Complaints()
+		p.Justifications()
+		var err error
+		if results[i], err = p.Finish(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return results
+}
+
+// sign runs both rounds with the signers at positions of keys
+func sign(t *testing.T, keys []*dkg.Result, message []byte, positions ...int) ([]Commitment, []*SignatureShare) {
+	signers := make([]*Signer, len(positions))
+	nonces := make([]*Nonces, len(positions))
+	commitments := make([]Commitment, len(positions))
+	for i, p := range positions {
+		var err error
+		if signers[i], err = NewSigner(keys[p].Share, keys[p].PublicKey); err != nil {
+			t.Fatal(err)
+		}
+		if nonces[i], commitments[i], err = signers[i].Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shares := make([]*SignatureShare, len(positions))
+	for i, s := range signers {
+		var err error
+		if shares[i], err = s.Sign(message, nonces[i], commitments); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := signers[0].Sign(message, nonces[0], commitments); err != ErrNoncesUsed {
+		t.Fatal(err)
+	}
+	return commitments, shares
+}
+
+func TestSign(t *testing.T) {
+	keys := keygen(t, 5, 3)
+	message := []byte("transfer 10 coins")
+	commitments, shares := sign(t, keys, message, 0, 2, 4)
+	signature, err := Aggregate(keys[0].PublicKey, message, commitments, shares, keys[0].Commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Verify(keys[0].PublicKey, message, signature) {
+		t.Fatal("signature does not verify")
+	}
+	if Verify(keys[0].PublicKey, []byte("transfer 99 coins"), signature) {
+		t.Fatal("signature verifies another message")
+	}
+	// two signers are not enough
+	commitments, shares = sign(t, keys, message, 1, 3)
+	signature, _ = Aggregate(keys[0].PublicKey, message, commitments, shares, nil)
+	if Verify(keys[0].PublicKey, message, signature) {
+		t.Fatal("signature below threshold verifies")
+	}
+}
+
+func TestAggregateCheating(t *testing.T) {
+	keys := keygen(t, 4, 2)
+	message := []byte("message")
+	commitments, shares := sign(t, keys, message, 1, 3)
+	shares[1].Z[31] ^= 1
+	_, err := Aggregate(keys[0].PublicKey, message, commitments, shares, keys[0].Commitments)
+	var shareErr *tss.ShareError
+	if !errors.As(err, &shareErr) || shareErr.Index != 4 || !errors.Is(err, ErrInvalidSignatureShare) {
+		t.Fatal(err)
+	}
+	if _, err := Aggregate(keys[0].PublicKey, message, []Commitment{commitments[1], commitments[0]}, shares, nil); err != ErrInvalidCommitments {
+		t.Fatal(err)
+	}
+}
+
+func TestAggregateNilShare(t *testing.T) {
+	keys := keygen(t, 3, 2)
+	message := []byte("message")
+	commitments, shares := sign(t, keys, message, 0, 2)
+	shares[1] = nil
+	if _, err := Aggregate(keys[0].PublicKey, message, commitments, shares, keys[0].Commitments); err != ErrInvalidCommitments {
+		t.Fatal(err)
+	}
+}
+
+func TestSignNilNonces(t *testing.T) {
+	keys := keygen(t, 3, 2)
+	signer, err := NewSigner(keys[0].Share, keys[0].PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, c1, _ := signer.Commit()
+	if _, err := signer.Sign([]byte("message"), nil, []Commitment{c1}); err != ErrNoncesRequired {
+		t.Fatal(err)
+	}
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestEd25519Vectors(t *testing.T) {
+	// RFC 9591, appendix E.1, signers 1 and 3 of 3 with threshold 2
+	cs := Ed25519SHA512
+	publicKey := decodeHex(t, "15d21ccd7ee42959562fc8aa63224c8851fb3ec85a3faf66040d380fb9738673")
+	message := decodeHex(t, "74657374")
+	vectors := []struct {
+		share, randomness, hiding, binding, z string
+		index                                 byte
+	}{
+		{
+			index:      1,
+			share:      "929dcc590407aae7d388761cddb0c0db6f5627aea8e217f4a033f2ec83d93509",
+			randomness: "0fd2e39e111cdc266f6c0f4d0fd45c947761f1f5d3cb583dfcb9bbaf8d4c9fec69cd85f631d5f7f2721ed5e40519b1366f340a87c2f6856363dbdcda348a7501",
+			hiding:     "b5aa8ab305882a6fc69cbee9327e5a45e54c08af61ae77cb8207be3d2ce13de3",
+			binding:    "67e98ab55aa310c3120418e5050c9cf76cf387cb20ac9e4b6fdb6f82a469f932",
+			z:          "001719ab5a53ee1a12095cd088fd149702c0720ce5fd2f29dbecf24b7281b603",
+		},
+		{
+			index:      3,
+			share:      "d3cb090a075eb154e82fdb4b3cb507f110040905468bb9c46da8bdea643a9a02",
+			randomness: "86d64a260059e495d0fb4fcc17ea3da7452391baa494d4b00321098ed2a0062f13e6b25afb2eba51716a9a7d44130c0dbae0004a9ef8d7b5550c8a0e07c61775",
+			hiding:     "cfbdb165bd8aad6eb79deb8d287bcc0ab6658ae57fdcc98ed12c0669e90aec91",
+			binding:    "7487bc41a6e712eea2f2af24681b58b1cf1da278ea11fe4e8b78398965f13552",
+			z:          "bd86125de990acc5e1f13781d8e32c03a9bbd4c53539bbc106058bfd14326007",
+		},
+	}
+	signers := make([]*Signer, len(vectors))
+	nonces := make([]*Nonces, len(vectors))
+	commitments := make([]Commitment, len(vectors))
+	for i, v := range vectors {
+		var err error
+		if signers[i], err = cs.NewSigner(tss.ScalarShare{Index: v.index, Value: decodeHex(t, v.share)}, publicKey); err != nil {
+			t.Fatal(err)
+		}
+		if nonces[i], commitments[i], err = signers[i].CommitWithRand(bytes.NewReader(decodeHex(t, v.randomness))); err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(commitments[i].Hiding) != v.hiding || hex.EncodeToString(commitments[i].Binding) != v.binding {
+			t.Fatalf("signer %d: commitment %x %x", v.index, commitments[i].Hiding, commitments[i].Binding)
+		}
+	}
+	shares := make([]*SignatureShare, len(vectors))
+	for i, v := range vectors {
+		var err error
+		if shares[i], err = signers[i].Sign(message, nonces[i], commitments); err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(shares[i].Z) != v.z {
+			t.Fatalf("signer %d: signature share %x, want %s", v.index, shares[i].Z, v.z)
+		}
+	}
+	signature, err := cs.Aggregate(publicKey, message, commitments, shares, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "36282629c383bb820a88b71cae937d41f2f2adfcc3d02e55507e2fb9e2dd3cbebd9d2b0844e49ae0f3fa935161e1419aab7b47d21a37ebeae1f17d4987b3160b"
+	if hex.EncodeToString(signature) != want {
+		t.Fatalf("signature %x, want %s", signature, want)
+	}
+	if !cs.Verify(publicKey, message, signature) || !ed25519.Verify(publicKey, message, signature) {
+		t.Fatal("signature does not verify")
+	}
+}
+
+func TestEd25519Sign(t *testing.T) {
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{42}, ed25519.SeedSize))
+	publicKey := key.Public().(ed25519.PublicKey)
+	shares, err := tss.SplitScalar(tss.Ed25519, tss.Ed25519Scalar(key), 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("transfer 10 coins")
+	var commitments []Commitment
+	var nonces []*Nonces
+	var signers []*Signer
+	for _, p := range []int{1, 2, 4} {
+		signer, err := Ed25519SHA512.NewSigner(shares[p], publicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, c, err := signer.Commit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		signers, nonces, commitments = append(signers, signer), append(nonces, n), append(commitments, c)
+	}
+	var signatureShares []*SignatureShare
+	for i, s := range signers {
+		z, err := s.Sign(message, nonces[i], commitments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signatureShares = append(signatureShares, z)
+	}
+	signature, err := Ed25519SHA512.Aggregate(publicKey, message, commitments, signatureShares, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signature) != ed25519.SignatureSize || !ed25519.Verify(publicKey, message, signature) {
+		t.Fatal("signature rejected by crypto/ed25519")
+	}
+	if Ed25519SHA512.Verify(publicKey, []byte("transfer 99 coins"), signature) {
+		t.Fatal("signature verifies another message")
+	}
+}
@@ -0,0 +1,38 @@
+package tss
+
+import "crypto/subtle"
+
+// mulTable[c][b] is c*b in GF(256), mulLow and mulHigh split it by nibble for
+// the vector kernels: c*b = mulLow[c][b&15] ^ mulHigh[c][b>>4]
+var (
+	mulTable [256][256]byte
+	mulLow   [256][16]byte
+	mulHigh  [256][16]byte
+)
+
+func init() {
+	for c := 0; c < 256; c++ {
+		for b := 0; b < 256; b++ {
+			mulTable[c][b] = mul(byte(c), byte(b))
+		}
+		for n := 0; n < 16; n++ {
+			mulLow[c][n] = mulTable[c][n]
+			mulHigh[c][n] = mulTable[c][n<<4]
+		}
+	}
+}
+
+// mulAddSlice sets out[i] ^= c*in[i] for every byte of in, out being at least
+// as long. It works on whole blocks with the vector kernel of the platform
+// when there is one, byte by byte otherwise.
+func mulAddSlice(c byte, in []byte, out []byte) {
+	switch c {
+	case 0:
+		return
+	case 1:
+		subtle.XORBytes(out[:len(in)], out[:len(in)], in)
+		return
+	}
+	done := mulAddVector(c, in, out)
+	mulAddGeneric(c, in[done:], out[done:])
+}
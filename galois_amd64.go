@@ -0,0 +1,36 @@
+package tss
+
+import "golang.org/x/sys/cpu"
+
+// the vector kernels in use, set from the CPU features and turned off by tests
+// to check them against the byte by byte loop. The GFNI kernel is VEX
+// encoded, cpu only reports GFNI along AVX-512 though: other GFNI CPUs run
+// the AVX2 one.
+var (
+	useAVX2 = cpu.X86.HasAVX2
+	useGFNI = cpu.X86.HasAVX2 && cpu.X86.HasAVX512GFNI
+)
+
+// mulAddVector runs the kernel on the whole 32 byte blocks of in and returns
+// the number of bytes done
+func mulAddVector(c byte, in []byte, out []byte) int {
+	n := len(in) &^ 31
+	if n == 0 {
+		return 0
+	}
+	switch {
+	case useGFNI:
+		mulAddGFNI(c, in[:n], out[:n])
+	case useAVX2:
+		mulAddAVX2(&mulLow[c], &mulHigh[c], in[:n], out[:n])
+	default:
+		return 0
+	}
+	return n
+}
+
+//go:noescape
+func mulAddAVX2(low *[16]byte, high *[16]byte, in []byte, out []byte)
+
+//go:noescape
+func mulAddGFNI(c byte, in []byte, out []byte)
@@ -0,0 +1,62 @@
+#include "textflag.h"
+
+// func mulAddAVX2(low *[16]byte, high *[16]byte, in []byte, out []byte)
+// out ^= c*in, 32 bytes at a time, with the nibble tables of c
+TEXT ·mulAddAVX2(SB), NOSPLIT, $0-64
+	MOVQ low+0(FP), AX
+	MOVQ high+8(FP), BX
+	MOVQ in_base+16(FP), SI
+	MOVQ in_len+24(FP), CX
+	MOVQ out_base+40(FP), DI
+	SHRQ $5, CX
+	JZ   avx2_done
+	VBROADCASTI128 (AX), Y6
+	VBROADCASTI128 (BX), Y7
+	MOVQ $15, DX
+	MOVQ DX, X8
+	VPBROADCASTB X8, Y8
+
+avx2_loop:
+	VMOVDQU (SI), Y0
+	VPSRLQ  $4, Y0, Y1
+	VPAND   Y8, Y0, Y0
+	VPAND   Y8, Y1, Y1
+	VPSHUFB Y0, Y6, Y2
+	VPSHUFB Y1, Y7, Y3
+	VPXOR   Y2, Y3, Y2
+	VPXOR   (DI), Y2, Y2
+	VMOVDQU Y2, (DI)
+	ADDQ    $32, SI
+	ADDQ    $32, DI
+	DECQ    CX
+	JNZ     avx2_loop
+	VZEROUPPER
+
+avx2_done:
+	RET
+
+// func mulAddGFNI(c byte, in []byte, out []byte)
+// out ^= c*in, 32 bytes at a time, GF2P8MULB using the field polynomial of TSS
+TEXT ·mulAddGFNI(SB), NOSPLIT, $0-56
+	MOVBQZX c+0(FP), AX
+	MOVQ    in_base+8(FP), SI
+	MOVQ    in_len+16(FP), CX
+	MOVQ    out_base+32(FP), DI
+	SHRQ    $5, CX
+	JZ      gfni_done
+	MOVQ    AX, X6
+	VPBROADCASTB X6, Y6
+
+gfni_loop:
+	VMOVDQU    (SI), Y0
+	VGF2P8MULB Y6, Y0, Y1
+	VPXOR      (DI), Y1, Y1
+	VMOVDQU    Y1, (DI)
+	ADDQ       $32, SI
+	ADDQ       $32, DI
+	DECQ       CX
+	JNZ        gfni_loop
+	VZEROUPPER
+
+gfni_done:
+	RET
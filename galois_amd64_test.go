@@ -0,0 +1,25 @@
+package tss
+
+import "testing"
+
+func TestMulAddKernels(t *testing.T) {
+	avx2, gfni := useAVX2, useGFNI
+	defer func() {
+		useAVX2, useGFNI = avx2, gfni
+	}()
+	for _, c := range []struct {
+		name       string
+		avx2, gfni bool
+	}{
+		{"generic", false, false},
+		{"avx2", true, false},
+		{"gfni", true, true},
+	} {
+		if (c.avx2 && !avx2) || (c.gfni && !gfni) {
+			t.Logf("%s not supported", c.name)
+			continue
+		}
+		useAVX2, useGFNI = c.avx2, c.gfni
+		testMulAddSlice(t)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !amd64
+
+package tss
+
+// mulAddVector has no vector kernel on this platform
+func mulAddVector(c byte, in []byte, out []byte) int {
+	return 0
+}
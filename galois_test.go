@@ -0,0 +1,29 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// testMulAddSlice checks mulAddSlice against mul for every constant
+func testMulAddSlice(t *testing.T) {
+	for c := 0; c < 256; c++ {
+		for _, n := range []int{0, 1, 31, 32, 33, 100, 1024 + 17} {
+			in := randomBytes(n + 1)[1:]
+			out := randomBytes(n + 1)[1:]
+			want := append([]byte{}, out...)
+			for i := range in {
+				want[i] ^= mul(byte(c), in[i])
+			}
+			mulAddSlice(byte(c), in, out)
+			if !bytes.Equal(out, want) {
+				failNow(t, fmt.Errorf("c=%d n=%d: mismatch", c, n))
+			}
+		}
+	}
+}
+
+func TestMulAddSlice(t *testing.T) {
+	testMulAddSlice(t)
+}
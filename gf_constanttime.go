@@ -0,0 +1,20 @@
+//go:build constanttime
+
+package tss
+
+// Built with -tags constanttime, the field arithmetic on secret dependent
+// values uses no table indexed by them and no branch on them, at some cost in
+// speed, for hosts where cache timing side channels are a concern. The vector
+// kernels are kept, they shuffle registers instead of indexing memory.
+
+func mul(x byte, y byte) byte {
+	return ctMul(x, y)
+}
+
+func div(x byte, y byte) byte {
+	return ctDiv(x, y)
+}
+
+func mulAddGeneric(c byte, in []byte, out []byte) {
+	mulAddSWAR(c, in, out)
+}
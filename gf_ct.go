@@ -0,0 +1,58 @@
+package tss
+
+import "encoding/binary"
+
+// ctMul multiplies in GF(256) by shifts and masks, in time independent of x
+// and y
+func ctMul(x byte, y byte) byte {
+	var r byte
+	for i := 0; i < 8; i++ {
+		r ^= -(y & 1) & x
+		y >>= 1
+		x = x<<1 ^ -(x>>7)&0x1b
+	}
+	return r
+}
+
+// ctInv returns y^254, the inverse of y, 0 for 0, in time independent of y
+func ctInv(y byte) byte {
+	var r byte = 1
+	for i := 1; i < 8; i++ {
+		y = ctMul(y, y)
+		r = ctMul(r, y)
+	}
+	return r
+}
+
+// ctDiv is div in time independent of x and y
+func ctDiv(x byte, y byte) byte {
+	return ctMul(x, ctInv(y))
+}
+
+// mulAddSWAR sets out[i] ^= c*in[i] eight bytes at a time, every byte of a
+// word being doubled in the field at once. Only the bits of c, the public
+// weight, drive branches.
+func mulAddSWAR(c byte, in []byte, out []byte) {
+	out = out[:len(in)]
+	n := len(in) &^ 7
+	for i := 0; i < n; i += 8 {
+		a := binary.LittleEndian.Uint64(in[i:])
+		var r uint64
+		for k := c; k != 0; k >>= 1 {
+			if k&1 != 0 {
+				r ^= a
+			}
+			a = xtime64(a)
+		}
+		binary.LittleEndian.PutUint64(out[i:], binary.LittleEndian.Uint64(out[i:])^r)
+	}
+	for i := n; i < len(in); i++ {
+		out[i] ^= ctMul(c, in[i])
+	}
+}
+
+// xtime64 multiplies by 2 each of the bytes packed in a
+func xtime64(a uint64) uint64 {
+	high := a & 0x8080808080808080
+	return (a&0x7f7f7f7f7f7f7f7f)<<1 ^ (high>>7)*0x1b
+}
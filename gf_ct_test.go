@@ -0,0 +1,38 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestConstantTimeArithmetic(t *testing.T) {
+	for x := 0; x < 256; x++ {
+		for y := 0; y < 256; y++ {
+			if got, want := ctMul(byte(x), byte(y)), mulTable[x][y]; got != want {
+				failNow(t, fmt.Errorf("%d*%d = %d, want %d", x, y, got, want))
+			}
+			if y == 0 {
+				continue
+			}
+			if q := ctDiv(byte(x), byte(y)); mulTable[q][y] != byte(x) {
+				failNow(t, fmt.Errorf("%d/%d = %d", x, y, q))
+			}
+		}
+	}
+	if ctInv(0) != 0 || ctDiv(5, 0) != 0 {
+		failNow(t, fmt.Errorf("division by zero must give 0"))
+	}
+	for c := 0; c < 256; c++ {
+		in := randomBytes(45)
+		out := randomBytes(45)
+		want := append([]byte{}, out...)
+		for i := range in {
+			want[i] ^= mulTable[c][in[i]]
+		}
+		mulAddSWAR(byte(c), in, out)
+		if !bytes.Equal(out, want) {
+			failNow(t, fmt.Errorf("c=%d: mismatch", c))
+		}
+	}
+}
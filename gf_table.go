@@ -0,0 +1,36 @@
+//go:build !constanttime
+
+package tss
+
+// The multiplication operation takes two elements X and Y as input and
+// proceeds as follows.  If either X or Y is equal to 0x00, then the
+// operation returns 0x00.
+// this function should inline
+// inline func
+func mul(x byte, y byte) byte {
+	if x == 0 || y == 0 {
+		return 0
+	}
+	return expOp[logOp[x]+logOp[y]]
+}
+
+// The division operation takes a dividend X and a divisor Y as input
+// and computes X divided by Y as follows.  If X is equal to 0x00, then
+// the operation returns 0x00.  If Y is equal to 0x00, then the input is
+// invalid and zero ir returned.
+// inline func
+func div(x byte, y byte) byte {
+	if x == 0 || y == 0 {
+		return 0
+	}
+	return expOp[0xff+logOp[x]-logOp[y]]
+}
+
+// mulAddGeneric sets out[i] ^= c*in[i] with the product table of c
+func mulAddGeneric(c byte, in []byte, out []byte) {
+	mt := &mulTable[c]
+	out = out[:len(in)]
+	for i := range in {
+		out[i] ^= mt[in[i]]
+	}
+}
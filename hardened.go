@@ -0,0 +1,55 @@
+package tss
+
+import (
+	"encoding/binary"
+	"runtime"
+)
+
+// WithHardenedLookups makes CreateShares and RecoverSecret read the field
+// tables with masked scans of whole tables, so the memory access pattern does
+// not depend on the secret bytes and cache timing tells nothing about them.
+// It only matters for the bytes not handled by a vector kernel, on platforms
+// without one; it is slower, but selectable per Splitter or Combiner unlike
+// the constanttime build tag.
+func WithHardenedLookups() Option {
+	return func(c *config) {
+		c.hardened = true
+	}
+}
+
+// mulAdd is mulAddHardened when hardened, mulAddSlice otherwise
+func mulAdd(c byte, in []byte, out []byte, hardened bool) {
+	if hardened {
+		mulAddHardened(c, in, out)
+		return
+	}
+	mulAddSlice(c, in, out)
+}
+
+// mulAddHardened is mulAddSlice reading the product table of c with
+// scanLookup
+func mulAddHardened(c byte, in []byte, out []byte) {
+	if c == 0 {
+		return
+	}
+	done := mulAddVector(c, in, out)
+	table := &mulTable[c]
+	out = out[:len(in)]
+	for i := done; i < len(in); i++ {
+		out[i] ^= scanLookup(table, in[i])
+	}
+}
+
+// scanLookup returns table[index] reading every word of the table in the
+// same order whatever index, the wanted word being kept with a mask
+func scanLookup(table *[256]byte, index byte) byte {
+	word := uint64(index >> 3)
+	var r uint64
+	for w := uint64(0); w < 32; w++ {
+		// mask is all ones when w == word, without a branch
+		mask := ((w ^ word) - 1) >> 63 * 0xffffffffffffffff
+		r |= binary.LittleEndian.Uint64(table[w*8:]) & mask
+	}
+	runtime.KeepAlive(table)
+	return byte(r >> (8 * uint64(index&7)))
+}
@@ -0,0 +1,35 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestHardenedLookups(t *testing.T) {
+	for c := 0; c < 256; c++ {
+		for b := 0; b < 256; b++ {
+			if got := scanLookup(&mulTable[c], byte(b)); got != mulTable[c][b] {
+				failNow(t, fmt.Errorf("lookup %d*%d = %d", c, b, got))
+			}
+		}
+		in := randomBytes(77)
+		out := randomBytes(77)
+		want := append([]byte{}, out...)
+		mulAddSlice(byte(c), in, want)
+		mulAddHardened(byte(c), in, out)
+		if !bytes.Equal(out, want) {
+			failNow(t, fmt.Errorf("c=%d: mismatch", c))
+		}
+	}
+	secret := randomBytes(100)
+	splitter := NewSplitter(WithHardenedLookups())
+	shares, err := splitter.Split(secret, 5, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	recovered, err := NewCombiner(WithHardenedLookups()).Combine(shares[1:4])
+	if err != nil || !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("secret mismatch %v", err))
+	}
+}
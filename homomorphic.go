@@ -0,0 +1,94 @@
+package tss
+
+import "crypto/subtle"
+
+var (
+	ErrIndexMismatch = validationError("share sets have different indexes")
+)
+
+// AddShareSets returns shares of the XOR of the secrets of a and b, which
+// must hold shares of the same indexes and secret size. Addition in GF(256)
+// is XOR, so the sum of the shares at an index is the share of the sum of
+// the polynomials; the result needs as many shares as the larger threshold
+// of a and b. It is the building block of proactive refresh, joint
+// randomness and simple multiparty computations: nobody learns the secrets
+// added. The shares are returned in the order of a.
+func AddShareSets(a ShareSet, b ShareSet) (ShareSet, error) {
+	cfg := newConfig(nil)
+	size, err := checkShares(a, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := checkShares(b, cfg); err != nil {
+		return nil, err
+	}
+	if len(a) != len(b) {
+		return nil, ErrIndexMismatch
+	}
+	var at [256]int
+	for i, s := range b {
+		at[s[0]] = i + 1
+	}
+	sum := make(ShareSet, len(a))
+	for i, s := range a {
+		j := at[s[0]]
+		if j == 0 {
+			return nil, ErrIndexMismatch
+		}
+		if len(b[j-1]) != size+1 {
+			return nil, &ShareError{Position: j, Index: s[0], Reason: ErrShareSize}
+		}
+		sum[i] = make(Share, size+1)
+		sum[i][0] = s[0]
+		subtle.XORBytes(sum[i][1:], s[1:], b[j-1][1:])
+	}
+	return sum, nil
+}
+
+// Add returns the share of the XOR of the secrets of s and t, shares of the
+// same index and size from two splits. A set of such shares needs as many
+// shares as the larger threshold of the splits.
+func (s Share) Add(t Share) (Share, error) {
+	if !s.Valid() || !t.Valid() {
+		return nil, ErrInvalidShare
+	}
+	if s[0] != t[0] {
+		return nil, ErrIndexMismatch
+	}
+	if len(s) != len(t) {
+		return nil, ErrShareSize
+	}
+	sum := make(Share, len(s))
+	sum[0] = s[0]
+	subtle.XORBytes(sum[1:], s[1:], t[1:])
+	return sum, nil
+}
+
+// Scale returns the share of the secret with every byte multiplied by c in
+// GF(256). Scaling every share of a set by the same non zero c gives a set
+// recovering to the scaled secret with the same threshold, scaling by zero
+// gives shares of zeros.
+func (s Share) Scale(c byte) Share {
+	if len(s) == 0 {
+		return nil
+	}
+	scaled := make(Share, len(s))
+	scaled[0] = s[0]
+	mulAddSlice(c, s[1:], scaled[1:])
+	return scaled
+}
+
+// Affine returns the share of c*secret+d, d being a public value as long as
+// the secret: adding the same constant to every share adds it to the
+// constant term of the polynomials, hence to the secret only.
+func (s Share) Affine(c byte, d []byte) (Share, error) {
+	if !s.Valid() {
+		return nil, ErrInvalidShare
+	}
+	if len(d) != s.SecretBytes() {
+		return nil, ErrShareSize
+	}
+	affine := s.Scale(c)
+	subtle.XORBytes(affine[1:], affine[1:], d)
+	return affine, nil
+}
@@ -0,0 +1,82 @@
+package tss
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestAddShareSets(t *testing.T) {
+	secretA, secretB := randomBytes(32), randomBytes(32)
+	a, err := CreateShares(secretA, 5, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	b, err := CreateShares(secretB, 5, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	sum, err := AddShareSets(a, ShareSet{b[4], b[2], b[0], b[3], b[1]})
+	if err != nil {
+		failNow(t, err)
+	}
+	want := make([]byte, 32)
+	subtle.XORBytes(want, secretA, secretB)
+	testRecover(t, want, sum.Subset(1, 3, 4))
+}
+
+func TestAddShareSetsErrors(t *testing.T) {
+	a, _ := CreateShares(randomBytes(32), 5, 3)
+	b, _ := CreateSharesWithIndexes(randomBytes(32), []byte{1, 2, 3, 4, 6}, 3)
+	if _, err := AddShareSets(a, b); err != ErrIndexMismatch {
+		failNow(t, expected(ErrIndexMismatch, err))
+	}
+	if _, err := AddShareSets(a, a[:4]); err != ErrIndexMismatch {
+		failNow(t, expected(ErrIndexMismatch, err))
+	}
+	c, _ := CreateShares(randomBytes(16), 5, 3)
+	if _, err := AddShareSets(a, c); !errors.Is(err, ErrShareSize) {
+		failNow(t, expected(ErrShareSize, err))
+	}
+	if _, err := AddShareSets(a, ShareSet{a[0], a[0]}); !errors.Is(err, ErrDuplicateShare) {
+		failNow(t, fmt.Errorf("duplicate shares accepted: %v", err))
+	}
+	if sum, _ := AddShareSets(a, a); !bytes.Equal(sum[0][1:], make([]byte, 32)) {
+		failNow(t, fmt.Errorf("a+a is not zero"))
+	}
+}
+
+func TestShareLinearOperations(t *testing.T) {
+	secretA, secretB, d := randomBytes(32), randomBytes(32), randomBytes(32)
+	a, _ := CreateShares(secretA, 5, 3)
+	b, _ := CreateShares(secretB, 5, 3)
+	var c byte = 0x57
+	sum, scaled, affine := make(ShareSet, 5), make(ShareSet, 5), make(ShareSet, 5)
+	for i := range a {
+		var err error
+		if sum[i], err = a[i].Add(b[i]); err != nil {
+			failNow(t, err)
+		}
+		scaled[i] = a[i].Scale(c)
+		if affine[i], err = a[i].Affine(c, d); err != nil {
+			failNow(t, err)
+		}
+	}
+	wantSum, wantScaled, wantAffine := make([]byte, 32), make([]byte, 32), make([]byte, 32)
+	subtle.XORBytes(wantSum, secretA, secretB)
+	for i := range secretA {
+		wantScaled[i] = mul(c, secretA[i])
+		wantAffine[i] = wantScaled[i] ^ d[i]
+	}
+	testRecover(t, wantSum, sum.Subset(0, 2, 4))
+	testRecover(t, wantScaled, scaled.Subset(1, 2, 3))
+	testRecover(t, wantAffine, affine.Subset(4, 0, 1))
+	if _, err := a[0].Add(b[1]); err != ErrIndexMismatch {
+		failNow(t, expected(ErrIndexMismatch, err))
+	}
+	if _, err := a[0].Affine(c, d[1:]); err != ErrShareSize {
+		failNow(t, expected(ErrShareSize, err))
+	}
+}
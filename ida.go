@@ -0,0 +1,22 @@
+package tss
+
+// CreateIDAShares disperses data with Rabin's information dispersal
+// algorithm: any k of the sharesCount shares rebuild it, and every share is
+// about 1/k of the data size. The shares are NOT confidential, fewer than k
+// of them leak part of the data: use it for availability of data that is
+// public or already encrypted, CreateShares otherwise.
+func CreateIDAShares(data []byte, sharesCount int, k int, opts ...Option) (ShareSet, error) {
+	if k > sharesCount || k < MinThreshold {
+		return nil, ErrInvalidThreshold
+	}
+	return createPacked(data, sharesCount, k, k, newConfig(opts))
+}
+
+// RecoverIDAData rebuilds data dispersed by CreateIDAShares from at least k
+// shares
+func RecoverIDAData(shares ShareSet, k int, opts ...Option) ([]byte, error) {
+	if k < MinThreshold || k > MaxShares {
+		return nil, ErrInvalidThreshold
+	}
+	return recoverPacked(shares, k, k, newConfig(opts))
+}
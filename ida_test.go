@@ -0,0 +1,31 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestIDA(t *testing.T) {
+	data := randomBytes(10000)
+	shares, err := CreateIDAShares(data, 6, 4)
+	if err != nil {
+		failNow(t, err)
+	}
+	if len(shares[0]) != len(data)/4+2 {
+		failNow(t, fmt.Errorf("share size %d", len(shares[0])))
+	}
+	got, err := RecoverIDAData(ShareSet{shares[5], shares[1], shares[3], shares[0]}, 4)
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(got, data) {
+		failNow(t, fmt.Errorf("data mismatch"))
+	}
+	if _, err := RecoverIDAData(shares[:3], 4); err != ErrTooFewShares {
+		failNow(t, expected(ErrTooFewShares, err))
+	}
+	if _, err := CreateIDAShares(data, 3, 4); err != ErrInvalidThreshold {
+		failNow(t, expected(ErrInvalidThreshold, err))
+	}
+}
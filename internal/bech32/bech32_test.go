@@ -0,0 +1,41 @@
+package bech32
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestVectors decodes the valid strings of BIP 173
+func TestVectors(t *testing.T) {
+	for _, s := range []string{
+		"A12UEL5L",
+		"a12uel5l",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+	} {
+		if _, _, err := Decode(s); err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+	}
+	for _, s := range []string{"A1G7SGD8", "a12UEL5L", "x1b4n0q5v", "1pzry9x0s0muk"} {
+		if _, _, err := Decode(s); err == nil {
+			t.Fatalf("%s decoded", s)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte{0xa5, 0x01, 0xff}, 30)
+	s, err := Encode("age-secret-key-", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hrp, decoded, err := Decode(strings.ToUpper(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hrp != "age-secret-key-" || !bytes.Equal(decoded, data) {
+		t.Fatalf("decoded %q %x", hrp, decoded)
+	}
+}
@@ -0,0 +1,162 @@
+// Package p256 holds the P-256 group operations the threshold protocols of
+// the module share: points, Lagrange coefficients, verification shares and
+// discrete logarithm equality proofs. Points are the constant time ones of
+// filippo.io/nistec.
+package p256
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"math/big"
+
+	"filippo.io/nistec"
+)
+
+// ScalarBytes is the size of an encoded scalar
+const ScalarBytes = 32
+
+var (
+	// Order is the order of the group
+	Order, _ = new(big.Int).SetString("ffffffff00000000ffffffffffffffffbce6faada7179e84f3b9cac2fc632551", 16)
+)
+
+// Point is a point of P-256
+type Point struct {
+	p *nistec.P256Point
+}
+
+// Decode decodes a compressed point, ok being false for an invalid one
+func Decode(b []byte) (p Point, ok bool) {
+	if len(b) != 1+ScalarBytes {
+		return Point{}, false
+	}
+	q, err := nistec.NewP256Point().SetBytes(b)
+	if err != nil {
+		return Point{}, false
+	}
+	return Point{q}, true
+}
+
+// Bytes returns the compressed encoding of p
+func (p Point) Bytes() []byte {
+	return p.p.BytesCompressed()
+}
+
+func (p Point) Add(q Point) Point {
+	return Point{nistec.NewP256Point().Add(p.p, q.p)}
+}
+
+// Mul returns k times p, k being reduced modulo the order
+func (p Point) Mul(k *big.Int) Point {
+	q, err := nistec.NewP256Point().ScalarMult(p.p, Scalar(new(big.Int).Mod(k, Order)))
+	if err != nil {
+		panic(err)
+	}
+	return Point{q}
+}
+
+func (p Point) Equal(q Point) bool {
+	return p.p.Equal(q.p) == 1
+}
+
+// Base returns the generator
+func Base() Point {
+	return Point{nistec.NewP256Point().SetGenerator()}
+}
+
+// BaseMul returns k times the generator, k being reduced modulo the order
+func BaseMul(k *big.Int) Point {
+	q, err := nistec.NewP256Point().ScalarBaseMult(Scalar(new(big.Int).Mod(k, Order)))
+	if err != nil {
+		panic(err)
+	}
+	return Point{q}
+}
+
+// Scalar returns the big endian encoding of k
+func Scalar(k *big.Int) []byte {
+	return k.FillBytes(make([]byte, ScalarBytes))
+}
+
+// DecodeScalar decodes a scalar, ok being false unless it is below the order
+func DecodeScalar(b []byte) (k *big.Int, ok bool) {
+	k = new(big.Int).SetBytes(b)
+	return k, len(b) == ScalarBytes && k.Cmp(Order) < 0
+}
+
+// Lagrange returns the Lagrange coefficient at zero of the i-th of the
+// distinct ids, the product of x_j / (x_j - x_i)
+func Lagrange(i int, ids []*big.Int) *big.Int {
+	num, den, t := big.NewInt(1), big.NewInt(1), new(big.Int)
+	for j, x := range ids {
+		if j == i {
+			continue
+		}
+		num.Mul(num, x)
+		den.Mul(den, t.Sub(x, ids[i]))
+	}
+	den.Mod(den, Order)
+	num.Mul(num, den.ModInverse(den, Order))
+	return num.Mod(num, Order)
+}
+
+// VerificationShare returns the sum of index^k times the k-th of the Feldman
+// commitments, the public share index of the committed polynomial
+func VerificationShare(commitments [][]byte, index byte) (p Point, ok bool) {
+	if len(commitments) == 0 {
+		return Point{}, false
+	}
+	for k := len(commitments) - 1; k >= 0; k-- {
+		c, ok := Decode(commitments[k])
+		if !ok {
+			return Point{}, false
+		}
+		if k == len(commitments)-1 {
+			p = c
+			continue
+		}
+		p = p.Mul(big.NewInt(int64(index))).Add(c)
+	}
+	return p, true
+}
+
+// challenge hashes the points of a proof into a scalar
+func challenge(domain string, points ...Point) *big.Int {
+	h := sha256.New()
+	h.Write([]byte(domain))
+	for _, p := range points {
+		h.Write(p.Bytes())
+	}
+	c := new(big.Int).SetBytes(h.Sum(nil))
+	return c.Mod(c, Order)
+}
+
+// ProveDLEQ proves without revealing alpha that h1 = alpha*g1 and
+// h2 = alpha*g2, with the Chaum-Pedersen protocol made non interactive, the
+// challenge being bound to domain
+func ProveDLEQ(domain string, g1, h1, g2, h2 Point, alpha *big.Int, random io.Reader) ([]byte, error) {
+	w, err := rand.Int(random, Order)
+	if err != nil {
+		return nil, err
+	}
+	c := challenge(domain, g1, h1, g2, h2, g1.Mul(w), g2.Mul(w))
+	// r = w - alpha*c
+	r := new(big.Int).Mul(alpha, c)
+	r.Sub(w, r)
+	r.Mod(r, Order)
+	w.SetInt64(0)
+	return append(Scalar(c), Scalar(r)...), nil
+}
+
+// VerifyDLEQ checks a proof of ProveDLEQ
+func VerifyDLEQ(domain string, g1, h1, g2, h2 Point, proof []byte) bool {
+	if len(proof) != 2*ScalarBytes {
+		return false
+	}
+	c := new(big.Int).SetBytes(proof[:ScalarBytes])
+	r := new(big.Int).SetBytes(proof[ScalarBytes:])
+	a1 := g1.Mul(r).Add(h1.Mul(c))
+	a2 := g2.Mul(r).Add(h2.Mul(c))
+	return challenge(domain, g1, h1, g2, h2, a1, a2).Cmp(c) == 0
+}
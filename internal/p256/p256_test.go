@@ -0,0 +1,49 @@
+package p256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestDLEQ(t *testing.T) {
+	alpha, g2 := big.NewInt(12345), BaseMul(big.NewInt(777))
+	h1, h2 := BaseMul(alpha), g2.Mul(alpha)
+	proof, err := ProveDLEQ("test", Base(), h1, g2, h2, alpha, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyDLEQ("test", Base(), h1, g2, h2, proof) {
+		t.Fatal("valid proof rejected")
+	}
+	if VerifyDLEQ("other", Base(), h1, g2, h2, proof) || VerifyDLEQ("test", Base(), h1, g2, h1, proof) {
+		t.Fatal("invalid proof accepted")
+	}
+}
+
+func TestLagrange(t *testing.T) {
+	// f(x) = 5 + 3x, sampled at 2 and 7
+	ids := []*big.Int{big.NewInt(2), big.NewInt(7)}
+	sum := new(big.Int)
+	for i, y := range []int64{11, 26} {
+		sum.Add(sum, new(big.Int).Mul(Lagrange(i, ids), big.NewInt(y)))
+	}
+	if sum.Mod(sum, Order).Int64() != 5 {
+		t.Fatalf("f(0) = %v", sum)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	p := BaseMul(big.NewInt(42))
+	q, ok := Decode(p.Bytes())
+	if !ok || !q.Equal(p) || !q.Equal(Base().Mul(big.NewInt(42))) {
+		t.Fatal("decoding failed")
+	}
+	if _, ok := Decode([]byte{0}); ok {
+		t.Fatal("identity accepted")
+	}
+	if _, ok := Decode(append([]byte{2}, bytes.Repeat([]byte{0xff}, 32)...)); ok {
+		t.Fatal("non canonical encoding accepted")
+	}
+}
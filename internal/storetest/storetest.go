@@ -0,0 +1,73 @@
+// Package storetest checks implementations of tss.ShareStore, for the tests
+// of the packages providing them.
+package storetest
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/antik10ud/go-tss"
+)
+
+// Run puts shares in store under the labels a, b and c, recovers their
+// secret from two of them read back and checks Delete, the errors of missing
+// shares and that the invalid labels are refused. A store that is also a
+// tss.BlobStore must keep blobs which are no shares.
+func Run(t *testing.T, store tss.ShareStore, invalid ...string) {
+	t.Helper()
+	secret := make([]byte, 32)
+	rand.Read(secret)
+	shares, err := tss.CreateShares(secret, 3, 2, tss.WithConsumeSecret(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range shares {
+		if err := store.Put(string(rune('a'+i)), s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a, err := store.Get("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := store.Get("c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered, err := tss.RecoverSecret(tss.ShareSet{a, c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(recovered) != string(secret) {
+		t.Fatal("recovered secret mismatch")
+	}
+	if err := store.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("a"); !errors.Is(err, tss.ErrShareNotFound) {
+		t.Fatal(err)
+	}
+	if err := store.Delete("a"); !errors.Is(err, tss.ErrShareNotFound) {
+		t.Fatal(err)
+	}
+	for _, label := range invalid {
+		if err := store.Put(label, shares[0]); !errors.Is(err, tss.ErrInvalidLabel) {
+			t.Fatalf("label %q: %v", label, err)
+		}
+	}
+	blobs, ok := store.(tss.BlobStore)
+	if !ok {
+		return
+	}
+	blob := append([]byte{0}, secret...)
+	if err := blobs.PutBlob("b", blob); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := blobs.GetBlob("b"); err != nil || string(got) != string(blob) {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("b"); !errors.Is(err, tss.ErrInvalidShare) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,11 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/antik10ud/go-tss"
+)
+
+func TestDirStore(t *testing.T) {
+	Run(t, tss.DirStore{Dir: t.TempDir()}, "../a", ".a")
+}
@@ -0,0 +1,153 @@
+package tss
+
+import (
+	"crypto/subtle"
+	"io"
+)
+
+var (
+	ErrUnknownParty          = validationError("unknown party")
+	ErrDuplicateContribution = validationError("duplicate contribution")
+	ErrContributionsMissing  = validationError("contributions missing")
+	ErrAlreadyDealt          = validationError("contribution already dealt")
+)
+
+// JointParty is one of the parties of a joint random sharing: every party
+// deals a sharing of a random secret of its own and sums the shares it gets
+// at its index, one from every party. The sums are shares of the XOR of all
+// the random secrets, a secret nobody ever saw and that stays unknown while
+// one party kept its contribution to itself, so no trusted dealer is needed
+// to generate a key. The parties must send the shares over private
+// authenticated channels.
+type JointParty struct {
+	index      byte
+	threshold  int
+	secretSize int
+	parties    []byte
+	random     io.Reader
+	dealt      bool
+	received   [256]bool
+	count      int
+	// share is the running sum of the contributions received
+	share Share
+}
+
+// NewJointParty sets up the party at index among the parties at indexes, for
+// a joint secret of secretBytes bytes recovered by threshold of them.
+// WithRand sets the source of the random contribution.
+func NewJointParty(index byte, indexes []byte, threshold int, secretBytes int, opts ...Option) (*JointParty, error) {
+	cfg := newConfig(opts)
+	if secretBytes < cfg.minSecretBytes {
+		return nil, ErrSecretTooShort
+	}
+	if secretBytes > cfg.secretLimit() {
+		return nil, ErrSecretTooLarge
+	}
+	if len(indexes) < MinShares {
+		return nil, ErrTooFewShares
+	}
+	if len(indexes) > cfg.sharesLimit() {
+		return nil, ErrTooManyShares
+	}
+	if threshold > len(indexes) || threshold < cfg.minThreshold() {
+		return nil, ErrInvalidThreshold
+	}
+	var seen [256]bool
+	for _, x := range indexes {
+		if x == 0 {
+			return nil, ErrInvalidShareIndex
+		}
+		if seen[x] {
+			return nil, ErrDuplicateShare
+		}
+		seen[x] = true
+	}
+	if !seen[index] {
+		return nil, ErrUnknownParty
+	}
+	p := &JointParty{
+		index:      index,
+		threshold:  threshold,
+		secretSize: secretBytes,
+		parties:    append([]byte{}, indexes...),
+		random:     cfg.random(),
+		share:      make(Share, secretBytes+1),
+	}
+	p.share[0] = index
+	return p, nil
+}
+
+// Deal draws the contribution of the party and returns its shares, one per
+// party in the order of the indexes, the party keeping none of the random
+// secret. Every share goes to the Receive of the party at its index,
+// including the party's own.
+func (p *JointParty) Deal() (ShareSet, error) {
+	if p.dealt {
+		return nil, ErrAlreadyDealt
+	}
+	secret := make([]byte, p.secretSize)
+	defer erase(secret)
+	if _, err := io.ReadFull(p.random, secret); err != nil {
+		return nil, err
+	}
+	dealer, err := NewDealer(secret, p.threshold, WithRand(p.random))
+	if err != nil {
+		return nil, err
+	}
+	defer dealer.Destroy()
+	shares, err := dealer.Shares(p.parties...)
+	if err != nil {
+		return nil, err
+	}
+	p.dealt = true
+	return shares, nil
+}
+
+// Receive adds the share dealt by the party at index from, which must be at
+// the index of this party
+func (p *JointParty) Receive(from byte, share Share) error {
+	if !p.isParty(from) {
+		return ErrUnknownParty
+	}
+	if p.received[from] {
+		return ErrDuplicateContribution
+	}
+	if share.Index() != p.index {
+		return ErrIndexMismatch
+	}
+	if len(share) != len(p.share) {
+		return ErrShareSize
+	}
+	subtle.XORBytes(p.share[1:], p.share[1:], share[1:])
+	p.received[from] = true
+	p.count++
+	return nil
+}
+
+func (p *JointParty) isParty(x byte) bool {
+	for _, party := range p.parties {
+		if party == x {
+			return true
+		}
+	}
+	return false
+}
+
+// Complete reports whether the contributions of all the parties arrived
+func (p *JointParty) Complete() bool {
+	return p.count == len(p.parties)
+}
+
+// Share returns the share of the joint secret held by the party, once all
+// the contributions arrived
+func (p *JointParty) Share() (Share, error) {
+	if !p.Complete() {
+		return nil, ErrContributionsMissing
+	}
+	return append(Share{}, p.share...), nil
+}
+
+// Destroy erases the contributions received
+func (p *JointParty) Destroy() {
+	erase(p.share)
+}
@@ -0,0 +1,75 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestJointParties(t *testing.T) {
+	indexes := []byte{3, 7, 9, 12}
+	parties := make([]*JointParty, len(indexes))
+	for i, x := range indexes {
+		var err error
+		if parties[i], err = NewJointParty(x, indexes, 3, 32); err != nil {
+			failNow(t, err)
+		}
+	}
+	for _, dealer := range parties {
+		shares, err := dealer.Deal()
+		if err != nil {
+			failNow(t, err)
+		}
+		for j, p := range parties {
+			if err := p.Receive(dealer.index, shares[j]); err != nil {
+				failNow(t, err)
+			}
+		}
+	}
+	shares := make(ShareSet, len(parties))
+	for i, p := range parties {
+		var err error
+		if shares[i], err = p.Share(); err != nil {
+			failNow(t, err)
+		}
+	}
+	secret, err := RecoverSecret(shares[:3])
+	if err != nil {
+		failNow(t, err)
+	}
+	other, err := RecoverSecret(shares[1:])
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(secret, other) || bytes.Equal(secret, make([]byte, 32)) {
+		failNow(t, fmt.Errorf("inconsistent joint secret"))
+	}
+}
+
+func TestJointPartyErrors(t *testing.T) {
+	indexes := []byte{1, 2, 3}
+	if _, err := NewJointParty(4, indexes, 2, 16); err != ErrUnknownParty {
+		failNow(t, expected(ErrUnknownParty, err))
+	}
+	p, _ := NewJointParty(1, indexes, 2, 16)
+	q, _ := NewJointParty(2, indexes, 2, 16)
+	shares, _ := q.Deal()
+	if _, err := q.Deal(); err != ErrAlreadyDealt {
+		failNow(t, expected(ErrAlreadyDealt, err))
+	}
+	if err := p.Receive(2, shares[1]); err != ErrIndexMismatch {
+		failNow(t, expected(ErrIndexMismatch, err))
+	}
+	if err := p.Receive(5, shares[0]); err != ErrUnknownParty {
+		failNow(t, expected(ErrUnknownParty, err))
+	}
+	if err := p.Receive(2, shares[0]); err != nil {
+		failNow(t, err)
+	}
+	if err := p.Receive(2, shares[0]); err != ErrDuplicateContribution {
+		failNow(t, expected(ErrDuplicateContribution, err))
+	}
+	if _, err := p.Share(); err != ErrContributionsMissing {
+		failNow(t, expected(ErrContributionsMissing, err))
+	}
+}
@@ -0,0 +1,188 @@
+package keystore
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/antik10ud/go-tss"
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	// credMaxBlobBytes is the largest blob of a generic credential
+	credMaxBlobBytes = 5 * 512
+	errorNotFound    = 1168
+)
+
+var (
+	advapi32       = syscall.NewLazyDLL("advapi32.dll")
+	crypt32        = syscall.NewLazyDLL("crypt32.dll")
+	kernel32       = syscall.NewLazyDLL("kernel32.dll")
+	procCredWrite  = advapi32.NewProc("CredWriteW")
+	procCredRead   = advapi32.NewProc("CredReadW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+	procProtect    = crypt32.NewProc("CryptProtectData")
+	procUnprotect  = crypt32.NewProc("CryptUnprotectData")
+	procLocalFree  = kernel32.NewProc("LocalFree")
+)
+
+// credential is the CREDENTIALW structure
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// dataBlob is the DATA_BLOB structure of DPAPI
+type dataBlob struct {
+	Size uint32
+	Data *byte
+}
+
+func newBlob(b []byte) *dataBlob {
+	if len(b) == 0 {
+		return &dataBlob{}
+	}
+	return &dataBlob{Size: uint32(len(b)), Data: &b[0]}
+}
+
+// CredentialManager is a tss.ShareStore keeping shares as generic
+// credentials of the Windows Credential Manager, protected by the logon
+// session of the user, named service/label
+type CredentialManager struct {
+	Service string
+}
+
+// New returns the key store of the platform, the Credential Manager on
+// Windows
+func New(service string) (tss.ShareStore, error) {
+	return CredentialManager{Service: service}, nil
+}
+
+// target returns the credential name of label
+func (c CredentialManager) target(label string) (*uint16, error) {
+	if !validName(c.Service) || !validName(label) {
+		return nil, tss.ErrInvalidLabel
+	}
+	return syscall.UTF16PtrFromString(c.Service + "/" + label)
+}
+
+// Put writes the credential of label, replacing an existing one
+func (c CredentialManager) Put(label string, share tss.Share) error {
+	if !share.Valid() {
+		return tss.ErrInvalidShare
+	}
+	if len(share) > credMaxBlobBytes {
+		return tss.ErrShareSize
+	}
+	target, err := c.target(label)
+	if err != nil {
+		return err
+	}
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(share)),
+		CredentialBlob:     &share[0],
+		Persist:            credPersistLocalMachine,
+	}
+	if r, _, err := procCredWrite.Call(uintptr(unsafe.Pointer(&cred)), 0); r == 0 {
+		return err
+	}
+	return nil
+}
+
+// Get reads the credential of label
+func (c CredentialManager) Get(label string) (tss.Share, error) {
+	target, err := c.target(label)
+	if err != nil {
+		return nil, err
+	}
+	var cred *credential
+	if r, _, err := procCredRead.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred))); r == 0 {
+		if err == syscall.Errno(errorNotFound) {
+			return nil, tss.ErrShareNotFound
+		}
+		return nil, err
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	blob := unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)
+	share := append(tss.Share{}, blob...)
+	tss.Wipe(blob)
+	if !share.Valid() {
+		return nil, tss.ErrInvalidShare
+	}
+	return share, nil
+}
+
+// Delete removes the credential of label
+func (c CredentialManager) Delete(label string) error {
+	target, err := c.target(label)
+	if err != nil {
+		return err
+	}
+	if r, _, err := procCredDelete.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0); r == 0 {
+		if err == syscall.Errno(errorNotFound) {
+			return tss.ErrShareNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// DPAPI is a tss.ShareStore encrypting each share with DPAPI for the current
+// user before writing it to Store as a blob, for shares kept in files. The
+// label is the DPAPI entropy, so a share moved to another label no longer
+// decrypts.
+type DPAPI struct {
+	Store tss.BlobStore
+}
+
+// Put encrypts the share and writes it to the underlying store
+func (d DPAPI) Put(label string, share tss.Share) error {
+	if !share.Valid() {
+		return tss.ErrInvalidShare
+	}
+	var out dataBlob
+	if r, _, err := procProtect.Call(uintptr(unsafe.Pointer(newBlob(share))), 0, uintptr(unsafe.Pointer(newBlob([]byte(label)))), 0, 0, 0, uintptr(unsafe.Pointer(&out))); r == 0 {
+		return err
+	}
+	defer procLocalFree.Call(uintptr(unsafe.Pointer(out.Data)))
+	return d.Store.PutBlob(label, append([]byte{}, unsafe.Slice(out.Data, out.Size)...))
+}
+
+// Get reads the share of label from the underlying store and decrypts it
+func (d DPAPI) Get(label string) (tss.Share, error) {
+	encrypted, err := d.Store.GetBlob(label)
+	if err != nil {
+		return nil, err
+	}
+	var out dataBlob
+	if r, _, err := procUnprotect.Call(uintptr(unsafe.Pointer(newBlob(encrypted))), 0, uintptr(unsafe.Pointer(newBlob([]byte(label)))), 0, 0, 0, uintptr(unsafe.Pointer(&out))); r == 0 {
+		return nil, err
+	}
+	defer procLocalFree.Call(uintptr(unsafe.Pointer(out.Data)))
+	plaintext := unsafe.Slice(out.Data, out.Size)
+	share := append(tss.Share{}, plaintext...)
+	tss.Wipe(plaintext)
+	if !share.Valid() {
+		return nil, tss.ErrInvalidShare
+	}
+	return share, nil
+}
+
+// Delete removes the share of label from the underlying store
+func (d DPAPI) Delete(label string) error {
+	return d.Store.Delete(label)
+}
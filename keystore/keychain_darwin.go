@@ -0,0 +1,96 @@
+package keystore
+
+import (
+	"fmt"
+
+	"github.com/antik10ud/go-tss"
+)
+
+// errSecItemNotFound is the exit code of security for a missing item
+const errSecItemNotFound = 44
+
+// Keychain is a tss.ShareStore keeping shares as generic passwords of the
+// login keychain, through the security tool. Shares are added through its
+// standard input, so they never show on a command line.
+type Keychain struct {
+	Service string
+}
+
+// New returns the key store of the platform, the Keychain on macOS
+func New(service string) (tss.ShareStore, error) {
+	return Keychain{Service: service}, nil
+}
+
+// item returns the security options selecting the item of label
+func (k Keychain) item(label string) ([]string, error) {
+	if !validName(k.Service) || !validName(label) {
+		return nil, tss.ErrInvalidLabel
+	}
+	return []string{"-s", k.Service, "-a", label}, nil
+}
+
+// Put adds or updates the item of label. The interactive mode of security
+// not reporting failed commands, the item is read back.
+func (k Keychain) Put(label string, share tss.Share) error {
+	if _, err := k.item(label); err != nil {
+		return err
+	}
+	text, err := encodeShare(share)
+	if err != nil {
+		return err
+	}
+	defer tss.Wipe(text)
+	stdin := fmt.Appendf(nil, "add-generic-password -U -s \"%s\" -a \"%s\" -w %s\n", k.Service, label, text)
+	defer tss.Wipe(stdin)
+	if _, _, err := run(stdin, "security", "-i"); err != nil {
+		return err
+	}
+	stored, err := k.Get(label)
+	if err != nil {
+		return err
+	}
+	defer tss.Wipe(stored)
+	if string(stored) != string(share) {
+		return fmt.Errorf("keychain item %q not updated", label)
+	}
+	return nil
+}
+
+// Get reads the item of label
+func (k Keychain) Get(label string) (tss.Share, error) {
+	item, err := k.item(label)
+	if err != nil {
+		return nil, err
+	}
+	out, code, err := run(nil, "security", append(append([]string{"find-generic-password"}, item...), "-w")...)
+	defer tss.Wipe(out)
+	if err != nil {
+		return nil, err
+	}
+	switch code {
+	case 0:
+		return decodeShare(out)
+	case errSecItemNotFound:
+		return nil, tss.ErrShareNotFound
+	}
+	return nil, toolError("security", code)
+}
+
+// Delete removes the item of label
+func (k Keychain) Delete(label string) error {
+	item, err := k.item(label)
+	if err != nil {
+		return err
+	}
+	_, code, err := run(nil, "security", append([]string{"delete-generic-password"}, item...)...)
+	if err != nil {
+		return err
+	}
+	switch code {
+	case 0:
+		return nil
+	case errSecItemNotFound:
+		return tss.ErrShareNotFound
+	}
+	return toolError("security", code)
+}
@@ -0,0 +1,73 @@
+// Package keystore keeps tss shares in the key store of the desktop: the
+// Keychain on macOS, the Secret Service on Linux and the Credential Manager,
+// or DPAPI, on Windows. Each store holds shares under a service name and
+// the label of the share.
+package keystore
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/antik10ud/go-tss"
+)
+
+var (
+	ErrUnsupported = fmt.Errorf("no key store on this platform: %w", tss.ErrValidation)
+)
+
+// command runs the key store tools, tests replace it
+var command = exec.Command
+
+// validName checks service names and labels, which the tools take on a
+// command line
+func validName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, "\"\\\n\r\x00")
+}
+
+// encodeShare writes share as the hex text the tools store
+func encodeShare(share tss.Share) ([]byte, error) {
+	if !share.Valid() {
+		return nil, tss.ErrInvalidShare
+	}
+	out := make([]byte, hex.EncodedLen(len(share)))
+	hex.Encode(out, share)
+	return out, nil
+}
+
+// decodeShare reads the hex text written by encodeShare
+func decodeShare(text []byte) (tss.Share, error) {
+	text = bytes.TrimSpace(text)
+	share := make(tss.Share, hex.DecodedLen(len(text)))
+	if _, err := hex.Decode(share, text); err != nil || !share.Valid() {
+		tss.Wipe(share)
+		return nil, tss.ErrInvalidShare
+	}
+	return share, nil
+}
+
+// run runs a tool with stdin, returning its output and exit code. A tool
+// failing to start is an error, exiting with a code is not.
+func run(stdin []byte, name string, args ...string) ([]byte, int, error) {
+	cmd := command(name, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		return out, exit.ExitCode(), nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return out, 0, nil
+}
+
+// toolError reports a tool exiting with an unexpected code
+func toolError(name string, code int) error {
+	return fmt.Errorf("%s exited with code %d", name, code)
+}
@@ -0,0 +1,12 @@
+//go:build !(darwin || linux || windows)
+
+package keystore
+
+import (
+	"github.com/antik10ud/go-tss"
+)
+
+// New returns the key store of the platform, there is none on this platform
+func New(service string) (tss.ShareStore, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,39 @@
+package keystore
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/antik10ud/go-tss"
+)
+
+func TestEncodeShare(t *testing.T) {
+	share := make(tss.Share, 33)
+	rand.Read(share)
+	share[0] = 7
+	text, err := encodeShare(share)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := decodeShare(append(text, '\n'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(decoded) != string(share) {
+		t.Fatal("decoded share mismatch")
+	}
+	if _, err := decodeShare([]byte("zz")); err != tss.ErrInvalidShare {
+		t.Fatal(err)
+	}
+	if _, err := encodeShare(tss.Share{0, 1}); err != tss.ErrInvalidShare {
+		t.Fatal(err)
+	}
+}
+
+func TestValidName(t *testing.T) {
+	for name, valid := range map[string]bool{"custodian 1": true, "": false, `a"b`: false, "a\nb": false} {
+		if validName(name) != valid {
+			t.Fatalf("validName(%q) != %v", name, valid)
+		}
+	}
+}
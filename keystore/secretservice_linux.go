@@ -0,0 +1,87 @@
+package keystore
+
+import (
+	"github.com/antik10ud/go-tss"
+)
+
+// SecretService is a tss.ShareStore keeping shares in the default collection
+// of the Secret Service, GNOME Keyring or KWallet, through secret-tool. The
+// share is written to its standard input, never to a command line.
+type SecretService struct {
+	Service string
+}
+
+// New returns the key store of the platform, the Secret Service on Linux
+func New(service string) (tss.ShareStore, error) {
+	return SecretService{Service: service}, nil
+}
+
+// attributes returns the secret-tool attributes of the item of label
+func (s SecretService) attributes(label string) ([]string, error) {
+	if !validName(s.Service) || !validName(label) {
+		return nil, tss.ErrInvalidLabel
+	}
+	return []string{"service", s.Service, "account", label}, nil
+}
+
+// Put stores the item of label, replacing an existing one
+func (s SecretService) Put(label string, share tss.Share) error {
+	attrs, err := s.attributes(label)
+	if err != nil {
+		return err
+	}
+	text, err := encodeShare(share)
+	if err != nil {
+		return err
+	}
+	defer tss.Wipe(text)
+	args := append([]string{"store", "--label=" + s.Service + " " + label}, attrs...)
+	_, code, err := run(text, "secret-tool", args...)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return toolError("secret-tool", code)
+	}
+	return nil
+}
+
+// Get looks the item of label up, secret-tool exits with 1 and no output
+// when there is none
+func (s SecretService) Get(label string) (tss.Share, error) {
+	attrs, err := s.attributes(label)
+	if err != nil {
+		return nil, err
+	}
+	out, code, err := run(nil, "secret-tool", append([]string{"lookup"}, attrs...)...)
+	defer tss.Wipe(out)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case code == 0 && len(out) > 0:
+		return decodeShare(out)
+	case code == 0 || code == 1:
+		return nil, tss.ErrShareNotFound
+	}
+	return nil, toolError("secret-tool", code)
+}
+
+// Delete clears the item of label, secret-tool not telling a missing one
+// apart it is looked up first
+func (s SecretService) Delete(label string) error {
+	share, err := s.Get(label)
+	if err != nil {
+		return err
+	}
+	tss.Wipe(share)
+	attrs, _ := s.attributes(label)
+	_, code, err := run(nil, "secret-tool", append([]string{"clear"}, attrs...)...)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return toolError("secret-tool", code)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/antik10ud/go-tss"
+)
+
+// TestHelperProcess is a secret-tool keeping items in the directory of
+// TSS_SECRET_TOOL_DIR, run by the tests in place of the real one
+func TestHelperProcess(t *testing.T) {
+	dir := os.Getenv("TSS_SECRET_TOOL_DIR")
+	if dir == "" {
+		return
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	// --, secret-tool, command, then attribute pairs
+	args = args[2:]
+	item := filepath.Join(dir, hex.EncodeToString([]byte(fmt.Sprint(args[len(args)-4:]))))
+	switch args[0] {
+	case "store":
+		secret, _ := io.ReadAll(os.Stdin)
+		os.WriteFile(item, secret, 0600)
+	case "lookup":
+		secret, err := os.ReadFile(item)
+		if err != nil {
+			os.Exit(1)
+		}
+		os.Stdout.Write(secret)
+	case "clear":
+		os.Remove(item)
+	}
+	os.Exit(0)
+}
+
+func TestSecretService(t *testing.T) {
+	dir := t.TempDir()
+	command = func(name string, args ...string) *exec.Cmd {
+		cmd := exec.Command(os.Args[0], append([]string{"-test.run=TestHelperProcess", "--", name}, args...)...)
+		cmd.Env = append(os.Environ(), "TSS_SECRET_TOOL_DIR="+dir)
+		return cmd
+	}
+	defer func() {
+		command = exec.Command
+	}()
+	store, err := New("go-tss test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := []byte("a secret kept by custodians")
+	shares, err := tss.CreateShares(secret, 3, 2, tss.WithConsumeSecret(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range shares {
+		if err := store.Put(fmt.Sprint("custodian ", i), s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a, err := store.Get("custodian 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := store.Get("custodian 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered, err := tss.RecoverSecret(tss.ShareSet{a, b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(recovered) != string(secret) {
+		t.Fatal("recovered secret mismatch")
+	}
+	if err := store.Delete("custodian 0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("custodian 0"); err != tss.ErrShareNotFound {
+		t.Fatal(err)
+	}
+	if err := store.Delete("custodian 0"); err != tss.ErrShareNotFound {
+		t.Fatal(err)
+	}
+	if err := store.Put(`custodian "3"`, shares[0]); err != tss.ErrInvalidLabel {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,80 @@
+package kms
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/antik10ud/go-tss"
+	"github.com/antik10ud/go-tss/internal/sigv4"
+)
+
+// awsMaxPlaintextBytes is the largest plaintext of TrentService.Encrypt
+const awsMaxPlaintextBytes = 4096
+
+// AWSCredentials are the credentials of the requests signed with AWS
+// Signature Version 4, SessionToken being set for temporary ones
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// AWSKey is a KeyWrapper over an AWS KMS symmetric key, encrypting at most
+// 4096 bytes. The additional data goes in the encryption context, under the
+// key tss-label, so it shows in CloudTrail.
+type AWSKey struct {
+	// KeyID is the id, ARN or alias of the key
+	KeyID  string
+	Region string
+	// Credentials returns the credentials of a request
+	Credentials func(ctx context.Context) (AWSCredentials, error)
+	// Endpoint replaces https://kms.<region>.amazonaws.com/ when set
+	Endpoint string
+	Client   *http.Client
+}
+
+// Encrypt calls TrentService.Encrypt
+func (k AWSKey) Encrypt(ctx context.Context, plaintext []byte, aad []byte) ([]byte, error) {
+	if len(plaintext) > awsMaxPlaintextBytes {
+		return nil, fmt.Errorf("plaintext of %d bytes: %w", len(plaintext), tss.ErrLimits)
+	}
+	var out struct{ CiphertextBlob []byte }
+	err := k.call(ctx, "Encrypt", map[string]any{
+		"KeyId":             k.KeyID,
+		"Plaintext":         plaintext,
+		"EncryptionContext": map[string]string{"tss-label": string(aad)},
+	}, &out)
+	return out.CiphertextBlob, err
+}
+
+// Decrypt calls TrentService.Decrypt
+func (k AWSKey) Decrypt(ctx context.Context, ciphertext []byte, aad []byte) ([]byte, error) {
+	var out struct{ Plaintext []byte }
+	err := k.call(ctx, "Decrypt", map[string]any{
+		"KeyId":             k.KeyID,
+		"CiphertextBlob":    ciphertext,
+		"EncryptionContext": map[string]string{"tss-label": string(aad)},
+	}, &out)
+	return out.Plaintext, err
+}
+
+// call sends a signed request of the KMS JSON protocol
+func (k AWSKey) call(ctx context.Context, action string, in any, out any) error {
+	endpoint := k.Endpoint
+	if endpoint == "" {
+		endpoint = "https://kms." + k.Region + ".amazonaws.com/"
+	}
+	req, body, err := newJSONRequest(ctx, endpoint, "application/x-amz-json-1.1", in)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	creds, err := k.Credentials(ctx)
+	if err != nil {
+		return err
+	}
+	sigv4.Sign(req, body, sigv4.Credentials(creds), k.Region, "kms", time.Now())
+	return doJSON(k.Client, req, out)
+}
@@ -0,0 +1,63 @@
+package kms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/antik10ud/go-tss"
+)
+
+// awsServer emulates the Encrypt and Decrypt actions of AWS KMS, the
+// ciphertext being the plaintext, the key id and the context
+func awsServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") || r.Header.Get("X-Amz-Security-Token") != "session" {
+			http.Error(w, `{"__type":"UnrecognizedClientException"}`, http.StatusBadRequest)
+			return
+		}
+		var in struct {
+			KeyId             string
+			Plaintext         []byte
+			CiphertextBlob    []byte
+			EncryptionContext map[string]string
+		}
+		json.NewDecoder(r.Body).Decode(&in)
+		tag := in.KeyId + "|" + in.EncryptionContext["tss-label"] + "|"
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.Encrypt":
+			json.NewEncoder(w).Encode(map[string][]byte{"CiphertextBlob": append([]byte(tag), in.Plaintext...)})
+		case "TrentService.Decrypt":
+			if !strings.HasPrefix(string(in.CiphertextBlob), tag) {
+				http.Error(w, `{"__type":"InvalidCiphertextException"}`, http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string][]byte{"Plaintext": in.CiphertextBlob[len(tag):]})
+		}
+	}))
+}
+
+func TestAWSKey(t *testing.T) {
+	server := awsServer(t)
+	defer server.Close()
+	key := AWSKey{
+		KeyID:  "alias/tss",
+		Region: "eu-west-1",
+		Credentials: func(context.Context) (AWSCredentials, error) {
+			return AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"}, nil
+		},
+		Endpoint: server.URL,
+	}
+	testStore(t, key)
+	if _, err := key.Encrypt(context.Background(), make([]byte, 4097), nil); !errors.Is(err, tss.ErrLimits) {
+		t.Fatal(err)
+	}
+	var apiErr *APIError
+	if _, err := key.Decrypt(context.Background(), []byte("garbage"), nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatal(err)
+	}
+}
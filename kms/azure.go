@@ -0,0 +1,91 @@
+package kms
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/antik10ud/go-tss"
+)
+
+const (
+	// azureAPIVersion is the Key Vault REST API version of the requests
+	azureAPIVersion = "7.4"
+	// azureMaxPlaintextBytes is the largest plaintext RSA-OAEP-256 wraps with
+	// a 2048 bit key, the smallest Key Vault RSA key
+	azureMaxPlaintextBytes = 2048/8 - 2*32 - 2
+)
+
+// AzureKey is a KeyWrapper over an Azure Key Vault RSA key, wrapping with
+// RSA-OAEP-256. Key Vault having no additional data, it is prefixed to the
+// plaintext, along its length, and checked on unwrap. Both take at most 190
+// bytes, what a 2048 bit key wraps.
+type AzureKey struct {
+	// KeyID is the URL of the key, https://<vault>.vault.azure.net/keys/<name>
+	// optionally followed by /<version>
+	KeyID string
+	// Token returns an access token for https://vault.azure.net
+	Token  TokenSource
+	Client *http.Client
+}
+
+// Encrypt calls wrapkey
+func (k AzureKey) Encrypt(ctx context.Context, plaintext []byte, aad []byte) ([]byte, error) {
+	if len(aad) > 255 {
+		return nil, fmt.Errorf("additional data of %d bytes: %w", len(aad), tss.ErrLimits)
+	}
+	data := append(append([]byte{byte(len(aad))}, aad...), plaintext...)
+	defer tss.Wipe(data)
+	if len(data) > azureMaxPlaintextBytes {
+		return nil, fmt.Errorf("plaintext and additional data of %d bytes: %w", len(data), tss.ErrLimits)
+	}
+	var out struct {
+		Value string `json:"value"`
+	}
+	if err := k.call(ctx, "wrapkey", base64.RawURLEncoding.EncodeToString(data), &out); err != nil {
+		return nil, err
+	}
+	return decodeBase64URL(out.Value)
+}
+
+// Decrypt calls unwrapkey
+func (k AzureKey) Decrypt(ctx context.Context, ciphertext []byte, aad []byte) ([]byte, error) {
+	var out struct {
+		Value string `json:"value"`
+	}
+	if err := k.call(ctx, "unwrapkey", base64.RawURLEncoding.EncodeToString(ciphertext), &out); err != nil {
+		return nil, err
+	}
+	data, err := decodeBase64URL(out.Value)
+	if err != nil {
+		return nil, err
+	}
+	defer tss.Wipe(data)
+	if len(data) < 1+len(aad) || int(data[0]) != len(aad) || string(data[1:1+len(aad)]) != string(aad) {
+		return nil, fmt.Errorf("additional data mismatch: %w", tss.ErrIntegrity)
+	}
+	return append([]byte{}, data[1+len(aad):]...), nil
+}
+
+func (k AzureKey) call(ctx context.Context, operation string, value string, out any) error {
+	url := strings.TrimSuffix(k.KeyID, "/") + "/" + operation + "?api-version=" + azureAPIVersion
+	req, _, err := newJSONRequest(ctx, url, "application/json", map[string]string{"alg": "RSA-OAEP-256", "value": value})
+	if err != nil {
+		return err
+	}
+	if err := bearer(ctx, req, k.Token); err != nil {
+		return err
+	}
+	return doJSON(k.Client, req, out)
+}
+
+// decodeBase64URL decodes the base64url values of Key Vault, padded or not
+func decodeBase64URL(s string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, fmt.Errorf("kms: decoding response: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,71 @@
+package kms
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/antik10ud/go-tss"
+)
+
+// azureServer emulates wrapkey and unwrapkey of Key Vault with an RSA key
+func azureServer(t *testing.T) *httptest.Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in struct {
+			Alg   string `json:"alg"`
+			Value string `json:"value"`
+		}
+		json.NewDecoder(r.Body).Decode(&in)
+		value, err := base64.RawURLEncoding.DecodeString(in.Value)
+		if r.Header.Get("Authorization") != "Bearer token" || r.URL.Query().Get("api-version") != azureAPIVersion || in.Alg != "RSA-OAEP-256" || err != nil {
+			http.Error(w, `{"error":{"code":"BadParameter"}}`, http.StatusBadRequest)
+			return
+		}
+		var out []byte
+		switch r.URL.Path {
+		case "/keys/tss/wrapkey":
+			out, err = rsa.EncryptOAEP(sha256.New(), rand.Reader, &key.PublicKey, value, nil)
+		case "/keys/tss/unwrapkey":
+			out, err = rsa.DecryptOAEP(sha256.New(), nil, key, value, nil)
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			http.Error(w, `{"error":{"code":"BadParameter"}}`, http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"kid": "tss", "value": base64.RawURLEncoding.EncodeToString(out)})
+	}))
+}
+
+func TestAzureKey(t *testing.T) {
+	server := azureServer(t)
+	defer server.Close()
+	key := AzureKey{
+		KeyID: server.URL + "/keys/tss",
+		Token: func(context.Context) (string, error) { return "token", nil },
+	}
+	testStore(t, key)
+	wrapped, err := key.Encrypt(context.Background(), []byte("share"), []byte("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := key.Decrypt(context.Background(), wrapped, []byte("b")); !errors.Is(err, tss.ErrIntegrity) {
+		t.Fatal(err)
+	}
+	if _, err := key.Encrypt(context.Background(), make([]byte, 189), []byte("a")); !errors.Is(err, tss.ErrLimits) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,59 @@
+package kms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const gcpKeyName = "projects/p/locations/global/keyRings/r/cryptoKeys/tss"
+
+// gcpServer emulates cryptoKeys.encrypt and decrypt of Cloud KMS, the
+// ciphertext being the additional data and the plaintext
+func gcpServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, `{"error":{"code":401}}`, http.StatusUnauthorized)
+			return
+		}
+		var in struct {
+			Plaintext  []byte `json:"plaintext"`
+			Ciphertext []byte `json:"ciphertext"`
+			AAD        []byte `json:"additionalAuthenticatedData"`
+		}
+		json.NewDecoder(r.Body).Decode(&in)
+		tag := string(in.AAD) + "|"
+		switch r.URL.Path {
+		case "/v1/" + gcpKeyName + ":encrypt":
+			json.NewEncoder(w).Encode(map[string][]byte{"ciphertext": append([]byte(tag), in.Plaintext...)})
+		case "/v1/" + gcpKeyName + ":decrypt":
+			if !strings.HasPrefix(string(in.Ciphertext), tag) {
+				http.Error(w, `{"error":{"code":400}}`, http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string][]byte{"plaintext": in.Ciphertext[len(tag):]})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestGCPKey(t *testing.T) {
+	server := gcpServer(t)
+	defer server.Close()
+	key := GCPKey{
+		Name:     gcpKeyName,
+		Token:    func(context.Context) (string, error) { return "token", nil },
+		Endpoint: server.URL + "/v1/",
+	}
+	testStore(t, key)
+	key.Token = func(context.Context) (string, error) { return "expired", nil }
+	var apiErr *APIError
+	if _, err := key.Encrypt(context.Background(), []byte("share"), nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatal(err)
+	}
+}
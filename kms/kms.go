@@ -0,0 +1,204 @@
+// Package kms wraps tss shares under cloud KMS keys before storage, AWS KMS,
+// Google Cloud KMS or Azure Key Vault, so every share read goes through the
+// access control and audit log of the cloud. It talks to the REST API of
+// each service with net/http, callers supply credentials.
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/antik10ud/go-tss"
+)
+
+const (
+	// maxResponseBytes bounds the responses read from the services
+	maxResponseBytes = 1 << 20
+	// blobVersion is the version of the blobs of a Store
+	blobVersion = 1
+	// blobHeaderBytes is the size of the header of a blob: version and size
+	// of the wrapped data key
+	blobHeaderBytes = 3
+	// dataKeyBytes is the size of the AES-256 data key of a share
+	dataKeyBytes = 32
+)
+
+// KeyWrapper encrypts and decrypts with a cloud key. The additional data is
+// bound to the ciphertext: decrypting with other additional data fails.
+// Store only gives it data keys, within the plaintext limits of every
+// service.
+type KeyWrapper interface {
+	Encrypt(ctx context.Context, plaintext []byte, aad []byte) ([]byte, error)
+	Decrypt(ctx context.Context, ciphertext []byte, aad []byte) ([]byte, error)
+}
+
+// TokenSource returns the OAuth 2.0 access token of a request
+type TokenSource func(ctx context.Context) (string, error)
+
+// Store is a tss.ShareStore encrypting each share with AES-256-GCM under a
+// fresh data key, wrapped with Key, before writing both to Store as a blob.
+// The label is the additional data, so a wrapped share moved to another
+// label no longer unwraps. Requests use Context, or context.Background when
+// it is nil.
+type Store struct {
+	Key     KeyWrapper
+	Store   tss.BlobStore
+	Context context.Context
+}
+
+func (s Store) context() context.Context {
+	if s.Context == nil {
+		return context.Background()
+	}
+	return s.Context
+}
+
+// Put encrypts the share, wraps its data key and writes both to the
+// underlying store
+func (s Store) Put(label string, share tss.Share) error {
+	if !share.Valid() {
+		return tss.ErrInvalidShare
+	}
+	key := make([]byte, dataKeyBytes)
+	defer tss.Wipe(key)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	wrapped, err := s.Key.Encrypt(s.context(), key, []byte(label))
+	if err != nil {
+		return err
+	}
+	if len(wrapped) > 0xffff {
+		return fmt.Errorf("wrapped data key of %d bytes: %w", len(wrapped), tss.ErrLimits)
+	}
+	blob := binary.BigEndian.AppendUint16([]byte{blobVersion}, uint16(len(wrapped)))
+	blob = append(blob, wrapped...)
+	aead, err := dataAEAD(key)
+	if err != nil {
+		return err
+	}
+	// the data key is fresh for every share, so the nonce is zero
+	blob = aead.Seal(blob, make([]byte, aead.NonceSize()), share, append(blob[:len(blob):len(blob)], label...))
+	return s.Store.PutBlob(label, blob)
+}
+
+// Get reads the blob of label from the underlying store, unwraps its data
+// key and decrypts the share
+func (s Store) Get(label string) (tss.Share, error) {
+	blob, err := s.Store.GetBlob(label)
+	if err != nil {
+		return nil, err
+	}
+	if len(blob) < blobHeaderBytes {
+		return nil, tss.ErrInvalidCiphertext
+	}
+	if blob[0] != blobVersion {
+		return nil, tss.ErrUnsupportedVersion
+	}
+	n := blobHeaderBytes + int(binary.BigEndian.Uint16(blob[1:]))
+	if len(blob) < n {
+		return nil, tss.ErrInvalidCiphertext
+	}
+	key, err := s.Key.Decrypt(s.context(), blob[blobHeaderBytes:n], []byte(label))
+	if err != nil {
+		return nil, fmt.Errorf("unwrapping share %q: %w", label, err)
+	}
+	defer tss.Wipe(key)
+	if len(key) != dataKeyBytes {
+		return nil, fmt.Errorf("unwrapping share %q: %w", label, tss.ErrDecryption)
+	}
+	aead, err := dataAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, make([]byte, aead.NonceSize()), blob[n:], append(blob[:n:n], label...))
+	if err != nil {
+		return nil, fmt.Errorf("decrypting share %q: %w", label, tss.ErrDecryption)
+	}
+	share := tss.Share(plaintext)
+	if !share.Valid() {
+		tss.Wipe(plaintext)
+		return nil, tss.ErrInvalidShare
+	}
+	return share, nil
+}
+
+// dataAEAD returns the AES-256-GCM AEAD of a data key
+func dataAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Delete removes the wrapped share of label from the underlying store
+func (s Store) Delete(label string) error {
+	return s.Store.Delete(label)
+}
+
+// APIError is an error response of a service
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("kms: HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// doJSON sends req, built by the caller with a JSON body, and decodes the
+// JSON response into out
+func doJSON(client *http.Client, req *http.Request, out any) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{StatusCode: resp.StatusCode, Body: string(bytes.TrimSpace(body))}
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("kms: decoding response: %w", err)
+	}
+	return nil
+}
+
+// newJSONRequest builds a POST of the JSON of in to url
+func newJSONRequest(ctx context.Context, url string, contentType string, in any) (*http.Request, []byte, error) {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return req, body, nil
+}
+
+// bearer sets the Authorization header of req with a token of tokens
+func bearer(ctx context.Context, req *http.Request, tokens TokenSource) error {
+	token, err := tokens(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
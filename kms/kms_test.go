@@ -0,0 +1,31 @@
+package kms
+
+import (
+	"testing"
+
+	"github.com/antik10ud/go-tss"
+	"github.com/antik10ud/go-tss/internal/storetest"
+)
+
+// testStore runs the store checks on a Store over key, and checks shares do
+// not unwrap under another label
+func testStore(t *testing.T, key KeyWrapper) {
+	store := Store{Key: key, Store: tss.DirStore{Dir: t.TempDir()}}
+	storetest.Run(t, store)
+	wrapped, _ := store.Store.GetBlob("b")
+	store.Store.PutBlob("c", wrapped)
+	if _, err := store.Get("c"); err == nil {
+		t.Fatal("share unwrapped under another label")
+	}
+	// only the data key is wrapped, whatever the size of the share
+	large, err := tss.CreateShares(make([]byte, tss.MaxSecretBytes), 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("large", large[0]); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := store.Get("large"); err != nil || string(s) != string(large[0]) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,305 @@
+// Package kube distributes tss shares as Kubernetes Secrets, one Secret per
+// share, in separate namespaces or clusters, and recovers them with service
+// accounts whose RBAC each grant a single Secret, so no one account holds a
+// quorum of the shares of the cluster root credentials. It talks to the
+// API server with net/http.
+package kube
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/antik10ud/go-tss"
+)
+
+const (
+	// ShareKey is the key of the share in the data of a Secret
+	ShareKey = "share"
+	// IndexAnnotation is the annotation of a Secret holding its share index
+	IndexAnnotation = "tss.antik10ud.github.com/share-index"
+	// serviceAccountDir holds the credentials of the pod service account
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount/"
+	// maxResponseBytes bounds the responses read from the API server
+	maxResponseBytes = 1 << 20
+)
+
+var (
+	ErrNotInCluster = fmt.Errorf("not running in a Kubernetes pod: %w", tss.ErrValidation)
+)
+
+// secretName matches the DNS subdomain names of Secrets
+var secretName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]{0,251}[a-z0-9])?$`)
+
+// Cluster is the API server of a cluster and the credentials of a service
+// account on it
+type Cluster struct {
+	// Server is the URL of the API server, https://10.0.0.1:443
+	Server string
+	// Token returns the bearer token of the service account
+	Token func(ctx context.Context) (string, error)
+	// Client carries the TLS configuration trusting the cluster CA
+	Client *http.Client
+}
+
+// InCluster returns the cluster of the pod running the process, with the
+// credentials of its service account. The token is read again on every
+// request, as the kubelet rotates it.
+func InCluster() (Cluster, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return Cluster{}, ErrNotInCluster
+	}
+	ca, err := os.ReadFile(serviceAccountDir + "ca.crt")
+	if err != nil {
+		return Cluster{}, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return Cluster{}, ErrNotInCluster
+	}
+	return Cluster{
+		Server: "https://" + net.JoinHostPort(host, port),
+		Token: func(context.Context) (string, error) {
+			token, err := os.ReadFile(serviceAccountDir + "token")
+			return string(bytes.TrimSpace(token)), err
+		},
+		Client: &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}},
+	}, nil
+}
+
+// APIError is an error response of the API server
+type APIError struct {
+	StatusCode int
+	Reason     string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("kube: HTTP %d %s: %s", e.StatusCode, e.Reason, e.Message)
+}
+
+// do sends a request with the JSON of in, when not nil, and decodes the
+// response into out, when not nil. A 404 is tss.ErrShareNotFound, other
+// statuses *APIError.
+func (c Cluster) do(ctx context.Context, method string, path string, in any, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+		defer tss.Wipe(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.Server+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.Token != nil {
+		token, err := c.Token(ctx)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return err
+	}
+	defer tss.Wipe(data)
+	if resp.StatusCode == http.StatusNotFound {
+		return tss.ErrShareNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		var status struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		}
+		json.Unmarshal(data, &status)
+		return &APIError{StatusCode: resp.StatusCode, Reason: status.Reason, Message: status.Message}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// Secrets is a tss.ShareStore keeping each share in the Secret named Prefix
+// followed by the label, in Namespace of Cluster
+type Secrets struct {
+	Cluster   Cluster
+	Namespace string
+	Prefix    string
+	Context   context.Context
+}
+
+// secret is the part of a Secret the store reads and writes
+type secret struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   metadata          `json:"metadata"`
+	Type       string            `json:"type,omitempty"`
+	Data       map[string][]byte `json:"data"`
+}
+
+type metadata struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+func (s Secrets) context() context.Context {
+	if s.Context == nil {
+		return context.Background()
+	}
+	return s.Context
+}
+
+// collection returns the API path of the Secrets of the namespace
+func (s Secrets) collection() string {
+	return "/api/v1/namespaces/" + s.Namespace + "/secrets"
+}
+
+// path returns the API path of the Secret of label
+func (s Secrets) path(label string) (string, error) {
+	name := s.Prefix + label
+	if label == "" || !secretName.MatchString(name) || !secretName.MatchString(s.Namespace) {
+		return "", tss.ErrInvalidLabel
+	}
+	return s.collection() + "/" + name, nil
+}
+
+// Put creates the Secret of label, or replaces the one already there
+func (s Secrets) Put(label string, share tss.Share) error {
+	if !share.Valid() {
+		return tss.ErrInvalidShare
+	}
+	path, err := s.path(label)
+	if err != nil {
+		return err
+	}
+	obj := secret{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata: metadata{
+			Name:        s.Prefix + label,
+			Namespace:   s.Namespace,
+			Labels:      map[string]string{"app.kubernetes.io/managed-by": "go-tss"},
+			Annotations: map[string]string{IndexAnnotation: strconv.Itoa(int(share.Index()))},
+		},
+		Type: "Opaque",
+		Data: map[string][]byte{ShareKey: share},
+	}
+	err = s.Cluster.do(s.context(), http.MethodPost, s.collection(), obj, nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		err = s.Cluster.do(s.context(), http.MethodPut, path, obj, nil)
+	}
+	return err
+}
+
+// Get reads the share of the Secret of label
+func (s Secrets) Get(label string) (tss.Share, error) {
+	path, err := s.path(label)
+	if err != nil {
+		return nil, err
+	}
+	var obj secret
+	if err := s.Cluster.do(s.context(), http.MethodGet, path, nil, &obj); err != nil {
+		return nil, err
+	}
+	share := tss.Share(obj.Data[ShareKey])
+	if !share.Valid() {
+		tss.Wipe(share)
+		return nil, tss.ErrInvalidShare
+	}
+	return share, nil
+}
+
+// Delete deletes the Secret of label
+func (s Secrets) Delete(label string) error {
+	path, err := s.path(label)
+	if err != nil {
+		return err
+	}
+	return s.Cluster.do(s.context(), http.MethodDelete, path, nil, nil)
+}
+
+// Target is the Secret of a share: its label in a store of Secrets, each
+// target usually with the credentials of its own service account
+type Target struct {
+	Secrets Secrets
+	Label   string
+}
+
+// Distribute writes each share to the target at the same position
+func Distribute(shares tss.ShareSet, targets []Target) error {
+	if len(shares) != len(targets) {
+		return fmt.Errorf("%d shares for %d targets: %w", len(shares), len(targets), tss.ErrValidation)
+	}
+	for i, t := range targets {
+		if err := t.Secrets.Put(t.Label, shares[i]); err != nil {
+			return fmt.Errorf("target %s/%s: %w", t.Secrets.Namespace, t.Secrets.Prefix+t.Label, err)
+		}
+	}
+	return nil
+}
+
+// Recover reads the shares of the targets and recovers the secret. Targets
+// whose Secret is missing or not readable by their service account are
+// skipped, the secret is recovered from the others when they meet the
+// threshold of opts; the errors of the skipped targets are returned along
+// a failed recovery.
+func Recover(targets []Target, opts ...tss.Option) ([]byte, error) {
+	var shares tss.ShareSet
+	defer func() {
+		for _, s := range shares {
+			tss.Wipe(s)
+		}
+	}()
+	var skipped []error
+	for _, t := range targets {
+		share, err := t.Secrets.Get(t.Label)
+		var apiErr *APIError
+		switch {
+		case err == nil:
+			shares = append(shares, share)
+			continue
+		case err == tss.ErrShareNotFound:
+		case errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden):
+		default:
+			return nil, fmt.Errorf("target %s/%s: %w", t.Secrets.Namespace, t.Secrets.Prefix+t.Label, err)
+		}
+		skipped = append(skipped, fmt.Errorf("target %s/%s: %w", t.Secrets.Namespace, t.Secrets.Prefix+t.Label, err))
+	}
+	if len(shares) == 0 {
+		return nil, errors.Join(append([]error{tss.ErrTooFewShares}, skipped...)...)
+	}
+	secret, err := tss.RecoverSecret(shares, opts...)
+	if err != nil {
+		return nil, errors.Join(append([]error{err}, skipped...)...)
+	}
+	return secret, nil
+}
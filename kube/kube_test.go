@@ -0,0 +1,127 @@
+package kube
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/antik10ud/go-tss"
+)
+
+// apiServer emulates the Secrets API of a cluster, each token granting the
+// Secrets of one namespace
+func apiServer(grants map[string]string) *httptest.Server {
+	var mu sync.Mutex
+	secrets := map[string][]byte{}
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		status := func(code int, reason string) {
+			w.WriteHeader(code)
+			json.NewEncoder(w).Encode(map[string]any{"kind": "Status", "code": code, "reason": reason, "message": reason})
+		}
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/namespaces/"), "/")
+		if len(parts) < 2 || parts[1] != "secrets" {
+			status(http.StatusNotFound, "NotFound")
+			return
+		}
+		if grants[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")] != parts[0] {
+			status(http.StatusForbidden, "Forbidden")
+			return
+		}
+		var obj secret
+		if r.Method == http.MethodPost || r.Method == http.MethodPut {
+			json.NewDecoder(r.Body).Decode(&obj)
+		}
+		key := parts[0] + "/" + obj.Metadata.Name
+		if len(parts) == 3 {
+			key = parts[0] + "/" + parts[2]
+		}
+		data, ok := secrets[key]
+		switch {
+		case r.Method == http.MethodPost && ok:
+			status(http.StatusConflict, "AlreadyExists")
+		case r.Method == http.MethodPost || r.Method == http.MethodPut:
+			secrets[key], _ = json.Marshal(obj)
+			w.Write(secrets[key])
+		case !ok:
+			status(http.StatusNotFound, "NotFound")
+		case r.Method == http.MethodGet:
+			w.Write(data)
+		case r.Method == http.MethodDelete:
+			delete(secrets, key)
+			status(http.StatusOK, "Success")
+		}
+	}))
+}
+
+func target(server *httptest.Server, token string, namespace string) Target {
+	return Target{
+		Secrets: Secrets{
+			Cluster: Cluster{
+				Server: server.URL,
+				Token:  func(context.Context) (string, error) { return token, nil },
+				Client: server.Client(),
+			},
+			Namespace: namespace,
+			Prefix:    "root-ca-",
+		},
+		Label: "share",
+	}
+}
+
+func TestDistribute(t *testing.T) {
+	east := apiServer(map[string]string{"a": "custodian-a", "b": "custodian-b"})
+	defer east.Close()
+	west := apiServer(map[string]string{"c": "custodian-c"})
+	defer west.Close()
+	targets := []Target{target(east, "a", "custodian-a"), target(east, "b", "custodian-b"), target(west, "c", "custodian-c")}
+	secret := make([]byte, 32)
+	rand.Read(secret)
+	shares, err := tss.CreateShares(secret, 3, 2, tss.WithConsumeSecret(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Distribute(shares, targets); err != nil {
+		t.Fatal(err)
+	}
+	// distributing again replaces the Secrets
+	if err := Distribute(shares, targets); err != nil {
+		t.Fatal(err)
+	}
+	// the service account of b lost its grant
+	targets[1].Secrets.Cluster.Token = func(context.Context) (string, error) { return "a", nil }
+	recovered, err := Recover(targets, tss.WithThreshold(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(recovered) != string(secret) {
+		t.Fatal("recovered secret mismatch")
+	}
+	if err := targets[2].Secrets.Delete("share"); err != nil {
+		t.Fatal(err)
+	}
+	var apiErr *APIError
+	if _, err := Recover(targets, tss.WithThreshold(2)); !errors.Is(err, tss.ErrThresholdNotMet) || !errors.As(err, &apiErr) || !errors.Is(err, tss.ErrShareNotFound) {
+		t.Fatal(err)
+	}
+	if err := Distribute(shares[:2], targets); !errors.Is(err, tss.ErrValidation) {
+		t.Fatal(err)
+	}
+	if err := targets[0].Secrets.Put("Share", shares[0]); err != tss.ErrInvalidLabel {
+		t.Fatal(err)
+	}
+}
+
+func TestInCluster(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	if _, err := InCluster(); err != ErrNotInCluster {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,89 @@
+package kvstore
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/antik10ud/go-tss"
+)
+
+// Consul is a tss.ShareStore keeping each share under the key Prefix
+// followed by the label, through the KV HTTP API of Consul. Tokens are ACL
+// tokens, whose policies may grant each custodian its own key only.
+type Consul struct {
+	// Endpoint is the URL of an agent, https://consul-1:8501
+	Endpoint string
+	Prefix   string
+	// Datacenter selects another datacenter than the one of the agent
+	Datacenter string
+	Token      TokenSource
+	Client     *http.Client
+	Context    context.Context
+}
+
+// do sends a request on the key of label
+func (c Consul) do(method string, label string, query string, body []byte) ([]byte, error) {
+	if !validLabel(label) {
+		return nil, tss.ErrInvalidLabel
+	}
+	ctx := background(c.Context)
+	t, err := token(ctx, c.Token, label)
+	if err != nil {
+		return nil, err
+	}
+	u := strings.TrimSuffix(c.Endpoint, "/") + "/v1/kv/" + url.PathEscape(c.Prefix+label)
+	var params []string
+	if c.Datacenter != "" {
+		params = append(params, "dc="+url.QueryEscape(c.Datacenter))
+	}
+	if query != "" {
+		params = append(params, query)
+	}
+	if len(params) > 0 {
+		u += "?" + strings.Join(params, "&")
+	}
+	return do(ctx, c.Client, method, u, body, "X-Consul-Token", t)
+}
+
+// Put writes the share to its key
+func (c Consul) Put(label string, share tss.Share) error {
+	if !share.Valid() {
+		return tss.ErrInvalidShare
+	}
+	out, err := c.do(http.MethodPut, label, "", share)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(out)) != "true" {
+		return &APIError{StatusCode: http.StatusOK, Body: string(out)}
+	}
+	return nil
+}
+
+// Get reads the share from its key
+func (c Consul) Get(label string) (tss.Share, error) {
+	data, err := c.do(http.MethodGet, label, "raw", nil)
+	if err != nil {
+		return nil, err
+	}
+	share := tss.Share(data)
+	if !share.Valid() {
+		tss.Wipe(data)
+		return nil, tss.ErrInvalidShare
+	}
+	return share, nil
+}
+
+// Delete removes the key of the share. Consul deleting missing keys
+// silently, the key is read first.
+func (c Consul) Delete(label string) error {
+	share, err := c.Get(label)
+	if err != nil {
+		return err
+	}
+	tss.Wipe(share)
+	_, err = c.do(http.MethodDelete, label, "", nil)
+	return err
+}
@@ -0,0 +1,67 @@
+package kvstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/antik10ud/go-tss/internal/storetest"
+)
+
+// consulServer emulates the KV API of Consul, each key under tss/ readable
+// with the token named after it
+func consulServer() *httptest.Server {
+	var mu sync.Mutex
+	kv := map[string][]byte{}
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		key, ok := strings.CutPrefix(r.URL.Path, "/v1/kv/")
+		if !ok || r.URL.Query().Get("dc") != "eu" {
+			http.NotFound(w, r)
+			return
+		}
+		if !strings.HasPrefix(key, "tss/") || r.Header.Get("X-Consul-Token") != "token-"+strings.TrimPrefix(key, "tss/") {
+			http.Error(w, "Permission denied", http.StatusForbidden)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			kv[key], _ = io.ReadAll(r.Body)
+			io.WriteString(w, "true")
+		case http.MethodDelete:
+			delete(kv, key)
+			io.WriteString(w, "true")
+		case http.MethodGet:
+			v, ok := kv[key]
+			if !ok || !r.URL.Query().Has("raw") {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(v)
+		}
+	}))
+}
+
+func TestConsul(t *testing.T) {
+	server := consulServer()
+	defer server.Close()
+	store := Consul{
+		Endpoint:   server.URL,
+		Prefix:     "tss/",
+		Datacenter: "eu",
+		Token:      func(_ context.Context, label string) (string, error) { return "token-" + label, nil },
+		Client:     server.Client(),
+	}
+	storetest.Run(t, store, "a/b")
+	store.Token = func(context.Context, string) (string, error) { return "token-c", nil }
+	var apiErr *APIError
+	if _, err := store.Get("b"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Fatal(err)
+	}
+}
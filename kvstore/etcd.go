@@ -0,0 +1,108 @@
+package kvstore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/antik10ud/go-tss"
+)
+
+// Etcd is a tss.ShareStore keeping each share under the key Prefix followed
+// by the label, through the JSON gateway of the etcd v3 API. Tokens are the
+// ones of /v3/auth/authenticate, see EtcdAuthenticate, whose roles may grant
+// each custodian its own key only.
+type Etcd struct {
+	// Endpoint is the URL of a member, https://etcd-1:2379
+	Endpoint string
+	Prefix   string
+	Token    TokenSource
+	Client   *http.Client
+	Context  context.Context
+}
+
+// EtcdAuthenticate returns the token of the etcd user name
+func EtcdAuthenticate(ctx context.Context, client *http.Client, endpoint string, name string, password string) (string, error) {
+	body, _ := json.Marshal(map[string]string{"name": name, "password": password})
+	data, err := do(ctx, client, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/v3/auth/authenticate", body, "", "")
+	if err != nil {
+		return "", err
+	}
+	var out struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return "", err
+	}
+	return out.Token, nil
+}
+
+// call posts in to the method of the KV service and decodes the response
+// into out
+func (e Etcd) call(label string, method string, in map[string]any, out any) error {
+	if !validLabel(label) {
+		return tss.ErrInvalidLabel
+	}
+	ctx := background(e.Context)
+	t, err := token(ctx, e.Token, label)
+	if err != nil {
+		return err
+	}
+	in["key"] = []byte(e.Prefix + label)
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	defer tss.Wipe(body)
+	data, err := do(ctx, e.Client, http.MethodPost, strings.TrimSuffix(e.Endpoint, "/")+"/v3/kv/"+method, body, "Authorization", t)
+	if err != nil {
+		return err
+	}
+	defer tss.Wipe(data)
+	return json.Unmarshal(data, out)
+}
+
+// Put writes the share to its key
+func (e Etcd) Put(label string, share tss.Share) error {
+	if !share.Valid() {
+		return tss.ErrInvalidShare
+	}
+	var out struct{}
+	return e.call(label, "put", map[string]any{"value": []byte(share)}, &out)
+}
+
+// Get reads the share from its key
+func (e Etcd) Get(label string) (tss.Share, error) {
+	var out struct {
+		Kvs []struct {
+			Value []byte `json:"value"`
+		} `json:"kvs"`
+	}
+	if err := e.call(label, "range", map[string]any{}, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Kvs) == 0 {
+		return nil, tss.ErrShareNotFound
+	}
+	share := tss.Share(out.Kvs[0].Value)
+	if !share.Valid() {
+		tss.Wipe(share)
+		return nil, tss.ErrInvalidShare
+	}
+	return share, nil
+}
+
+// Delete removes the key of the share
+func (e Etcd) Delete(label string) error {
+	var out struct {
+		Deleted string `json:"deleted"`
+	}
+	if err := e.call(label, "deleterange", map[string]any{}, &out); err != nil {
+		return err
+	}
+	if out.Deleted == "" || out.Deleted == "0" {
+		return tss.ErrShareNotFound
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package kvstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/antik10ud/go-tss/internal/storetest"
+)
+
+// etcdServer emulates the JSON gateway of etcd, the token of user alice
+// granting the keys under /tss/
+func etcdServer() *httptest.Server {
+	var mu sync.Mutex
+	kv := map[string][]byte{}
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var in struct {
+			Key      []byte `json:"key"`
+			Value    []byte `json:"value"`
+			Name     string `json:"name"`
+			Password string `json:"password"`
+		}
+		json.NewDecoder(r.Body).Decode(&in)
+		if r.URL.Path == "/v3/auth/authenticate" {
+			if in.Name != "alice" || in.Password != "secret" {
+				http.Error(w, `{"error":"authentication failed"}`, http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"token": "alice-token"})
+			return
+		}
+		if r.Header.Get("Authorization") != "alice-token" || !strings.HasPrefix(string(in.Key), "/tss/") {
+			http.Error(w, `{"error":"permission denied"}`, http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v3/kv/put":
+			kv[string(in.Key)] = in.Value
+			json.NewEncoder(w).Encode(map[string]any{})
+		case "/v3/kv/range":
+			out := map[string]any{}
+			if v, ok := kv[string(in.Key)]; ok {
+				out["kvs"] = []map[string][]byte{{"key": in.Key, "value": v}}
+			}
+			json.NewEncoder(w).Encode(out)
+		case "/v3/kv/deleterange":
+			out := map[string]any{}
+			if _, ok := kv[string(in.Key)]; ok {
+				delete(kv, string(in.Key))
+				out["deleted"] = "1"
+			}
+			json.NewEncoder(w).Encode(out)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestEtcd(t *testing.T) {
+	server := etcdServer()
+	defer server.Close()
+	token, err := EtcdAuthenticate(context.Background(), server.Client(), server.URL, "alice", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := Etcd{
+		Endpoint: server.URL,
+		Prefix:   "/tss/",
+		Token:    func(context.Context, string) (string, error) { return token, nil },
+		Client:   server.Client(),
+	}
+	storetest.Run(t, store, "a/b")
+	store.Prefix = "/other/"
+	var apiErr *APIError
+	if _, err := store.Get("b"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Fatal(err)
+	}
+	if _, err := EtcdAuthenticate(context.Background(), server.Client(), server.URL, "alice", "guess"); !errors.As(err, &apiErr) {
+		t.Fatal(err)
+	}
+}
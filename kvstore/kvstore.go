@@ -0,0 +1,88 @@
+// Package kvstore keeps tss shares in a distributed key value store, etcd or
+// Consul, so clustered systems keep shares on separate nodes and only
+// recover secrets in memory. It talks to the HTTP API of each store with
+// net/http: pass a Client with a TLS configuration for TLS and client
+// certificates, and a Token per label for ACLs scoped to a share.
+package kvstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/antik10ud/go-tss"
+)
+
+// maxResponseBytes bounds the responses read from the stores
+const maxResponseBytes = 1 << 20
+
+// TokenSource returns the ACL token of the requests on the share of label,
+// the empty string for none
+type TokenSource func(ctx context.Context, label string) (string, error)
+
+// APIError is an error response of a store
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("kvstore: HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// validLabel checks a label is a single key segment
+func validLabel(label string) bool {
+	return label != "" && label != "." && label != ".." && !strings.ContainsAny(label, "/?#\x00")
+}
+
+// do sends a request of method to url with body and the token header, and
+// returns the response body of a 2xx status. A 404 is
+// tss.ErrShareNotFound, other statuses *APIError.
+func do(ctx context.Context, client *http.Client, method string, url string, body []byte, header string, token string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set(header, token)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		tss.Wipe(data)
+		return nil, tss.ErrShareNotFound
+	case resp.StatusCode/100 != 2:
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(bytes.TrimSpace(data))}
+	}
+	return data, nil
+}
+
+// token returns the token of label, none without a source
+func token(ctx context.Context, tokens TokenSource, label string) (string, error) {
+	if tokens == nil {
+		return "", nil
+	}
+	return tokens(ctx, label)
+}
+
+// background returns ctx, or context.Background when it is nil
+func background(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
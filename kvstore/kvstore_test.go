@@ -0,0 +1,11 @@
+package kvstore
+
+import "testing"
+
+func TestValidLabel(t *testing.T) {
+	for label, valid := range map[string]bool{"custodian-1": true, "": false, "..": false, "a/b": false, "a?b": false} {
+		if validLabel(label) != valid {
+			t.Fatalf("validLabel(%q) != %v", label, valid)
+		}
+	}
+}
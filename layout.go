@@ -0,0 +1,84 @@
+package tss
+
+// ShareLayout tells where other GF(256) Shamir libraries keep the index of a
+// share. They work over the same field with the secret as constant term, so
+// their shares only differ from ours in layout.
+type ShareLayout int
+
+const (
+	// LayoutIndexFirst puts the index before the share bytes, as this package
+	LayoutIndexFirst ShareLayout = iota
+	// LayoutIndexLast puts the index after the share bytes, as Vault's shamir
+	// package and its forks
+	LayoutIndexLast
+	// LayoutNoIndex keeps the share bytes only, the indexes travel apart
+	LayoutNoIndex
+)
+
+var (
+	ErrInvalidLayout = validationError("invalid share layout")
+)
+
+// MarshalShares writes shares in layout. With LayoutNoIndex the indexes are
+// lost, keep shares.Indexes() along.
+func MarshalShares(shares ShareSet, layout ShareLayout) ([][]byte, error) {
+	parts := make([][]byte, len(shares))
+	for i, s := range shares {
+		if !s.Valid() {
+			return nil, &ShareError{Position: i + 1, Index: s.Index(), Reason: ErrInvalidShare}
+		}
+		switch layout {
+		case LayoutIndexFirst:
+			parts[i] = append([]byte{}, s...)
+		case LayoutIndexLast:
+			parts[i] = append(append([]byte{}, s[1:]...), s[0])
+		case LayoutNoIndex:
+			parts[i] = append([]byte{}, s[1:]...)
+		default:
+			return nil, ErrInvalidLayout
+		}
+	}
+	return parts, nil
+}
+
+// UnmarshalShares reads shares written in layout. With LayoutNoIndex, indexes
+// gives the index of every part, it must be nil otherwise. A malformed part
+// is reported as a *ShareError.
+func UnmarshalShares(parts [][]byte, layout ShareLayout, indexes []byte) (ShareSet, error) {
+	if (layout == LayoutNoIndex) != (indexes != nil) || indexes != nil && len(indexes) != len(parts) {
+		return nil, ErrInvalidIndexes
+	}
+	shares := make(ShareSet, len(parts))
+	for i, p := range parts {
+		var s Share
+		switch layout {
+		case LayoutIndexFirst:
+			s = append(Share{}, p...)
+		case LayoutIndexLast:
+			if len(p) > 0 {
+				s = append(Share{p[len(p)-1]}, p[:len(p)-1]...)
+			}
+		case LayoutNoIndex:
+			s = append(Share{indexes[i]}, p...)
+		default:
+			return nil, ErrInvalidLayout
+		}
+		if !s.Valid() {
+			eraseShares(shares[:i])
+			return nil, &ShareError{Position: i + 1, Index: s.Index(), Reason: ErrInvalidShare}
+		}
+		shares[i] = s
+	}
+	return shares, nil
+}
+
+// RecoverSecretWithLayout is RecoverSecret for parts written in layout, see
+// UnmarshalShares
+func RecoverSecretWithLayout(parts [][]byte, layout ShareLayout, indexes []byte, opts ...Option) ([]byte, error) {
+	shares, err := UnmarshalShares(parts, layout, indexes)
+	if err != nil {
+		return nil, err
+	}
+	defer eraseShares(shares)
+	return RecoverSecret(shares, opts...)
+}
@@ -0,0 +1,35 @@
+package tss
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestShareLayouts(t *testing.T) {
+	secret := randomBytes(20)
+	shares, _ := CreateShares(secret, 4, 3, WithRandomIndexes(), WithConsumeSecret(false))
+	for _, layout := range []ShareLayout{LayoutIndexFirst, LayoutIndexLast, LayoutNoIndex} {
+		parts, err := MarshalShares(shares, layout)
+		if err != nil {
+			failNow(t, err)
+		}
+		var indexes []byte
+		if layout == LayoutNoIndex {
+			indexes = shares.Indexes()
+		}
+		recovered, err := RecoverSecretWithLayout(parts, layout, indexes)
+		if err != nil {
+			failNow(t, err)
+		}
+		if !bytes.Equal(recovered, secret) {
+			failNow(t, ErrIntegrity)
+		}
+	}
+	parts, _ := MarshalShares(shares, LayoutNoIndex)
+	if _, err := UnmarshalShares(parts, LayoutNoIndex, nil); err != ErrInvalidIndexes {
+		failNow(t, expected(ErrInvalidIndexes, err))
+	}
+	if _, err := UnmarshalShares(parts, ShareLayout(7), nil); err != ErrInvalidLayout {
+		failNow(t, expected(ErrInvalidLayout, err))
+	}
+}
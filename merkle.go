@@ -0,0 +1,150 @@
+package tss
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"io"
+)
+
+// MerkleSaltBytes is the size of the random salt of each leaf, it keeps the
+// hashes of short shares from being brute forced out of the proofs
+const MerkleSaltBytes = 32
+
+var (
+	ErrInvalidMerkleProof = validationError("invalid merkle proof")
+)
+
+// MerkleProof proves a share is part of the dealing committed to by a Merkle
+// root, following the tree shape of RFC 6962.
+type MerkleProof struct {
+	// Leaf is the position of the share in the dealing
+	Leaf int
+	// Leaves is the number of shares of the dealing
+	Leaves int
+	// Salt is the salt of the leaf of the share
+	Salt []byte
+	// Path holds the sibling hashes from the leaf up to the root
+	Path [][]byte
+}
+
+// CommitShares computes a Merkle root over shares and an inclusion proof for
+// each of them. Handing every custodian the root and their proof lets them
+// check later their share is unmodified and part of the original dealing.
+func CommitShares(shares ShareSet, opts ...Option) (root []byte, proofs []MerkleProof, err error) {
+	cfg := newConfig(opts)
+	if len(shares) == 0 {
+		return nil, nil, ErrInvalidShare
+	}
+	leaves := make([][]byte, len(shares))
+	proofs = make([]MerkleProof, len(shares))
+	for i, share := range shares {
+		if !share.Valid() {
+			return nil, nil, &ShareError{Position: i + 1, Reason: ErrInvalidShare}
+		}
+		salt := make([]byte, MerkleSaltBytes)
+		if _, err := io.ReadFull(cfg.random(), salt); err != nil {
+			return nil, nil, err
+		}
+		leaves[i] = merkleLeaf(salt, share)
+		proofs[i] = MerkleProof{Leaf: i, Leaves: len(shares), Salt: salt}
+	}
+	root = merkleTree(leaves, 0, proofs)
+	return root, proofs, nil
+}
+
+// merkleTree returns the hash of leaves, the subtree starting at offset, and
+// appends the sibling hashes to the paths of the proofs of its leaves
+func merkleTree(leaves [][]byte, offset int, proofs []MerkleProof) []byte {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	k := merkleSplit(len(leaves))
+	left := merkleTree(leaves[:k], offset, proofs)
+	right := merkleTree(leaves[k:], offset+k, proofs)
+	for i := range leaves {
+		p := &proofs[offset+i]
+		if i < k {
+			p.Path = append(p.Path, right)
+		} else {
+			p.Path = append(p.Path, left)
+		}
+	}
+	return merkleNode(left, right)
+}
+
+// merkleSplit returns the largest power of two below n
+func merkleSplit(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+func merkleLeaf(salt []byte, share Share) []byte {
+	h := sha256.New()
+	h.Write([]byte{0})
+	h.Write(salt)
+	h.Write(share)
+	return h.Sum(nil)
+}
+
+func merkleNode(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{1})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// Verify checks share against root with the proof, it returns
+// ErrShareNotCommitted if the share was modified or is not part of the
+// dealing.
+func (p MerkleProof) Verify(root []byte, share Share) error {
+	if !share.Valid() {
+		return ErrInvalidShare
+	}
+	if p.Leaves < 1 || p.Leaf < 0 || p.Leaf >= p.Leaves || len(p.Salt) != MerkleSaltBytes {
+		return ErrInvalidMerkleProof
+	}
+	hash, path, err := merklePath(merkleLeaf(p.Salt, share), p.Leaf, p.Leaves, p.Path)
+	if err != nil {
+		return err
+	}
+	if len(path) != 0 {
+		return ErrInvalidMerkleProof
+	}
+	if subtle.ConstantTimeCompare(hash, root) != 1 {
+		return ErrShareNotCommitted
+	}
+	return nil
+}
+
+// merklePath folds the hash of the leaf at position leaf of a tree of leaves
+// with the siblings of path, from the bottom up, and returns the hash of the
+// tree and the unused rest of path
+func merklePath(hash []byte, leaf, leaves int, path [][]byte) ([]byte, [][]byte, error) {
+	if leaves == 1 {
+		return hash, path, nil
+	}
+	if len(path) == 0 {
+		return nil, nil, ErrInvalidMerkleProof
+	}
+	k := merkleSplit(leaves)
+	var err error
+	if leaf < k {
+		hash, path, err = merklePath(hash, leaf, k, path)
+	} else {
+		hash, path, err = merklePath(hash, leaf-k, leaves-k, path)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(path) == 0 || len(path[0]) != sha256.Size {
+		return nil, nil, ErrInvalidMerkleProof
+	}
+	if leaf < k {
+		return merkleNode(hash, path[0]), path[1:], nil
+	}
+	return merkleNode(path[0], hash), path[1:], nil
+}
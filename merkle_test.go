@@ -0,0 +1,37 @@
+package tss
+
+import (
+	"testing"
+)
+
+func TestCommitShares(t *testing.T) {
+	all, _ := CreateShares(randomBytes(1), 13, 2)
+	for _, n := range []int{1, 2, 3, 5, 8, 13} {
+		shares := all[:n]
+		root, proofs, err := CommitShares(shares)
+		if err != nil {
+			failNow(t, err)
+		}
+		for i, share := range shares {
+			if err := proofs[i].Verify(root, share); err != nil {
+				failNow(t, err)
+			}
+		}
+		if n == 1 {
+			continue
+		}
+		if err := proofs[0].Verify(root, shares[1]); err != ErrShareNotCommitted {
+			failNow(t, expected(ErrShareNotCommitted, err))
+		}
+		forged := append(Share{}, shares[n-1]...)
+		forged[1] ^= 1
+		if err := proofs[n-1].Verify(root, forged); err != ErrShareNotCommitted {
+			failNow(t, expected(ErrShareNotCommitted, err))
+		}
+		proof := proofs[n-1]
+		proof.Path = proof.Path[1:]
+		if err := proof.Verify(root, shares[n-1]); err != ErrInvalidMerkleProof {
+			failNow(t, expected(ErrInvalidMerkleProof, err))
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package tss
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+)
+
+const (
+	// multiMagic starts the binary form of a MultiShare
+	multiMagic = "TSSMULTI"
+	// multiVersion is the version of the binary form of a MultiShare
+	multiVersion = 1
+	// MaxMultiSecrets is the largest number of secrets in a multi-secret split
+	MaxMultiSecrets = 1<<16 - 1
+)
+
+// MultiShare is what a custodian holds for several secrets split together:
+// Shares[k] is the share of the k-th secret, every share having the same index
+type MultiShare struct {
+	Index  byte
+	Shares ShareSet
+}
+
+// CreateMultiShares splits several independent secrets at once, so every
+// custodian holds a single MultiShare covering all of them. Each secret gets
+// its own polynomials, recovering one reveals nothing about the others.
+func CreateMultiShares(secrets [][]byte, sharesCount int, threshold int, opts ...Option) ([]MultiShare, error) {
+	cfg := newConfig(opts)
+	if len(secrets) == 0 {
+		return nil, ErrSecretRequired
+	}
+	if len(secrets) > MaxMultiSecrets {
+		return nil, ErrSecretTooLarge
+	}
+	if sharesCount < MinShares {
+		return nil, ErrTooFewShares
+	}
+	if sharesCount > cfg.sharesLimit() {
+		return nil, ErrTooManyShares
+	}
+	indexes := make([]byte, sharesCount)
+	if cfg.randomIndexes {
+		if err := randomIndexes(cfg.random(), indexes); err != nil {
+			return nil, err
+		}
+	} else {
+		for i := range indexes {
+			indexes[i] = byte(i + 1)
+		}
+	}
+	multi := make([]MultiShare, sharesCount)
+	for j := range multi {
+		multi[j].Index = indexes[j]
+	}
+	for _, secret := range secrets {
+		var shares ShareSet
+		random, err := cfg.source(secret, threshold)
+		if err == nil {
+			shares, err = createShares(context.Background(), nil, secret, indexes, threshold, cfg, random)
+		}
+		if err != nil {
+			for _, m := range multi {
+				eraseShares(m.Shares)
+			}
+			return nil, err
+		}
+		for j := range multi {
+			multi[j].Shares = append(multi[j].Shares, shares[j])
+		}
+	}
+	return multi, nil
+}
+
+// RecoverMultiSecret recovers the k-th secret of a split made by
+// CreateMultiShares, leaving the others untouched
+func RecoverMultiSecret(shares []MultiShare, k int, opts ...Option) ([]byte, error) {
+	set := make(ShareSet, len(shares))
+	for i, s := range shares {
+		if err := s.validate(); err != nil {
+			return nil, &ShareError{Position: i + 1, Index: s.Index, Reason: err}
+		}
+		if k < 0 || k >= len(s.Shares) || len(s.Shares) != len(shares[0].Shares) {
+			return nil, &ShareError{Position: i + 1, Index: s.Index, Reason: ErrInvalidShare}
+		}
+		set[i] = s.Shares[k]
+	}
+	return RecoverSecret(set, opts...)
+}
+
+// validate checks every share carries the index of the MultiShare
+func (s MultiShare) validate() error {
+	if s.Index == 0 || len(s.Shares) == 0 || len(s.Shares) > MaxMultiSecrets {
+		return ErrInvalidShare
+	}
+	for _, share := range s.Shares {
+		if !share.Valid() || share[0] != s.Index {
+			return ErrInvalidShare
+		}
+	}
+	return nil
+}
+
+// MarshalBinary encodes the share as the index followed by the value of every
+// share, each prefixed by its size
+func (s MultiShare) MarshalBinary() ([]byte, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	b.WriteString(multiMagic)
+	b.WriteByte(multiVersion)
+	b.WriteByte(s.Index)
+	binary.Write(&b, binary.BigEndian, uint16(len(s.Shares)))
+	for _, share := range s.Shares {
+		binary.Write(&b, binary.BigEndian, uint16(share.SecretBytes()))
+		b.Write(share.Value())
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a share written by MarshalBinary
+func (s *MultiShare) UnmarshalBinary(data []byte) error {
+	if !bytes.HasPrefix(data, []byte(multiMagic)) {
+		return ErrInvalidShare
+	}
+	p := data[len(multiMagic):]
+	if len(p) < 4 {
+		return ErrInvalidShare
+	}
+	if p[0] != multiVersion {
+		return ErrUnsupportedVersion
+	}
+	index := p[1]
+	count := int(binary.BigEndian.Uint16(p[2:]))
+	p = p[4:]
+	var shares ShareSet
+	for k := 0; k < count; k++ {
+		if len(p) < 2 {
+			return ErrInvalidShare
+		}
+		size := int(binary.BigEndian.Uint16(p))
+		if len(p) < 2+size {
+			return ErrInvalidShare
+		}
+		shares = append(shares, NewShare(index, p[2:2+size]))
+		p = p[2+size:]
+	}
+	if len(p) != 0 {
+		return ErrInvalidShare
+	}
+	m := MultiShare{Index: index, Shares: shares}
+	if err := m.validate(); err != nil {
+		return err
+	}
+	*s = m
+	return nil
+}
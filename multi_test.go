@@ -0,0 +1,54 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestMultiShares(t *testing.T) {
+	secrets := [][]byte{randomBytes(16), randomBytes(32), randomBytes(1000)}
+	shares, err := CreateMultiShares(secrets, 5, 3, WithRandomIndexes())
+	if err != nil {
+		failNow(t, err)
+	}
+	quorum := make([]MultiShare, 3)
+	for i, s := range shares[2:] {
+		data, err := s.MarshalBinary()
+		if err != nil {
+			failNow(t, err)
+		}
+		if err := quorum[i].UnmarshalBinary(data); err != nil {
+			failNow(t, err)
+		}
+	}
+	for k, secret := range secrets {
+		got, err := RecoverMultiSecret(quorum, k)
+		if err != nil {
+			failNow(t, err)
+		}
+		if !bytes.Equal(got, secret) {
+			failNow(t, fmt.Errorf("secret %d mismatch", k))
+		}
+	}
+}
+
+func TestMultiSharesErrors(t *testing.T) {
+	shares, _ := CreateMultiShares([][]byte{randomBytes(16), randomBytes(16)}, 3, 2)
+	if _, err := RecoverMultiSecret(shares, 2); !errors.Is(err, ErrInvalidShare) {
+		failNow(t, expected(ErrInvalidShare, err))
+	}
+	shares[1].Shares[0][0] = 9
+	if _, err := RecoverMultiSecret(shares, 0); !errors.Is(err, ErrInvalidShare) {
+		failNow(t, expected(ErrInvalidShare, err))
+	}
+	data, _ := shares[0].MarshalBinary()
+	var s MultiShare
+	if err := s.UnmarshalBinary(data[:len(data)-1]); err != ErrInvalidShare {
+		failNow(t, expected(ErrInvalidShare, err))
+	}
+	if _, err := CreateMultiShares(nil, 3, 2); err != ErrSecretRequired {
+		failNow(t, expected(ErrSecretRequired, err))
+	}
+}
@@ -0,0 +1,57 @@
+package objectstore
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// azureVersion is the Blob Storage REST API version of the requests
+const azureVersion = "2021-08-06"
+
+// AzureBlob is a Backend over an Azure Blob Storage container, authorized
+// with a shared access signature or a Microsoft Entra token
+type AzureBlob struct {
+	Account   string
+	Container string
+	// SAS is a shared access signature query string, without the leading ?
+	SAS string
+	// Token returns an access token for https://storage.azure.com when SAS
+	// is empty
+	Token func(ctx context.Context) (string, error)
+	// Endpoint replaces https://<account>.blob.core.windows.net when set
+	Endpoint string
+}
+
+// NewRequest builds a request of a block blob
+func (b AzureBlob) NewRequest(ctx context.Context, method string, object string, body []byte, header http.Header) (*http.Request, error) {
+	endpoint := "https://" + b.Account + ".blob.core.windows.net"
+	if b.Endpoint != "" {
+		endpoint = strings.TrimSuffix(b.Endpoint, "/")
+	}
+	url := endpoint + "/" + b.Container + "/" + escapePath(object)
+	if b.SAS != "" {
+		url += "?" + strings.TrimPrefix(b.SAS, "?")
+	}
+	req, err := newRequest(ctx, method, url, body, header)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Ms-Version", azureVersion)
+	if method == http.MethodPut {
+		req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+	}
+	if b.SAS == "" {
+		token, err := b.Token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+// MetadataHeader returns x-ms-meta-<name>
+func (b AzureBlob) MetadataHeader(name string) string {
+	return "X-Ms-Meta-" + name
+}
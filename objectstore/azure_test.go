@@ -0,0 +1,29 @@
+package objectstore
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/antik10ud/go-tss/internal/storetest"
+)
+
+func TestAzureBlob(t *testing.T) {
+	_, server := newBucket(func(r *http.Request) bool {
+		if r.Header.Get("X-Ms-Version") != azureVersion {
+			return false
+		}
+		if r.Method == http.MethodPut && r.Header.Get("X-Ms-Blob-Type") != "BlockBlob" {
+			return false
+		}
+		return r.URL.Query().Get("sig") == "signature" || bearerToken(r)
+	})
+	defer server.Close()
+	storetest.Run(t, Store{Backend: AzureBlob{Account: "custodian", Container: "shares", SAS: "sv=2021-08-06&sig=signature", Endpoint: server.URL}}, "../a")
+	storetest.Run(t, Store{Backend: AzureBlob{
+		Account:   "custodian",
+		Container: "shares",
+		Token:     func(context.Context) (string, error) { return "token", nil },
+		Endpoint:  server.URL,
+	}}, "../a")
+}
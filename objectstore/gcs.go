@@ -0,0 +1,39 @@
+package objectstore
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// GCS is a Backend over a Google Cloud Storage bucket, through its XML API
+type GCS struct {
+	Bucket string
+	// Token returns an access token with the devstorage.read_write scope
+	Token func(ctx context.Context) (string, error)
+	// Endpoint replaces https://storage.googleapis.com when set
+	Endpoint string
+}
+
+// NewRequest builds a request with a bearer token
+func (b GCS) NewRequest(ctx context.Context, method string, object string, body []byte, header http.Header) (*http.Request, error) {
+	endpoint := "https://storage.googleapis.com"
+	if b.Endpoint != "" {
+		endpoint = strings.TrimSuffix(b.Endpoint, "/")
+	}
+	req, err := newRequest(ctx, method, endpoint+"/"+b.Bucket+"/"+escapePath(object), body, header)
+	if err != nil {
+		return nil, err
+	}
+	token, err := b.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req, nil
+}
+
+// MetadataHeader returns x-goog-meta-<name>
+func (b GCS) MetadataHeader(name string) string {
+	return "X-Goog-Meta-" + name
+}
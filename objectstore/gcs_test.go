@@ -0,0 +1,13 @@
+package objectstore
+
+import (
+	"testing"
+
+	"github.com/antik10ud/go-tss/internal/storetest"
+)
+
+func TestGCS(t *testing.T) {
+	_, server := newBucket(bearerToken)
+	defer server.Close()
+	storetest.Run(t, Store{Backend: testGCS(server.URL), Prefix: "shares/"}, "../a")
+}
@@ -0,0 +1,286 @@
+// Package objectstore keeps tss shares in object storage buckets, Amazon S3,
+// Google Cloud Storage or Azure Blob Storage, for geographically dispersed
+// custody. It talks to the REST API of each service with net/http, callers
+// supply credentials. Shares are uploaded as they are given: use the store
+// as the tss.BlobStore of kms.Store, or of another encrypting store, so
+// buckets only hold encrypted shares.
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/antik10ud/go-tss"
+)
+
+// maxObjectBytes bounds the objects read from buckets
+const maxObjectBytes = 1 << 20
+
+var (
+	ErrDigestMismatch = fmt.Errorf("object does not match its sha256 metadata: %w", tss.ErrIntegrity)
+)
+
+// Backend is an object storage service. NewRequest builds an authenticated
+// request of method on the object, with body and the headers of header;
+// MetadataHeader returns the header carrying the user metadata name.
+type Backend interface {
+	NewRequest(ctx context.Context, method string, object string, body []byte, header http.Header) (*http.Request, error)
+	MetadataHeader(name string) string
+}
+
+// Retry is the retry policy of requests failing on the network, throttled or
+// failing with a server error: up to Attempts tries, waiting Backoff doubled
+// at every try, up to MaxBackoff, with jitter
+type Retry struct {
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetry is the retry policy of a Store with a zero Retry
+var DefaultRetry = Retry{Attempts: 4, Backoff: 200 * time.Millisecond, MaxBackoff: 5 * time.Second}
+
+// Store is a tss.ShareStore and tss.BlobStore keeping each share or blob in
+// an object of Backend named Prefix, the label and .share. The SHA-256 of the
+// object goes in its metadata and is checked on Get. Requests use Context, or
+// context.Background when it is nil.
+type Store struct {
+	Backend Backend
+	Prefix  string
+	Retry   Retry
+	Client  *http.Client
+	Context context.Context
+}
+
+// object returns the object name of label, which must be a plain name
+func (s Store) object(label string) (string, error) {
+	if label == "" || label == "." || label == ".." || strings.ContainsAny(label, "/\\\x00") {
+		return "", tss.ErrInvalidLabel
+	}
+	return s.Prefix + label + ".share", nil
+}
+
+// Put uploads the share
+func (s Store) Put(label string, share tss.Share) error {
+	if !share.Valid() {
+		return tss.ErrInvalidShare
+	}
+	return s.PutBlob(label, share)
+}
+
+// PutBlob uploads blob
+func (s Store) PutBlob(label string, blob []byte) error {
+	object, err := s.object(label)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(blob)
+	header := http.Header{}
+	header.Set("Content-Type", "application/octet-stream")
+	header.Set(s.Backend.MetadataHeader("sha256"), hex.EncodeToString(sum[:]))
+	resp, err := s.do(http.MethodPut, object, blob, header)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get downloads the share of label
+func (s Store) Get(label string) (tss.Share, error) {
+	data, err := s.GetBlob(label)
+	if err != nil {
+		return nil, err
+	}
+	share := tss.Share(data)
+	if !share.Valid() {
+		tss.Wipe(data)
+		return nil, tss.ErrInvalidShare
+	}
+	return share, nil
+}
+
+// GetBlob downloads the blob of label
+func (s Store) GetBlob(label string) ([]byte, error) {
+	object, err := s.object(label)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(http.MethodGet, object, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxObjectBytes))
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	if resp.Header.Get(s.Backend.MetadataHeader("sha256")) != hex.EncodeToString(sum[:]) {
+		tss.Wipe(data)
+		return nil, ErrDigestMismatch
+	}
+	return data, nil
+}
+
+// Delete removes the object of label. Deleting a missing object succeeding
+// on some services, its existence is checked first.
+func (s Store) Delete(label string) error {
+	object, err := s.object(label)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(http.MethodHead, object, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	resp, err = s.do(http.MethodDelete, object, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// do sends a request with the retry policy, returning the response of a
+// 2xx status. A 404 is tss.ErrShareNotFound, other statuses *APIError.
+func (s Store) do(method string, object string, body []byte, header http.Header) (*http.Response, error) {
+	ctx := s.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	retry := s.Retry
+	if retry.Attempts == 0 {
+		retry = DefaultRetry
+	}
+	backoff := retry.Backoff
+	for attempt := 1; ; attempt++ {
+		req, err := s.Backend.NewRequest(ctx, method, object, body, header)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err == nil {
+			if resp.StatusCode/100 == 2 {
+				return resp, nil
+			}
+			err = newAPIError(resp)
+			if resp.StatusCode == http.StatusNotFound {
+				return nil, tss.ErrShareNotFound
+			}
+		}
+		if attempt >= retry.Attempts || ctx.Err() != nil || !retryable(err) {
+			return nil, err
+		}
+		wait := backoff/2 + rand.N(backoff/2+1)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		if backoff *= 2; backoff > retry.MaxBackoff && retry.MaxBackoff > 0 {
+			backoff = retry.MaxBackoff
+		}
+	}
+}
+
+// retryable tells whether a failed request is worth trying again
+func retryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	return true
+}
+
+// APIError is an error response of a service
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("objectstore: HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// newAPIError reads the error response resp, closing its body
+func newAPIError(resp *http.Response) *APIError {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return &APIError{StatusCode: resp.StatusCode, Body: string(bytes.TrimSpace(body))}
+}
+
+// escapePath escapes the segments of an object name
+func escapePath(name string) string {
+	parts := strings.Split(name, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+// newRequest builds a request with body and the headers of header
+func newRequest(ctx context.Context, method string, url string, body []byte, header http.Header) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	return req, nil
+}
+
+// Custodians is a tss.ShareStore routing each label to the store of its
+// custodian, a bucket or account per custodian. Labels without a store are
+// tss.ErrInvalidLabel.
+type Custodians map[string]tss.ShareStore
+
+func (c Custodians) store(label string) (tss.ShareStore, error) {
+	if s, ok := c[label]; ok {
+		return s, nil
+	}
+	return nil, tss.ErrInvalidLabel
+}
+
+// Put writes the share to the store of label
+func (c Custodians) Put(label string, share tss.Share) error {
+	s, err := c.store(label)
+	if err != nil {
+		return err
+	}
+	return s.Put(label, share)
+}
+
+// Get reads the share from the store of label
+func (c Custodians) Get(label string) (tss.Share, error) {
+	s, err := c.store(label)
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(label)
+}
+
+// Delete removes the share from the store of label
+func (c Custodians) Delete(label string) error {
+	s, err := c.store(label)
+	if err != nil {
+		return err
+	}
+	return s.Delete(label)
+}
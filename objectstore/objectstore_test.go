@@ -0,0 +1,142 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/antik10ud/go-tss"
+)
+
+// bucket is an object storage server keeping objects and their metadata by
+// path, failing the first failures requests with 503 and refusing requests
+// authorize does not accept
+type bucket struct {
+	mu        sync.Mutex
+	objects   map[string][]byte
+	metadata  map[string]http.Header
+	failures  int
+	requests  int
+	authorize func(r *http.Request) bool
+}
+
+func newBucket(authorize func(r *http.Request) bool) (*bucket, *httptest.Server) {
+	b := &bucket{objects: map[string][]byte{}, metadata: map[string]http.Header{}, authorize: authorize}
+	return b, httptest.NewServer(b)
+}
+
+func (b *bucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.requests++
+	if b.failures > 0 {
+		b.failures--
+		http.Error(w, "SlowDown", http.StatusServiceUnavailable)
+		return
+	}
+	if !b.authorize(r) {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+	data, ok := b.objects[r.URL.Path]
+	switch r.Method {
+	case http.MethodPut:
+		b.objects[r.URL.Path], _ = io.ReadAll(r.Body)
+		meta := http.Header{}
+		for name, values := range r.Header {
+			if strings.Contains(name, "-Meta-") {
+				meta[name] = values
+			}
+		}
+		b.metadata[r.URL.Path] = meta
+		return
+	case http.MethodDelete:
+		delete(b.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	for name, values := range b.metadata[r.URL.Path] {
+		w.Header()[name] = values
+	}
+	if r.Method == http.MethodGet {
+		w.Write(data)
+	}
+}
+
+func testGCS(url string) GCS {
+	return GCS{Bucket: "custodian", Token: func(context.Context) (string, error) { return "token", nil }, Endpoint: url}
+}
+
+func bearerToken(r *http.Request) bool {
+	return r.Header.Get("Authorization") == "Bearer token"
+}
+
+func TestStoreIntegrity(t *testing.T) {
+	b, server := newBucket(bearerToken)
+	defer server.Close()
+	store := Store{Backend: testGCS(server.URL), Prefix: "tss/"}
+	share := tss.Share{1, 2, 3}
+	if err := store.Put("a", share); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.objects["/custodian/tss/a.share"]; !ok {
+		t.Fatal("object not found in bucket")
+	}
+	b.objects["/custodian/tss/a.share"][1] ^= 1
+	if _, err := store.Get("a"); !errors.Is(err, ErrDigestMismatch) {
+		t.Fatal(err)
+	}
+}
+
+func TestStoreRetry(t *testing.T) {
+	b, server := newBucket(bearerToken)
+	defer server.Close()
+	store := Store{Backend: testGCS(server.URL), Retry: Retry{Attempts: 3, Backoff: time.Millisecond}}
+	b.failures = 2
+	if err := store.Put("a", tss.Share{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if b.requests != 3 {
+		t.Fatalf("%d requests", b.requests)
+	}
+	b.failures, b.requests = 3, 0
+	var apiErr *APIError
+	if _, err := store.Get("a"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable || b.requests != 3 {
+		t.Fatal(err)
+	}
+	b.requests = 0
+	store.Backend = GCS{Bucket: "custodian", Token: func(context.Context) (string, error) { return "expired", nil }, Endpoint: server.URL}
+	if _, err := store.Get("a"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden || b.requests != 1 {
+		t.Fatal(err)
+	}
+}
+
+func TestCustodians(t *testing.T) {
+	_, east := newBucket(bearerToken)
+	defer east.Close()
+	_, west := newBucket(bearerToken)
+	defer west.Close()
+	custodians := Custodians{
+		"alice": Store{Backend: testGCS(east.URL)},
+		"bob":   Store{Backend: testGCS(west.URL)},
+	}
+	if err := custodians.Put("alice", tss.Share{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := custodians["bob"].Get("alice"); err != tss.ErrShareNotFound {
+		t.Fatal(err)
+	}
+	if _, err := custodians.Get("carol"); err != tss.ErrInvalidLabel {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,56 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/antik10ud/go-tss/internal/sigv4"
+)
+
+// S3Credentials are the credentials of the requests signed with AWS
+// Signature Version 4, SessionToken being set for temporary ones
+type S3Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// S3 is a Backend over an Amazon S3 bucket, or a service speaking its API
+type S3 struct {
+	Bucket string
+	Region string
+	// Credentials returns the credentials of a request
+	Credentials func(ctx context.Context) (S3Credentials, error)
+	// Endpoint, when set, replaces https://<bucket>.s3.<region>.amazonaws.com
+	// with path style requests to <endpoint>/<bucket>
+	Endpoint string
+}
+
+// NewRequest builds a request signed with Signature Version 4
+func (b S3) NewRequest(ctx context.Context, method string, object string, body []byte, header http.Header) (*http.Request, error) {
+	url := "https://" + b.Bucket + ".s3." + b.Region + ".amazonaws.com/" + escapePath(object)
+	if b.Endpoint != "" {
+		url = strings.TrimSuffix(b.Endpoint, "/") + "/" + b.Bucket + "/" + escapePath(object)
+	}
+	req, err := newRequest(ctx, method, url, body, header)
+	if err != nil {
+		return nil, err
+	}
+	creds, err := b.Credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+	sigv4.Sign(req, body, sigv4.Credentials(creds), b.Region, "s3", time.Now())
+	return req, nil
+}
+
+// MetadataHeader returns x-amz-meta-<name>
+func (b S3) MetadataHeader(name string) string {
+	return "X-Amz-Meta-" + name
+}
@@ -0,0 +1,34 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/antik10ud/go-tss/internal/storetest"
+)
+
+func TestS3(t *testing.T) {
+	_, server := newBucket(func(r *http.Request) bool {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+		sum := sha256.Sum256(body)
+		return strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") &&
+			strings.Contains(r.Header.Get("Authorization"), "/eu-west-1/s3/aws4_request") &&
+			r.Header.Get("X-Amz-Content-Sha256") == hex.EncodeToString(sum[:]) &&
+			strings.HasPrefix(r.URL.Path, "/custodian/")
+	})
+	defer server.Close()
+	storetest.Run(t, Store{Backend: S3{
+		Bucket: "custodian",
+		Region: "eu-west-1",
+		Credentials: func(context.Context) (S3Credentials, error) {
+			return S3Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+		},
+		Endpoint: server.URL,
+	}}, "../a")
+}
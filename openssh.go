@@ -0,0 +1,186 @@
+package tss
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+)
+
+const (
+	// sshKeyMagic starts the binary of an OpenSSH private key
+	sshKeyMagic = "openssh-key-v1\x00"
+	// sshPEMType is the PEM type of an OpenSSH private key file
+	sshPEMType = "OPENSSH PRIVATE KEY"
+	// sshShareMagic starts a marshaled SSHShare
+	sshShareMagic = "TSSSSH"
+	// sshShareVersion is the version of the marshaled SSHShare
+	sshShareVersion = 1
+)
+
+var (
+	ErrInvalidSSHKey = validationError("invalid OpenSSH private key")
+)
+
+// SSHShare is a share of the private section of an OpenSSH private key, along
+// the public header of the key file: cipher, KDF and its options, and the
+// public keys. Encrypted keys stay encrypted, the passphrase is still needed
+// once the key is recovered.
+type SSHShare struct {
+	Header []byte
+	Share  Share
+}
+
+// SplitSSHKey splits the private section of an OpenSSH private key file, as
+// written by ssh-keygen, encrypted or not
+func SplitSSHKey(key []byte, sharesCount int, threshold int, opts ...Option) ([]SSHShare, error) {
+	block, _ := pem.Decode(key)
+	if block == nil || block.Type != sshPEMType {
+		return nil, ErrInvalidSSHKey
+	}
+	defer erase(block.Bytes)
+	n, err := sshHeaderBytes(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	private, rest, ok := cutSSHString(block.Bytes[n:])
+	if !ok || len(rest) != 0 || len(private) == 0 {
+		return nil, ErrInvalidSSHKey
+	}
+	shares, err := CreateShares(private, sharesCount, threshold, append(opts, WithConsumeSecret(false))...)
+	if err != nil {
+		return nil, err
+	}
+	header := append([]byte{}, block.Bytes[:n]...)
+	out := make([]SSHShare, len(shares))
+	for i, s := range shares {
+		out[i] = SSHShare{Header: header, Share: s}
+	}
+	return out, nil
+}
+
+// RecoverSSHKey recovers the OpenSSH private key file split by SplitSSHKey.
+// The shares must carry the same header.
+func RecoverSSHKey(shares []SSHShare, opts ...Option) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, ErrTooFewShares
+	}
+	set := make(ShareSet, len(shares))
+	for i, s := range shares {
+		if !bytes.Equal(s.Header, shares[0].Header) {
+			return nil, &ShareError{Position: i + 1, Index: s.Share.Index(), Reason: ErrMixedShares}
+		}
+		set[i] = s.Share
+	}
+	if n, err := sshHeaderBytes(shares[0].Header); err != nil || n != len(shares[0].Header) {
+		return nil, ErrInvalidSSHKey
+	}
+	private, err := RecoverSecret(set, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer erase(private)
+	data := make([]byte, 0, len(shares[0].Header)+4+len(private))
+	defer erase(data)
+	data = append(data, shares[0].Header...)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(private)))
+	data = append(data, private...)
+	return pem.EncodeToMemory(&pem.Block{Type: sshPEMType, Bytes: data}), nil
+}
+
+// AuthorizedKey returns the first public key of the header in the
+// authorized_keys format, for custodians to check which key they hold a share
+// of
+func (s SSHShare) AuthorizedKey() (string, error) {
+	if _, err := sshHeaderBytes(s.Header); err != nil {
+		return "", err
+	}
+	p := s.Header[len(sshKeyMagic):]
+	for i := 0; i < 3; i++ {
+		_, p, _ = cutSSHString(p)
+	}
+	public, _, _ := cutSSHString(p[4:])
+	typ, _, ok := cutSSHString(public)
+	if !ok {
+		return "", ErrInvalidSSHKey
+	}
+	return string(typ) + " " + base64.StdEncoding.EncodeToString(public), nil
+}
+
+// MarshalBinary writes the share as magic, version, the header prefixed by
+// its 4 bytes big endian length, and the share
+func (s SSHShare) MarshalBinary() ([]byte, error) {
+	if !s.Share.Valid() {
+		return nil, ErrInvalidShare
+	}
+	b := append([]byte(sshShareMagic), sshShareVersion)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s.Header)))
+	b = append(b, s.Header...)
+	return append(b, s.Share...), nil
+}
+
+// UnmarshalSSHShare reads a share written by SSHShare.MarshalBinary
+func UnmarshalSSHShare(data []byte) (SSHShare, error) {
+	header := len(sshShareMagic) + 1 + 4
+	if len(data) < header || !bytes.HasPrefix(data, []byte(sshShareMagic)) {
+		return SSHShare{}, ErrInvalidShare
+	}
+	if data[len(sshShareMagic)] != sshShareVersion {
+		return SSHShare{}, ErrUnsupportedVersion
+	}
+	n := binary.BigEndian.Uint32(data[header-4:])
+	if uint64(n) > uint64(len(data)-header) {
+		return SSHShare{}, ErrInvalidShare
+	}
+	s := SSHShare{
+		Header: append([]byte{}, data[header:header+int(n)]...),
+		Share:  append(Share{}, data[header+int(n):]...),
+	}
+	if !s.Share.Valid() {
+		return SSHShare{}, ErrInvalidShare
+	}
+	return s, nil
+}
+
+// sshHeaderBytes returns the size of the public header of the binary of an
+// OpenSSH private key: magic, cipher name, KDF name, KDF options, number of
+// keys and the public keys
+func sshHeaderBytes(data []byte) (int, error) {
+	if !bytes.HasPrefix(data, []byte(sshKeyMagic)) {
+		return 0, ErrInvalidSSHKey
+	}
+	p := data[len(sshKeyMagic):]
+	var ok bool
+	for i := 0; i < 3; i++ {
+		if _, p, ok = cutSSHString(p); !ok {
+			return 0, ErrInvalidSSHKey
+		}
+	}
+	if len(p) < 4 {
+		return 0, ErrInvalidSSHKey
+	}
+	keys := binary.BigEndian.Uint32(p)
+	if keys < 1 || keys > 16 {
+		return 0, ErrInvalidSSHKey
+	}
+	p = p[4:]
+	for ; keys > 0; keys-- {
+		if _, p, ok = cutSSHString(p); !ok {
+			return 0, ErrInvalidSSHKey
+		}
+	}
+	return len(data) - len(p), nil
+}
+
+// cutSSHString splits a string prefixed by its 4 bytes big endian length
+// from p
+func cutSSHString(p []byte) (s []byte, rest []byte, ok bool) {
+	if len(p) < 4 {
+		return nil, nil, false
+	}
+	n := binary.BigEndian.Uint32(p)
+	if uint64(n) > uint64(len(p)-4) {
+		return nil, nil, false
+	}
+	return p[4 : 4+n], p[4+n:], true
+}
@@ -0,0 +1,197 @@
+package tss
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"runtime"
+)
+
+// DefaultMaxChunkedSecretBytes is the largest secret accepted in chunked mode
+// unless WithMaxSecretBytes says otherwise
+const DefaultMaxChunkedSecretBytes = 1 << 32
+
+// Option tunes how secrets are split and recovered
+type Option func(*config)
+
+// config holds the settings built from the options, the zero value of a
+// field means the package default
+type config struct {
+	minSecretBytes int
+	maxSecretBytes int64
+	maxShares      int
+	threshold      int
+	randomIndexes  bool
+	rand           io.Reader
+	deterministic  bool
+	salt           []byte
+	info           string
+	xor            bool
+	trivial        bool
+	consume        bool
+	allocator      Allocator
+	parallelism    int
+	hardened       bool
+	argonTime      uint32
+	argonMemory    uint32
+	argonThreads   uint8
+	dealerKey      ed25519.PublicKey
+	strict         bool
+	ssssPlain      bool
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{minSecretBytes: MinSecretBytes}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithMinSecretBytes sets the smallest secret accepted, MinSecretBytes by
+// default. Services can raise it so short, low entropy secrets are refused;
+// values below MinSecretBytes are ignored.
+func WithMinSecretBytes(n int) Option {
+	return func(c *config) {
+		if n >= MinSecretBytes {
+			c.minSecretBytes = n
+		}
+	}
+}
+
+// WithMaxSecretBytes sets the largest secret accepted when splitting, and the
+// largest secret a set of shares may recover to. Services handling untrusted
+// shares can lower it. A share never holds more than MaxSecretBytes whatever
+// the option, values above only matter for chunked mode, where the default is
+// DefaultMaxChunkedSecretBytes.
+func WithMaxSecretBytes(n int64) Option {
+	return func(c *config) {
+		if n >= MinSecretBytes {
+			c.maxSecretBytes = n
+		}
+	}
+}
+
+// WithMaxShares sets the largest number of shares created or accepted on
+// recovery, MaxShares by default. Values above MaxShares are ignored.
+func WithMaxShares(n int) Option {
+	return func(c *config) {
+		if n >= MinShares && n <= MaxShares {
+			c.maxShares = n
+		}
+	}
+}
+
+// WithThreshold gives the threshold recorded along the shares, so recovery
+// from fewer shares fails with a *ThresholdError instead of returning a wrong
+// secret, and a Combiner's CanRecover waits for enough shares
+func WithThreshold(t int) Option {
+	return func(c *config) {
+		if t >= MinThreshold && t <= MaxShares {
+			c.threshold = t
+		}
+	}
+}
+
+// WithRandomIndexes makes CreateShares pick random distinct indexes instead of
+// 1 to n, so a share index tells nothing about the number of shares
+func WithRandomIndexes() Option {
+	return func(c *config) {
+		c.randomIndexes = true
+	}
+}
+
+// WithRand sets the source of the random polynomial coefficients and indexes,
+// crypto/rand by default. It must be a cryptographically secure generator,
+// such as an HSM backed DRBG, unless reproducible shares are wanted in tests.
+func WithRand(r io.Reader) Option {
+	return func(c *config) {
+		c.rand = r
+	}
+}
+
+// WithXOR selects the XOR scheme for n of n splits, when threshold equals the
+// number of shares: every share but one is random and the last one is the
+// secret xored with them. It is much faster than polynomial evaluation, but
+// the shares must be recovered with WithXOR too, all of them being required.
+func WithXOR() Option {
+	return func(c *config) {
+		c.xor = true
+	}
+}
+
+// AllowTrivialThreshold permits a threshold of 1, every share then holding
+// the secret in the clear, and recovery from a single share. It makes plain
+// replicated backups with the same API and share formats as real splits.
+func AllowTrivialThreshold() Option {
+	return func(c *config) {
+		c.trivial = true
+	}
+}
+
+// WithConsumeSecret makes CreateShares, its variants and CreateChunkedShares
+// wipe the secret passed to them once the shares are computed, so the
+// caller's buffer no longer holds it. The secret is left untouched when
+// splitting fails.
+func WithConsumeSecret(consume bool) Option {
+	return func(c *config) {
+		c.consume = consume
+	}
+}
+
+// WithParallelism makes CreateShares compute the shares of large secrets, and
+// RecoverSecret and RecoverChunkedSecret recover them, with n goroutines,
+// runtime.GOMAXPROCS(0) when n is 0. The bytes of the secret are handled
+// independently, so the shares are the same as without the option for the
+// same random source. Secrets below a few hundred bytes are handled
+// sequentially whatever n.
+func WithParallelism(n int) Option {
+	return func(c *config) {
+		if n == 0 {
+			n = runtime.GOMAXPROCS(0)
+		}
+		if n >= 1 {
+			c.parallelism = n
+		}
+	}
+}
+
+// minThreshold is the smallest threshold accepted, also the smallest number
+// of shares accepted on recovery
+func (c *config) minThreshold() int {
+	if c.trivial {
+		return 1
+	}
+	return MinThreshold
+}
+
+// random is the randomness source
+func (c *config) random() io.Reader {
+	if c.rand != nil {
+		return c.rand
+	}
+	return rand.Reader
+}
+
+// secretLimit is the largest secret held in a single set of shares
+func (c *config) secretLimit() int {
+	if c.maxSecretBytes > 0 && c.maxSecretBytes < MaxSecretBytes {
+		return int(c.maxSecretBytes)
+	}
+	return MaxSecretBytes
+}
+
+// chunkedSecretLimit is the largest secret accepted in chunked mode
+func (c *config) chunkedSecretLimit() int64 {
+	if c.maxSecretBytes > 0 {
+		return c.maxSecretBytes
+	}
+	return DefaultMaxChunkedSecretBytes
+}
+
+func (c *config) sharesLimit() int {
+	if c.maxShares > 0 {
+		return c.maxShares
+	}
+	return MaxShares
+}
@@ -0,0 +1,181 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestMinSecretBytes(t *testing.T) {
+	// 128 bit AES keys split by default
+	shares, err := CreateShares(randomBytes(16), 3, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	if _, err := CreateShares(randomBytes(16), 3, 2, WithMinSecretBytes(32)); err != ErrSecretTooShort {
+		failNow(t, expected(ErrSecretTooShort, err))
+	}
+	if _, err := CreateShares(randomBytes(32), 3, 2, WithMinSecretBytes(32)); err != nil {
+		failNow(t, err)
+	}
+	if _, err := RecoverSecret(shares, WithMinSecretBytes(32)); !errors.Is(err, ErrShareSize) {
+		failNow(t, expected(ErrInvalidShare, err))
+	}
+	if _, err := CreateShares(randomBytes(1), 3, 2, WithMinSecretBytes(0)); err != nil {
+		failNow(t, err)
+	}
+}
+
+func TestMaxSecretBytes(t *testing.T) {
+	if _, err := CreateShares(randomBytes(100), 3, 2, WithMaxSecretBytes(64)); err != ErrSecretTooLarge {
+		failNow(t, expected(ErrSecretTooLarge, err))
+	}
+	// a share never holds more than MaxSecretBytes
+	if _, err := CreateShares(randomBytes(MaxSecretBytes+1), 3, 2, WithMaxSecretBytes(1<<20)); err != ErrSecretTooLarge {
+		failNow(t, expected(ErrSecretTooLarge, err))
+	}
+	shares, _ := CreateShares(randomBytes(100), 3, 2)
+	if _, err := RecoverSecret(shares, WithMaxSecretBytes(64)); !errors.Is(err, ErrShareSize) {
+		failNow(t, expected(ErrInvalidShare, err))
+	}
+	secret := randomBytes(ChunkBytes + 1)
+	if _, err := CreateChunkedShares(secret, 3, 2, WithMaxSecretBytes(ChunkBytes)); err != ErrSecretTooLarge {
+		failNow(t, expected(ErrSecretTooLarge, err))
+	}
+	chunked, err := CreateChunkedShares(secret, 3, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	if _, err := RecoverChunkedSecret(chunked, WithMaxSecretBytes(ChunkBytes)); err != ErrSecretTooLarge {
+		failNow(t, expected(ErrSecretTooLarge, err))
+	}
+}
+
+func TestMaxShares(t *testing.T) {
+	if _, err := CreateShares(randomBytes(32), 10, 2, WithMaxShares(5)); err != ErrTooManyShares {
+		failNow(t, expected(ErrTooManyShares, err))
+	}
+	shares, _ := CreateShares(randomBytes(32), 10, 2)
+	if _, err := RecoverSecret(shares, WithMaxShares(5)); err != ErrTooManyShares {
+		failNow(t, expected(ErrTooManyShares, err))
+	}
+	if _, err := RecoverSecret(shares[:5], WithMaxShares(5)); err != nil {
+		failNow(t, err)
+	}
+}
+
+func TestRandomIndexesOption(t *testing.T) {
+	secret := randomBytes(32)
+	shares, err := CreateShares(secret, 5, 3, WithRandomIndexes())
+	if err != nil {
+		failNow(t, err)
+	}
+	sequential := true
+	for i, s := range shares {
+		if s[0] == 0 {
+			failNow(t, ErrInvalidShare)
+		}
+		sequential = sequential && s[0] == byte(i+1)
+	}
+	if sequential {
+		// one chance in 255*254*253*252*251
+		failNow(t, fmt.Errorf("indexes are sequential"))
+	}
+	testRecover(t, secret, shares[2:])
+}
+
+func TestWithRand(t *testing.T) {
+	secret := randomBytes(32)
+	seed := randomBytes(1000)
+	a, err := CreateShares(secret, 5, 3, WithRand(bytes.NewReader(seed)), WithRandomIndexes())
+	if err != nil {
+		failNow(t, err)
+	}
+	b, _ := CreateShares(secret, 5, 3, WithRand(bytes.NewReader(seed)), WithRandomIndexes())
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			failNow(t, fmt.Errorf("share %d differs with the same randomness", i))
+		}
+	}
+	testRecover(t, secret, a[1:4])
+	if _, err := CreateShares(secret, 5, 3, WithRand(bytes.NewReader(seed[:10]))); err != io.ErrUnexpectedEOF {
+		failNow(t, expected(io.ErrUnexpectedEOF, err))
+	}
+}
+
+func TestAllowTrivialThreshold(t *testing.T) {
+	secret := randomBytes(32)
+	if _, err := CreateShares(secret, 3, 1); err != ErrInvalidThreshold {
+		failNow(t, expected(ErrInvalidThreshold, err))
+	}
+	shares, err := CreateShares(secret, 3, 1, AllowTrivialThreshold())
+	if err != nil {
+		failNow(t, err)
+	}
+	if _, err := RecoverSecret(shares[2:]); err != ErrTooFewShares {
+		failNow(t, expected(ErrTooFewShares, err))
+	}
+	got, err := RecoverSecret(shares[2:], AllowTrivialThreshold())
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(got, secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+	data, err := MarshalContainer(shares[1], ShareParams{Threshold: 1})
+	if err != nil {
+		failNow(t, err)
+	}
+	share, params, err := UnmarshalContainer(data)
+	if err != nil || params.Threshold != 1 {
+		failNow(t, fmt.Errorf("container round trip: %v", err))
+	}
+	c := NewCombiner(AllowTrivialThreshold())
+	if err := c.AddShareWithParams(share, params); err != nil {
+		failNow(t, err)
+	}
+	if !c.CanRecover() {
+		failNow(t, fmt.Errorf("cannot recover from a single share"))
+	}
+	got, _ = c.Recover()
+	if !bytes.Equal(got, secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+}
+
+func TestWithConsumeSecret(t *testing.T) {
+	secret := randomBytes(32)
+	buf := append([]byte{}, secret...)
+	shares, err := CreateShares(buf, 3, 2, WithConsumeSecret(true))
+	if err != nil {
+		failNow(t, err)
+	}
+	if bytes.Equal(buf, secret) {
+		failNow(t, fmt.Errorf("secret not wiped"))
+	}
+	testRecover(t, secret, shares[:2])
+
+	buf = append(buf[:0], secret...)
+	if _, err := CreateShares(buf, 3, 4, WithConsumeSecret(true)); err != ErrInvalidThreshold {
+		failNow(t, expected(ErrInvalidThreshold, err))
+	}
+	if !bytes.Equal(buf, secret) {
+		failNow(t, fmt.Errorf("secret wiped on failure"))
+	}
+
+	large := randomBytes(2*ChunkBytes + 10)
+	buf = append([]byte{}, large...)
+	chunked, err := CreateChunkedShares(buf, 3, 2, WithConsumeSecret(true))
+	if err != nil {
+		failNow(t, err)
+	}
+	if bytes.Equal(buf[:32], large[:32]) || bytes.Equal(buf[len(buf)-8:], large[len(large)-8:]) {
+		failNow(t, fmt.Errorf("chunked secret not wiped"))
+	}
+	recovered, err := RecoverChunkedSecret(chunked[1:])
+	if err != nil || !bytes.Equal(recovered, large) {
+		failNow(t, fmt.Errorf("chunked secret mismatch %v", err))
+	}
+}
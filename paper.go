@@ -0,0 +1,156 @@
+package tss
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// PaperLineBytes is the number of data bytes per line of a paper backup
+	PaperLineBytes = 15
+	// paperGroupChars is the number of base32 characters between spaces
+	paperGroupChars = 4
+	// paperCheckChars is the number of base32 characters of the line check
+	paperCheckChars = 2
+	// paperFingerprintBytes is the number of SHA-256 bytes in the footer
+	paperFingerprintBytes = 10
+)
+
+var (
+	ErrMissingLine  = validationError("missing line")
+	ErrFingerprint  = integrityError("fingerprint mismatch")
+	ErrInvalidPaper = validationError("invalid paper backup")
+)
+
+var paperEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// PaperLineError reports a line of a paper backup whose check does not match
+type PaperLineError struct {
+	Line int
+}
+
+func (e *PaperLineError) Error() string {
+	return fmt.Sprintf("line %d fails checksum", e.Line)
+}
+
+func (e *PaperLineError) Unwrap() error {
+	return ErrChecksum
+}
+
+// RenderPaper lays data, typically a share or a share container, out for a
+// paper backup: numbered lines of PaperLineBytes bytes as grouped base32, each
+// followed by a check, and a footer holding the line count and a fingerprint
+// of the whole data.
+//
+//	01  GEZD GNBV GY3T QOJQ GEZD GNBV  7S
+//	02  GY3T QOJQ GEZD GNBV  3F
+//	END 02  FP 6JKD-77NU-V74S-MWWQ
+func RenderPaper(data []byte) string {
+	var b strings.Builder
+	lines := (len(data) + PaperLineBytes - 1) / PaperLineBytes
+	width := len(strconv.Itoa(lines))
+	if width < 2 {
+		width = 2
+	}
+	for i := 0; i < lines; i++ {
+		end := (i + 1) * PaperLineBytes
+		if end > len(data) {
+			end = len(data)
+		}
+		chunk := data[i*PaperLineBytes : end]
+		fmt.Fprintf(&b, "%0*d  %s  %s\n", width, i+1, group(paperEncoding.EncodeToString(chunk), paperGroupChars, " "), paperCheck(i+1, chunk))
+	}
+	fmt.Fprintf(&b, "END %0*d  FP %s\n", width, lines, paperFingerprint(data))
+	return b.String()
+}
+
+// ParsePaper reads back a paper backup written by RenderPaper, as re-typed by
+// a person: case, spacing, blank lines and line order don't matter, and the
+// digits 0, 1 and 8 are read as the letters O, I and B they are mistaken for.
+// A mistyped line is reported as a *PaperLineError.
+func ParsePaper(text string) ([]byte, error) {
+	chunks := make(map[int][]byte)
+	lines := -1
+	var fingerprint string
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(strings.ToUpper(line))
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "END" {
+			if len(fields) != 4 || fields[2] != "FP" {
+				return nil, ErrInvalidPaper
+			}
+			n, err := strconv.Atoi(fields[1])
+			if err != nil || n < 0 {
+				return nil, ErrInvalidPaper
+			}
+			lines, fingerprint = n, strings.Replace(fields[3], "-", "", -1)
+			continue
+		}
+		n, err := strconv.Atoi(fields[0])
+		if err != nil || n < 1 || len(fields) < 3 {
+			return nil, ErrInvalidPaper
+		}
+		body := paperNormalize(strings.Join(fields[1:len(fields)-1], ""))
+		chunk, err := paperEncoding.DecodeString(body)
+		if err != nil || paperCheck(n, chunk) != paperNormalize(fields[len(fields)-1]) {
+			return nil, &PaperLineError{Line: n}
+		}
+		chunks[n] = chunk
+	}
+	if lines < 0 {
+		return nil, ErrMissingLine
+	}
+	numbers := make([]int, 0, len(chunks))
+	for n := range chunks {
+		if n > lines {
+			return nil, ErrInvalidPaper
+		}
+		numbers = append(numbers, n)
+	}
+	if len(numbers) != lines {
+		return nil, ErrMissingLine
+	}
+	sort.Ints(numbers)
+	var data []byte
+	for _, n := range numbers {
+		data = append(data, chunks[n]...)
+	}
+	if paperNormalize(fingerprint) != strings.Replace(paperFingerprint(data), "-", "", -1) {
+		return nil, ErrFingerprint
+	}
+	return data, nil
+}
+
+// paperCheck binds the line number to the line content, so swapped or
+// duplicated lines are caught too
+func paperCheck(line int, chunk []byte) string {
+	sum := crc32.Update(crc32.Checksum([]byte(strconv.Itoa(line)), castagnoli), castagnoli, chunk)
+	return paperEncoding.EncodeToString([]byte{byte(sum >> 8), byte(sum)})[:paperCheckChars]
+}
+
+func paperFingerprint(data []byte) string {
+	sum := sha256.Sum256(data)
+	return group(paperEncoding.EncodeToString(sum[:paperFingerprintBytes]), paperGroupChars, "-")
+}
+
+// paperNormalize maps the digits outside the base32 alphabet to the letters they look like
+func paperNormalize(s string) string {
+	return strings.NewReplacer("0", "O", "1", "I", "8", "B").Replace(strings.ToUpper(s))
+}
+
+// group splits s in groups of n characters joined by sep
+func group(s string, n int, sep string) string {
+	var groups []string
+	for len(s) > n {
+		groups = append(groups, s[:n])
+		s = s[n:]
+	}
+	return strings.Join(append(groups, s), sep)
+}
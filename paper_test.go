@@ -0,0 +1,66 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPaper(t *testing.T) {
+	for _, size := range []int{1, PaperLineBytes, 100, 1000} {
+		data := randomBytes(size)
+		text := RenderPaper(data)
+		lines := strings.Split(strings.TrimSpace(text), "\n")
+		// re-typed in lower case, with odd spacing, out of order
+		lines[0], lines[len(lines)-1] = lines[len(lines)-1], lines[0]
+		typed := strings.ToLower(strings.Join(lines, "\n\n  "))
+		got, err := ParsePaper(typed)
+		if err != nil {
+			failNow(t, err)
+		}
+		if !bytes.Equal(got, data) {
+			failNow(t, fmt.Errorf("data mismatch for size %d", size))
+		}
+	}
+}
+
+func TestPaperExample(t *testing.T) {
+	text := RenderPaper([]byte("1234567890123456789012345"))
+	want := "01  GEZD GNBV GY3T QOJQ GEZD GNBV  7S\n" +
+		"02  GY3T QOJQ GEZD GNBV  3F\n" +
+		"END 02  FP 6JKD-77NU-V74S-MWWQ\n"
+	if text != want {
+		failNow(t, fmt.Errorf("unexpected layout\n%s", text))
+	}
+}
+
+func TestPaperErrors(t *testing.T) {
+	data := randomBytes(100)
+	lines := strings.Split(strings.TrimSpace(RenderPaper(data)), "\n")
+	typo := append([]string{}, lines...)
+	b := []byte(typo[3])
+	if b[4] == 'A' {
+		b[4] = 'B'
+	} else {
+		b[4] = 'A'
+	}
+	typo[3] = string(b)
+	_, err := ParsePaper(strings.Join(typo, "\n"))
+	if le, ok := err.(*PaperLineError); !ok || le.Line != 4 {
+		failNow(t, fmt.Errorf("unexpected error %v", err))
+	}
+	missing := append(append([]string{}, lines[:2]...), lines[3:]...)
+	if _, err := ParsePaper(strings.Join(missing, "\n")); err != ErrMissingLine {
+		failNow(t, expected(ErrMissingLine, err))
+	}
+	if _, err := ParsePaper(strings.Join(lines[:len(lines)-1], "\n")); err != ErrMissingLine {
+		failNow(t, expected(ErrMissingLine, err))
+	}
+	footer := append([]string{}, lines...)
+	other := RenderPaper(randomBytes(100))
+	footer[len(footer)-1] = other[strings.Index(other, "END"):]
+	if _, err := ParsePaper(strings.Join(footer, "\n")); err != ErrFingerprint {
+		failNow(t, expected(ErrFingerprint, err))
+	}
+}
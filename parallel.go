@@ -0,0 +1,124 @@
+package tss
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// parallelBlockBytes is the number of secret bytes a worker splits at a time
+const parallelBlockBytes = 512
+
+// parallelJob is a block of secret bytes starting at start, with the
+// polynomial coefficients of every byte, threshold bytes each
+type parallelJob struct {
+	start        int
+	coefficients []byte
+}
+
+// createParallel fills shares like the sequential loop of createShares, with
+// cfg.parallelism workers. The coefficients are drawn from random in the same
+// order, so the shares do not depend on the number of workers.
+func createParallel(ctx context.Context, secret []byte, shares ShareSet, threshold int, cfg *config, random io.Reader) error {
+	jobs := make(chan parallelJob, cfg.parallelism)
+	failed := make(chan error, cfg.parallelism)
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rows, err := cfg.alloc(parallelBlockBytes * threshold)
+			if err != nil {
+				failed <- err
+				for job := range jobs {
+					cfg.free(job.coefficients)
+				}
+				return
+			}
+			defer cfg.free(rows)
+			for job := range jobs {
+				n := len(job.coefficients) / threshold
+				evalBatch(cfg.field(), shares, secret[job.start:job.start+n], job.start, job.coefficients, rows, threshold)
+				cfg.free(job.coefficients)
+			}
+		}()
+	}
+	err := dealJobs(ctx, jobs, len(secret), threshold, cfg, random)
+	close(jobs)
+	wg.Wait()
+	if err == nil && len(failed) > 0 {
+		err = <-failed
+	}
+	return err
+}
+
+// dealJobs draws the coefficients of every block and sends the blocks to the
+// workers
+func dealJobs(ctx context.Context, jobs chan<- parallelJob, secretSize int, threshold int, cfg *config, random io.Reader) error {
+	for start := 0; start < secretSize; start += parallelBlockBytes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n := secretSize - start
+		if n > parallelBlockBytes {
+			n = parallelBlockBytes
+		}
+		coefficients, err := cfg.alloc(n * threshold)
+		if err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(random, coefficients); err != nil {
+			cfg.free(coefficients)
+			return err
+		}
+		jobs <- parallelJob{start: start, coefficients: coefficients}
+	}
+	return nil
+}
+
+// recoverParallel interpolates at 0 every byte of secret from shares checked
+// by checkShares, with cfg.parallelism workers taking blocks of bytes
+func recoverParallel(ctx context.Context, secret []byte, shares ShareSet, cfg *config) error {
+	n := len(shares)
+	scratch, err := cfg.alloc(2 * n)
+	if err != nil {
+		return err
+	}
+	defer cfg.free(scratch)
+	u, weights := scratch[:n], scratch[n:]
+	for i, s := range shares {
+		u[i] = s[0]
+	}
+	field := cfg.field()
+	field.weights(weights, u, 0)
+	blocks := make(chan int, cfg.parallelism)
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ys := make([][]byte, n)
+			for start := range blocks {
+				end := start + parallelBlockBytes
+				if end > len(secret) {
+					end = len(secret)
+				}
+				for i, s := range shares {
+					ys[i] = s[1+start : 1+end]
+				}
+				field.combine(secret[start:end], weights, ys)
+			}
+		}()
+	}
+	for start := 0; start < len(secret) && err == nil; start += parallelBlockBytes {
+		if err = ctx.Err(); err == nil {
+			blocks <- start
+		}
+	}
+	close(blocks)
+	wg.Wait()
+	if err != nil {
+		erase(secret)
+	}
+	return err
+}
@@ -0,0 +1,56 @@
+package tss
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	mrand "math/rand"
+	"testing"
+)
+
+func TestWithParallelism(t *testing.T) {
+	secret := randomBytes(MaxSecretBytes)
+	sequential, err := CreateShares(secret, 20, 7, WithRand(mrand.New(mrand.NewSource(1))))
+	if err != nil {
+		failNow(t, err)
+	}
+	for _, n := range []int{0, 2, 5} {
+		parallel, err := CreateShares(secret, 20, 7, WithRand(mrand.New(mrand.NewSource(1))), WithParallelism(n))
+		if err != nil {
+			failNow(t, err)
+		}
+		for i := range parallel {
+			if !bytes.Equal(parallel[i], sequential[i]) {
+				failNow(t, fmt.Errorf("parallelism %d: share %d differs", n, i+1))
+			}
+		}
+	}
+	for _, n := range []int{0, 3} {
+		recovered, err := RecoverSecret(sequential[5:12], WithParallelism(n))
+		if err != nil || !bytes.Equal(recovered, secret) {
+			failNow(t, fmt.Errorf("parallelism %d: secret mismatch %v", n, err))
+		}
+	}
+	large := randomBytes(3*ChunkBytes + 7)
+	chunked, _ := CreateChunkedShares(large, 5, 3, WithParallelism(0))
+	recovered, err := RecoverChunkedSecret(chunked[2:], WithParallelism(0))
+	if err != nil || !bytes.Equal(recovered, large) {
+		failNow(t, fmt.Errorf("chunked secret mismatch %v", err))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := CreateSharesContext(ctx, secret, 20, 7, WithParallelism(4)); err != context.Canceled {
+		failNow(t, expected(context.Canceled, err))
+	}
+	if _, err := RecoverSecretContext(ctx, sequential[:7], WithParallelism(4)); err != context.Canceled {
+		failNow(t, expected(context.Canceled, err))
+	}
+}
+
+func BenchmarkCreateSharesParallel(b *testing.B) {
+	secret := randomBytes(MaxSecretBytes)
+	b.SetBytes(int64(len(secret)))
+	for i := 0; i < b.N; i++ {
+		CreateShares(secret, 255, 128, WithParallelism(0))
+	}
+}
@@ -0,0 +1,158 @@
+package tss
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// protectedMagic starts a passphrase protected share
+	protectedMagic = "TSSPASS"
+	// protectedVersion is the version of the passphrase protected shares
+	protectedVersion = 1
+	// protectedHeaderBytes is the size of the header of a protected share:
+	// magic, version, index, Argon2id time, memory and threads, salt, nonce
+	protectedHeaderBytes = len(protectedMagic) + 1 + 1 + 4 + 4 + 1 + 16 + 12
+
+	// DefaultArgon2Time, DefaultArgon2Memory, in KiB, and
+	// DefaultArgon2Threads are the Argon2id parameters of ProtectShare, those
+	// recommended by RFC 9106 for memory constrained settings
+	DefaultArgon2Time    = 3
+	DefaultArgon2Memory  = 64 * 1024
+	DefaultArgon2Threads = 4
+	// MaxArgon2Memory is the most memory, in KiB, a protected share may ask
+	// to unwrap it
+	MaxArgon2Memory = 4 * 1024 * 1024
+	// MaxArgon2Time is the most passes a protected share may ask to unwrap it
+	MaxArgon2Time = 64
+)
+
+var (
+	ErrWrongPassphrase   = integrityError("wrong passphrase or corrupt share")
+	ErrInvalidProtected  = validationError("invalid protected share")
+	ErrArgon2ParamsLimit = limitsError("passphrase hash parameters too large")
+)
+
+// WithArgon2Params sets the Argon2id parameters ProtectShare derives the key
+// from the passphrase with: passes, memory in KiB and threads. They are
+// recorded in the protected share, unwrapping needs no option.
+func WithArgon2Params(time uint32, memory uint32, threads uint8) Option {
+	return func(c *config) {
+		if time >= 1 && time <= MaxArgon2Time && memory >= 8*uint32(threads) && memory <= MaxArgon2Memory && threads >= 1 {
+			c.argonTime, c.argonMemory, c.argonThreads = time, memory, threads
+		}
+	}
+}
+
+// ProtectShare encrypts share with AES-256-GCM under a key derived from
+// passphrase with Argon2id, so a stolen share file is useless without the
+// passphrase of its custodian. The index stays in the clear, to tell which
+// passphrase to ask for.
+func ProtectShare(share Share, passphrase []byte, opts ...Option) ([]byte, error) {
+	cfg := newConfig(opts)
+	if !share.Valid() {
+		return nil, ErrInvalidShare
+	}
+	time, memory, threads := uint32(DefaultArgon2Time), uint32(DefaultArgon2Memory), uint8(DefaultArgon2Threads)
+	if cfg.argonTime != 0 {
+		time, memory, threads = cfg.argonTime, cfg.argonMemory, cfg.argonThreads
+	}
+	header := make([]byte, protectedHeaderBytes)
+	p := header[copy(header, protectedMagic):]
+	p[0], p[1] = protectedVersion, share[0]
+	binary.BigEndian.PutUint32(p[2:], time)
+	binary.BigEndian.PutUint32(p[6:], memory)
+	p[10] = threads
+	if _, err := io.ReadFull(cfg.random(), p[11:]); err != nil {
+		return nil, err
+	}
+	aead, nonce, err := protectedAEAD(header, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(header, nonce, share[1:], header), nil
+}
+
+// protectedAEAD derives the key of a protected share from its header and
+// passphrase, and returns its AEAD and nonce
+func protectedAEAD(header []byte, passphrase []byte) (cipher.AEAD, []byte, error) {
+	p := header[len(protectedMagic)+2:]
+	time, memory, threads := binary.BigEndian.Uint32(p), binary.BigEndian.Uint32(p[4:]), p[8]
+	if time < 1 || threads < 1 {
+		return nil, nil, ErrInvalidProtected
+	}
+	if time > MaxArgon2Time || memory > MaxArgon2Memory {
+		return nil, nil, ErrArgon2ParamsLimit
+	}
+	key := argon2.IDKey(passphrase, p[9:25], time, memory, threads, 32)
+	defer erase(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, p[25:37], nil
+}
+
+// ProtectedShareIndex returns the index of a share protected by ProtectShare
+func ProtectedShareIndex(protected []byte) (byte, error) {
+	if len(protected) < protectedHeaderBytes || !bytes.HasPrefix(protected, []byte(protectedMagic)) {
+		return 0, ErrInvalidProtected
+	}
+	if protected[len(protectedMagic)] != protectedVersion {
+		return 0, ErrUnsupportedVersion
+	}
+	return protected[len(protectedMagic)+1], nil
+}
+
+// UnprotectShare decrypts a share protected by ProtectShare
+func UnprotectShare(protected []byte, passphrase []byte) (Share, error) {
+	index, err := ProtectedShareIndex(protected)
+	if err != nil {
+		return nil, err
+	}
+	header := protected[:protectedHeaderBytes]
+	aead, nonce, err := protectedAEAD(header, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	share, err := aead.Open(Share{index}, nonce, protected[protectedHeaderBytes:], header)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return share, nil
+}
+
+// RecoverProtectedSecret recovers the secret from shares protected by
+// ProtectShare, asking passphrase for the passphrase of every index. A
+// share failing to unwrap gives a *ShareError naming it.
+func RecoverProtectedSecret(protected [][]byte, passphrase func(index byte) ([]byte, error), opts ...Option) ([]byte, error) {
+	shares := make(ShareSet, 0, len(protected))
+	defer func() {
+		eraseShares(shares)
+	}()
+	for i, p := range protected {
+		index, err := ProtectedShareIndex(p)
+		if err != nil {
+			return nil, &ShareError{Position: i + 1, Reason: err}
+		}
+		pass, err := passphrase(index)
+		if err != nil {
+			return nil, err
+		}
+		share, err := UnprotectShare(p, pass)
+		if err != nil {
+			return nil, &ShareError{Position: i + 1, Index: index, Reason: err}
+		}
+		shares = append(shares, share)
+	}
+	return RecoverSecret(shares, opts...)
+}
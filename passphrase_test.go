@@ -0,0 +1,55 @@
+package tss
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fastArgon2 keeps the tests quick, real deployments use the defaults
+var fastArgon2 = WithArgon2Params(1, 64, 1)
+
+func TestProtectShare(t *testing.T) {
+	secret := randomBytes(32)
+	shares, _ := CreateShares(secret, 5, 3)
+	protected := make([][]byte, len(shares))
+	for i, s := range shares {
+		var err error
+		if protected[i], err = ProtectShare(s, []byte(fmt.Sprintf("custodian %d", s[0])), fastArgon2); err != nil {
+			failNow(t, err)
+		}
+	}
+	if index, _ := ProtectedShareIndex(protected[3]); index != 4 {
+		failNow(t, fmt.Errorf("index %d, want 4", index))
+	}
+	passphrase := func(index byte) ([]byte, error) {
+		return []byte(fmt.Sprintf("custodian %d", index)), nil
+	}
+	recovered, err := RecoverProtectedSecret(protected[1:4], passphrase)
+	if err != nil {
+		failNow(t, err)
+	}
+	testRecover(t, recovered, shares[:3])
+	if _, err := UnprotectShare(protected[0], []byte("custodian 2")); err != ErrWrongPassphrase {
+		failNow(t, expected(ErrWrongPassphrase, err))
+	}
+	protected[2][len(protected[2])-1] ^= 1
+	_, err = RecoverProtectedSecret(protected[:3], passphrase)
+	var shareErr *ShareError
+	if !errors.As(err, &shareErr) || shareErr.Index != 3 || !errors.Is(err, ErrWrongPassphrase) {
+		failNow(t, expected(ErrWrongPassphrase, err))
+	}
+}
+
+func TestProtectShareLimits(t *testing.T) {
+	shares, _ := CreateShares(randomBytes(16), 3, 2)
+	protected, _ := ProtectShare(shares[0], []byte("pass"), fastArgon2)
+	// a share asking for 8 GiB of memory
+	protected[len(protectedMagic)+6] = 0x80
+	if _, err := UnprotectShare(protected, []byte("pass")); err != ErrArgon2ParamsLimit {
+		failNow(t, expected(ErrArgon2ParamsLimit, err))
+	}
+	if _, err := UnprotectShare(protected[:20], []byte("pass")); err != ErrInvalidProtected {
+		failNow(t, expected(ErrInvalidProtected, err))
+	}
+}
@@ -0,0 +1,161 @@
+package tss
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"sync"
+)
+
+// pedersenDomain is hashed to find the second generator H of P-256
+const pedersenDomain = "go-tss pedersen P-256 H"
+
+var (
+	pedersenOnce sync.Once
+	pedersenHX   *big.Int
+	pedersenHY   *big.Int
+)
+
+// pedersenH returns the generator H, the first point whose x coordinate is
+// the SHA-256 of pedersenDomain and a counter, so nobody knows its discrete
+// logarithm in base G
+func pedersenH() (*big.Int, *big.Int) {
+	pedersenOnce.Do(func() {
+		curve := elliptic.P256()
+		for counter := byte(0); ; counter++ {
+			h := sha256.Sum256(append([]byte(pedersenDomain), counter))
+			x, y := elliptic.UnmarshalCompressed(curve, append([]byte{2}, h[:]...))
+			if x != nil {
+				pedersenHX, pedersenHY = x, y
+				return
+			}
+		}
+	})
+	return pedersenHX, pedersenHY
+}
+
+// PedersenShare is a share of a Pedersen split: the value of the polynomial
+// of the secret at Index, and the value of the blinding polynomial
+type PedersenShare struct {
+	Index    byte
+	Value    []byte
+	Blinding []byte
+}
+
+// PedersenCommitments are the commitments a_k*G+b_k*H of a Pedersen split to
+// the coefficients a_k of the polynomial of the secret and b_k of the
+// blinding polynomial. Unlike Feldman's, they tell nothing about the secret,
+// whatever the computing power of who holds them.
+type PedersenCommitments Commitments
+
+// SplitPedersen splits a P-256 scalar, 32 bytes big endian, with Pedersen's
+// verifiable scheme. Custodians check their share against the commitments
+// with Verify as with SplitFeldman, but the commitments are hiding: the
+// secret may be guessable. The binding of the commitments relies on the
+// discrete logarithm of H being unknown.
+func SplitPedersen(secret []byte, sharesCount int, threshold int, opts ...Option) ([]PedersenShare, PedersenCommitments, error) {
+	cfg := newConfig(opts)
+	s, err := P256.decode(secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer eraseInt(s)
+	if sharesCount < MinShares {
+		return nil, nil, ErrTooFewShares
+	}
+	if sharesCount > cfg.sharesLimit() {
+		return nil, nil, ErrTooManyShares
+	}
+	if threshold > sharesCount || threshold < cfg.minThreshold() {
+		return nil, nil, ErrInvalidThreshold
+	}
+	random, err := cfg.source(secret, threshold)
+	if err != nil {
+		return nil, nil, err
+	}
+	indexes, err := shareIndexes(random, sharesCount, cfg.randomIndexes)
+	if err != nil {
+		return nil, nil, err
+	}
+	curve := elliptic.P256()
+	order := curve.Params().N
+	a, err := primePolynomial(s, order, threshold, random)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer eraseInts(a)
+	blinding, err := rand.Int(random, order)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := primePolynomial(blinding, order, threshold, random)
+	eraseInt(blinding)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer eraseInts(b)
+	hx, hy := pedersenH()
+	commitments := make(PedersenCommitments, threshold)
+	for k := range a {
+		scalar := P256.encode(a[k])
+		gx, gy := curve.ScalarBaseMult(scalar)
+		erase(scalar)
+		scalar = P256.encode(b[k])
+		bx, by := curve.ScalarMult(hx, hy, scalar)
+		erase(scalar)
+		x, y := curve.Add(gx, gy, bx, by)
+		commitments[k] = elliptic.MarshalCompressed(curve, x, y)
+	}
+	shares := make([]PedersenShare, sharesCount)
+	for i, x := range indexes {
+		u := big.NewInt(int64(x))
+		y, z := primeEval(u, a, order), primeEval(u, b, order)
+		shares[i] = PedersenShare{Index: x, Value: P256.encode(y), Blinding: P256.encode(z)}
+		eraseInt(y)
+		eraseInt(z)
+	}
+	return shares, commitments, nil
+}
+
+// Verify checks share lies on the committed polynomials: value*G+blinding*H
+// must equal the sum of index^k times the k-th commitment
+func (c PedersenCommitments) Verify(share PedersenShare) error {
+	if share.Index == 0 {
+		return ErrInvalidShareIndex
+	}
+	for _, v := range [][]byte{share.Value, share.Blinding} {
+		x, err := P256.decode(v)
+		if err != nil {
+			return err
+		}
+		eraseInt(x)
+	}
+	x, y, err := Commitments(c).evalAt(share.Index)
+	if err != nil {
+		return err
+	}
+	curve := elliptic.P256()
+	hx, hy := pedersenH()
+	gx, gy := curve.ScalarBaseMult(share.Value)
+	bx, by := curve.ScalarMult(hx, hy, share.Blinding)
+	sx, sy := curve.Add(gx, gy, bx, by)
+	if sx.Cmp(x) != 0 || sy.Cmp(y) != 0 {
+		return ErrShareNotCommitted
+	}
+	return nil
+}
+
+// RecoverPedersen recovers a scalar split by SplitPedersen, checking every
+// share against the commitments first like RecoverFeldman
+func RecoverPedersen(shares []PedersenShare, commitments PedersenCommitments, opts ...Option) ([]byte, error) {
+	opts = append([]Option{WithThreshold(len(commitments))}, opts...)
+	values := make([]ScalarShare, len(shares))
+	for i, s := range shares {
+		if err := commitments.Verify(s); err != nil {
+			return nil, &ShareError{Position: i + 1, Index: s.Index, Reason: err}
+		}
+		values[i] = ScalarShare{Index: s.Index, Value: s.Value}
+	}
+	return RecoverScalar(P256, values, opts...)
+}
@@ -0,0 +1,47 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestPedersen(t *testing.T) {
+	secret := P256.encode(big.NewInt(42))
+	shares, commitments, err := SplitPedersen(secret, 5, 3, WithRandomIndexes())
+	if err != nil {
+		failNow(t, err)
+	}
+	for _, s := range shares {
+		if err := commitments.Verify(s); err != nil {
+			failNow(t, err)
+		}
+	}
+	recovered, err := RecoverPedersen([]PedersenShare{shares[3], shares[1], shares[0]}, commitments)
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+	// the commitments of the same secret differ, the blinding hides it
+	_, again, _ := SplitPedersen(secret, 5, 3)
+	if bytes.Equal(again[0], commitments[0]) {
+		failNow(t, fmt.Errorf("commitments are not hiding"))
+	}
+}
+
+func TestPedersenCheating(t *testing.T) {
+	shares, commitments, _ := SplitPedersen(P256.encode(big.NewInt(42)), 5, 3)
+	forged := shares[2]
+	forged.Blinding = append([]byte{}, forged.Blinding...)
+	forged.Blinding[0] ^= 0x10
+	if err := commitments.Verify(forged); err != ErrShareNotCommitted {
+		failNow(t, expected(ErrShareNotCommitted, err))
+	}
+	if _, err := RecoverPedersen([]PedersenShare{shares[0], shares[1], forged}, commitments); !errors.Is(err, ErrShareNotCommitted) {
+		failNow(t, expected(ErrShareNotCommitted, err))
+	}
+}
@@ -0,0 +1,50 @@
+package tss
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPGPWordList(t *testing.T) {
+	// fingerprint example from the PGP word list documentation
+	data, _ := hex.DecodeString("E58294F2E9A227486E8B061B31CC528FD7FA3F19")
+	want := "topmost Istanbul Pluto vagabond treadmill Pacific brackish dictator goldfish Medusa " +
+		"afflict bravado chatter revolver Dupont midsummer stopwatch whimsical cowbell bottomless"
+	words := EncodeShareWords(data)
+	if !strings.HasPrefix(words, want+" ") {
+		failNow(t, fmt.Errorf("words %q, want prefix %q", words, want))
+	}
+}
+
+func TestShareWords(t *testing.T) {
+	shares, err := CreateShares(randomBytes(32), 3, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	words := EncodeShareWords(shares[2])
+	share, err := DecodeShareWords("  " + strings.ToUpper(strings.Replace(words, " ", " -\n", 5)))
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(share, shares[2]) {
+		failNow(t, fmt.Errorf("share mismatch"))
+	}
+	list := strings.Fields(words)
+	if _, err := DecodeShareWords(strings.Join(append(list[:3:3], list[4:]...), " ")); err != ErrWordOrder {
+		failNow(t, expected(ErrWordOrder, err))
+	}
+	list[3] = "unknown"
+	if _, err := DecodeShareWords(strings.Join(list, " ")); err != ErrUnknownWord {
+		failNow(t, expected(ErrUnknownWord, err))
+	}
+	list = strings.Fields(words)
+	list[0], list[2] = list[2], list[0]
+	if list[0] != list[2] {
+		if _, err := DecodeShareWords(strings.Join(list, " ")); err != ErrChecksum {
+			failNow(t, expected(ErrChecksum, err))
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package piv keeps a tss share in a data object of a PIV card such as a
+// YubiKey, for hardware token custody of shares without an HSM. The object
+// content is tagged, so other data is never read as a share.
+package piv
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/antik10ud/go-tss"
+)
+
+// PIV data objects suited to shares. ObjectPrinted is only read after the
+// PIN is verified; the retired key history objects are read by anyone
+// holding the card.
+const (
+	ObjectPrinted  uint32 = 0x5fc109
+	ObjectRetired1 uint32 = 0x5fc10d
+	ObjectRetired2 uint32 = 0x5fc10e
+	ObjectRetired3 uint32 = 0x5fc10f
+	ObjectRetired4 uint32 = 0x5fc110
+)
+
+// MaxObjectBytes is the largest data object a YubiKey stores
+const MaxObjectBytes = 3052
+
+const (
+	// shareMagic starts the content of an object holding a share
+	shareMagic = "TSSPIV"
+	// shareVersion is the version of that content
+	shareVersion = 1
+)
+
+// Card is a PIV card, authenticated with its management key for PutData.
+// GetData and PutData run GET DATA and PUT DATA on the object, the data
+// being the content of its 0x53 tag; VerifyPIN runs VERIFY on the PIV PIN.
+// These are the few card operations the package needs, so any smart card
+// library fits, such as github.com/go-piv/piv-go through a small adapter.
+type Card interface {
+	GetData(object uint32) ([]byte, error)
+	PutData(object uint32, data []byte) error
+	VerifyPIN(pin string) error
+}
+
+// WriteShare writes share to object, verifying pin first when it is not
+// empty, as PIN protected objects require
+func WriteShare(card Card, object uint32, share tss.Share, pin string) error {
+	if !share.Valid() {
+		return tss.ErrInvalidShare
+	}
+	if pin != "" {
+		if err := card.VerifyPIN(pin); err != nil {
+			return err
+		}
+	}
+	data := append(append([]byte(shareMagic), shareVersion), share...)
+	defer tss.Wipe(data)
+	if len(data)+4 > MaxObjectBytes {
+		return fmt.Errorf("share of %d bytes too large for a PIV object: %w", len(share), tss.ErrLimits)
+	}
+	return card.PutData(object, data)
+}
+
+// ReadShare reads the share written by WriteShare to object, verifying pin
+// first when it is not empty
+func ReadShare(card Card, object uint32, pin string) (tss.Share, error) {
+	if pin != "" {
+		if err := card.VerifyPIN(pin); err != nil {
+			return nil, err
+		}
+	}
+	data, err := card.GetData(object)
+	if err != nil {
+		return nil, err
+	}
+	defer tss.Wipe(data)
+	if !bytes.HasPrefix(data, []byte(shareMagic)) || len(data) < len(shareMagic)+1 {
+		return nil, fmt.Errorf("PIV object %06x holds no share: %w", object, tss.ErrValidation)
+	}
+	if data[len(shareMagic)] != shareVersion {
+		return nil, tss.ErrUnsupportedVersion
+	}
+	share := append(tss.Share{}, data[len(shareMagic)+1:]...)
+	if !share.Valid() {
+		return nil, tss.ErrInvalidShare
+	}
+	return share, nil
+}
@@ -0,0 +1,87 @@
+package piv
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/antik10ud/go-tss"
+)
+
+var errSecurity = errors.New("security status not satisfied")
+
+// card is an in memory Card, ObjectPrinted read only after VerifyPIN
+type card struct {
+	pin      string
+	verified bool
+	objects  map[uint32][]byte
+}
+
+func (c *card) GetData(object uint32) ([]byte, error) {
+	if object == ObjectPrinted && !c.verified {
+		return nil, errSecurity
+	}
+	data, ok := c.objects[object]
+	if !ok {
+		return nil, errors.New("object not found")
+	}
+	return append([]byte{}, data...), nil
+}
+
+func (c *card) PutData(object uint32, data []byte) error {
+	c.objects[object] = append([]byte{}, data...)
+	return nil
+}
+
+func (c *card) VerifyPIN(pin string) error {
+	if pin != c.pin {
+		return errSecurity
+	}
+	c.verified = true
+	return nil
+}
+
+func TestShare(t *testing.T) {
+	secret := make([]byte, 32)
+	rand.Read(secret)
+	shares, err := tss.CreateShares(secret, 3, 2, tss.WithConsumeSecret(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &card{pin: "123456", objects: map[uint32][]byte{}}
+	if err := WriteShare(c, ObjectPrinted, shares[0], "123456"); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteShare(c, ObjectRetired1, shares[1], ""); err != nil {
+		t.Fatal(err)
+	}
+	c.verified = false
+	if _, err := ReadShare(c, ObjectPrinted, ""); err != errSecurity {
+		t.Fatal(err)
+	}
+	if _, err := ReadShare(c, ObjectPrinted, "000000"); err != errSecurity {
+		t.Fatal(err)
+	}
+	a, err := ReadShare(c, ObjectPrinted, "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ReadShare(c, ObjectRetired1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered, err := tss.RecoverSecret(tss.ShareSet{a, b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(recovered) != string(secret) {
+		t.Fatal("recovered secret mismatch")
+	}
+	c.objects[ObjectRetired2] = []byte("certificate")
+	if _, err := ReadShare(c, ObjectRetired2, ""); !errors.Is(err, tss.ErrValidation) {
+		t.Fatal(err)
+	}
+	if err := WriteShare(c, ObjectRetired2, append(tss.Share{1}, make([]byte, MaxObjectBytes)...), ""); !errors.Is(err, tss.ErrLimits) {
+		t.Fatal(err)
+	}
+}
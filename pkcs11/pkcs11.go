@@ -0,0 +1,111 @@
+// Package pkcs11 keeps tss shares in a PKCS#11 token, so they never sit
+// unencrypted on disk: as private data objects of the token, or encrypted by
+// a key of the token when it is too small to hold them.
+package pkcs11
+
+import (
+	"fmt"
+
+	"github.com/antik10ud/go-tss"
+)
+
+// Session is a logged in session of a PKCS#11 token. Objects are CKO_DATA
+// objects with CKA_TOKEN and CKA_PRIVATE set, found by CKA_LABEL; FindObject
+// and DestroyObject return tss.ErrShareNotFound when there is none.
+// Encrypt and Decrypt run an authenticated mechanism, such as CKM_AES_GCM,
+// with the secret key object of the label, which never leaves the token.
+// The package has no cgo of its own: wrap the session of a binding such as
+// github.com/miekg/pkcs11.
+type Session interface {
+	CreateObject(label string, value []byte) error
+	FindObject(label string) ([]byte, error)
+	DestroyObject(label string) error
+	Encrypt(key string, plaintext []byte) ([]byte, error)
+	Decrypt(key string, ciphertext []byte) ([]byte, error)
+}
+
+// ObjectStore is a tss.ShareStore writing each share as a private data
+// object of the token, under LabelPrefix followed by the label
+type ObjectStore struct {
+	Session     Session
+	LabelPrefix string
+}
+
+// Put replaces the object of label with the share
+func (s ObjectStore) Put(label string, share tss.Share) error {
+	if !share.Valid() {
+		return tss.ErrInvalidShare
+	}
+	if err := s.Session.DestroyObject(s.LabelPrefix + label); err != nil && err != tss.ErrShareNotFound {
+		return err
+	}
+	return s.Session.CreateObject(s.LabelPrefix+label, share)
+}
+
+// Get reads the share of label
+func (s ObjectStore) Get(label string) (tss.Share, error) {
+	value, err := s.Session.FindObject(s.LabelPrefix + label)
+	if err != nil {
+		return nil, err
+	}
+	if share := tss.Share(value); share.Valid() {
+		return share, nil
+	}
+	return nil, tss.ErrInvalidShare
+}
+
+// Delete destroys the object of label
+func (s ObjectStore) Delete(label string) error {
+	return s.Session.DestroyObject(s.LabelPrefix + label)
+}
+
+// WrappedStore is a tss.ShareStore encrypting each share with the token key
+// Key before writing it to Store as a blob, for tokens too small to hold the
+// shares. The label is prepended to the share before encryption, so a
+// wrapped share moved to another label no longer reads.
+type WrappedStore struct {
+	Session Session
+	Key     string
+	Store   tss.BlobStore
+}
+
+// Put wraps the share and writes it to the underlying store
+func (s WrappedStore) Put(label string, share tss.Share) error {
+	if !share.Valid() {
+		return tss.ErrInvalidShare
+	}
+	plaintext := append(append([]byte(label), 0), share...)
+	defer tss.Wipe(plaintext)
+	wrapped, err := s.Session.Encrypt(s.Key, plaintext)
+	if err != nil {
+		return err
+	}
+	return s.Store.PutBlob(label, wrapped)
+}
+
+// Get reads the share of label from the underlying store and unwraps it
+func (s WrappedStore) Get(label string) (tss.Share, error) {
+	wrapped, err := s.Store.GetBlob(label)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := s.Session.Decrypt(s.Key, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapping share %q: %w", label, tss.ErrIntegrity)
+	}
+	defer tss.Wipe(plaintext)
+	n := len(label)
+	if len(plaintext) <= n || string(plaintext[:n]) != label || plaintext[n] != 0 {
+		return nil, fmt.Errorf("share %q wrapped for another label: %w", label, tss.ErrIntegrity)
+	}
+	share := append(tss.Share{}, plaintext[n+1:]...)
+	if !share.Valid() {
+		return nil, tss.ErrInvalidShare
+	}
+	return share, nil
+}
+
+// Delete removes the wrapped share of label from the underlying store
+func (s WrappedStore) Delete(label string) error {
+	return s.Store.Delete(label)
+}
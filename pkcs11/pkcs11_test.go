@@ -0,0 +1,96 @@
+package pkcs11
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/antik10ud/go-tss"
+	"github.com/antik10ud/go-tss/internal/storetest"
+)
+
+// token is an in memory Session with AES-GCM keys
+type token struct {
+	objects map[string][]byte
+	keys    map[string]cipher.AEAD
+}
+
+func newToken(keys ...string) *token {
+	t := &token{objects: map[string][]byte{}, keys: map[string]cipher.AEAD{}}
+	for _, k := range keys {
+		key := make([]byte, 32)
+		rand.Read(key)
+		block, _ := aes.NewCipher(key)
+		t.keys[k], _ = cipher.NewGCM(block)
+	}
+	return t
+}
+
+func (t *token) CreateObject(label string, value []byte) error {
+	t.objects[label] = append([]byte{}, value...)
+	return nil
+}
+
+func (t *token) FindObject(label string) ([]byte, error) {
+	if v, ok := t.objects[label]; ok {
+		return append([]byte{}, v...), nil
+	}
+	return nil, tss.ErrShareNotFound
+}
+
+func (t *token) DestroyObject(label string) error {
+	if _, ok := t.objects[label]; !ok {
+		return tss.ErrShareNotFound
+	}
+	delete(t.objects, label)
+	return nil
+}
+
+func (t *token) Encrypt(key string, plaintext []byte) ([]byte, error) {
+	aead := t.keys[key]
+	nonce := make([]byte, aead.NonceSize())
+	rand.Read(nonce)
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (t *token) Decrypt(key string, ciphertext []byte) ([]byte, error) {
+	aead := t.keys[key]
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("short ciphertext")
+	}
+	return aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], nil)
+}
+
+func TestObjectStore(t *testing.T) {
+	storetest.Run(t, ObjectStore{Session: newToken(), LabelPrefix: "tss-"})
+}
+
+func TestWrappedStore(t *testing.T) {
+	session := newToken("wrap")
+	dir := tss.DirStore{Dir: t.TempDir()}
+	store := WrappedStore{Session: session, Key: "wrap", Store: dir}
+	storetest.Run(t, store)
+	b, err := dir.GetBlob("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dir.PutBlob("c", b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("c"); !errors.Is(err, tss.ErrIntegrity) {
+		t.Fatal(err)
+	}
+	// the largest shares wrap although the blobs outgrow them
+	shares, err := tss.CreateShares(make([]byte, tss.MaxSecretBytes), 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("d", shares[0]); err != nil {
+		t.Fatal(err)
+	}
+	if d, err := store.Get("d"); err != nil || string(d) != string(shares[0]) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,61 @@
+package tss
+
+import (
+	"crypto"
+	"crypto/x509"
+)
+
+var (
+	ErrInvalidPrivateKey = validationError("invalid private key")
+)
+
+// SplitPrivateKey splits the PKCS#8 encoding of key, an *rsa.PrivateKey,
+// *ecdsa.PrivateKey, ed25519.PrivateKey or *ecdh.PrivateKey as accepted by
+// x509.MarshalPKCS8PrivateKey
+func SplitPrivateKey(key crypto.PrivateKey, sharesCount int, threshold int, opts ...Option) (ShareSet, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	defer erase(der)
+	return CreateShares(der, sharesCount, threshold, append(opts, WithConsumeSecret(false))...)
+}
+
+// SplitPKCS8 splits a PKCS#8 DER private key, as found in the PRIVATE KEY
+// PEM blocks of CA and TLS keys. The key must parse, so that a quorum never
+// rebuilds garbage.
+func SplitPKCS8(der []byte, sharesCount int, threshold int, opts ...Option) (ShareSet, error) {
+	if _, err := x509.ParsePKCS8PrivateKey(der); err != nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	return CreateShares(der, sharesCount, threshold, opts...)
+}
+
+// RecoverPKCS8 recovers the PKCS#8 DER private key split by SplitPKCS8 or
+// SplitPrivateKey
+func RecoverPKCS8(shares ShareSet, opts ...Option) ([]byte, error) {
+	der, err := RecoverSecret(shares, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := x509.ParsePKCS8PrivateKey(der); err != nil {
+		erase(der)
+		return nil, ErrInvalidPrivateKey
+	}
+	return der, nil
+}
+
+// RecoverPrivateKey recovers the private key split by SplitPrivateKey or
+// SplitPKCS8, parsed as by x509.ParsePKCS8PrivateKey
+func RecoverPrivateKey(shares ShareSet, opts ...Option) (crypto.PrivateKey, error) {
+	der, err := RecoverSecret(shares, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer erase(der)
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	return key, nil
+}
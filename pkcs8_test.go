@@ -0,0 +1,59 @@
+package tss
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+)
+
+func TestSplitPrivateKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		failNow(t, err)
+	}
+	shares, err := SplitPrivateKey(key, 5, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	recovered, err := RecoverPrivateKey(shares.Subset(1, 3, 4))
+	if err != nil {
+		failNow(t, err)
+	}
+	if !key.Equal(recovered) {
+		failNow(t, ErrInvalidPrivateKey)
+	}
+	if _, err := RecoverPrivateKey(shares.Subset(1, 3)); err == nil {
+		failNow(t, expected(ErrThresholdNotMet, err))
+	}
+	if _, err := SplitPrivateKey("key", 5, 3); err != ErrInvalidPrivateKey {
+		failNow(t, expected(ErrInvalidPrivateKey, err))
+	}
+}
+
+func TestSplitPKCS8(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	split, err := SplitPrivateKey(key, 3, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	pkcs8, err := RecoverPKCS8(split[1:])
+	if err != nil {
+		failNow(t, err)
+	}
+	shares, err := SplitPKCS8(pkcs8, 3, 2, WithConsumeSecret(false))
+	if err != nil {
+		failNow(t, err)
+	}
+	recovered, err := RecoverPrivateKey(shares[:2])
+	if err != nil {
+		failNow(t, err)
+	}
+	if !key.Equal(recovered) {
+		failNow(t, ErrInvalidPrivateKey)
+	}
+	if _, err := SplitPKCS8(randomBytes(64), 3, 2); err != ErrInvalidPrivateKey {
+		failNow(t, expected(ErrInvalidPrivateKey, err))
+	}
+}
@@ -0,0 +1,227 @@
+package tss
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// PolicyError reports a policy that does not parse
+type PolicyError struct {
+	// Offset is the byte offset of the problem in the policy
+	Offset int
+	Msg    string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("policy: %s at offset %d", e.Msg, e.Offset)
+}
+
+func (e *PolicyError) Unwrap() error {
+	return ErrInvalidAccessStructure
+}
+
+// And is a member requiring all of members
+func And(members ...Member) Member {
+	return Group(len(members), members...)
+}
+
+// Or is a member requiring any of members
+func Or(members ...Member) Member {
+	return Group(1, members...)
+}
+
+// ParsePolicy compiles a policy over named parties into an AccessStructure.
+// The language has parties, "and", "or", "k of (...)" and parentheses, "and"
+// binding tighter than "or":
+//
+//	2 of (alice, bob, carol) and (it1 or it2)
+//
+// Party names are made of letters, digits and the characters _ - . @
+// A malformed policy is reported as a *PolicyError.
+func ParsePolicy(policy string) (*AccessStructure, error) {
+	p := &policyParser{s: policy}
+	m, err := p.expr(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok, off := p.next(); tok != "" {
+		return nil, &PolicyError{Offset: off, Msg: fmt.Sprintf("unexpected %q", tok)}
+	}
+	a := m.Group
+	if a == nil {
+		a = NewAccessStructure(1, m)
+	}
+	if err := a.validate(0); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Satisfied reports whether the parties together satisfy the policy
+func (a *AccessStructure) Satisfied(parties ...string) bool {
+	present := make(map[string]bool, len(parties))
+	for _, p := range parties {
+		present[p] = true
+	}
+	return a.satisfied(present)
+}
+
+func (a *AccessStructure) satisfied(present map[string]bool) bool {
+	n := 0
+	for _, m := range a.Members {
+		if (m.Group == nil && present[m.Party]) || (m.Group != nil && m.Group.satisfied(present)) {
+			n++
+		}
+	}
+	return n >= a.Threshold
+}
+
+type policyParser struct {
+	s   string
+	pos int
+}
+
+// next consumes and returns the next token and its offset, "" at the end
+func (p *policyParser) next() (string, int) {
+	tok, off, end := p.scan()
+	p.pos = end
+	return tok, off
+}
+
+// peek returns the next token without consuming it
+func (p *policyParser) peek() string {
+	tok, _, _ := p.scan()
+	return tok
+}
+
+func (p *policyParser) scan() (tok string, off int, end int) {
+	i := p.pos
+	for i < len(p.s) && unicode.IsSpace(rune(p.s[i])) {
+		i++
+	}
+	if i == len(p.s) {
+		return "", i, i
+	}
+	if strings.IndexByte("(),", p.s[i]) >= 0 {
+		return p.s[i : i+1], i, i + 1
+	}
+	j := i
+	for j < len(p.s) && isPolicyNameByte(p.s[j]) {
+		j++
+	}
+	if j == i {
+		return p.s[i : i+1], i, i + 1
+	}
+	return p.s[i:j], i, j
+}
+
+func isPolicyNameByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("_-.@", c) >= 0
+}
+
+// expr parses terms joined by "or"
+func (p *policyParser) expr(depth int) (Member, error) {
+	if depth >= MaxAccessDepth {
+		return Member{}, &PolicyError{Offset: p.pos, Msg: "nesting too deep"}
+	}
+	m, err := p.term(depth)
+	if err != nil {
+		return Member{}, err
+	}
+	members := []Member{m}
+	for strings.EqualFold(p.peek(), "or") {
+		p.next()
+		m, err := p.term(depth)
+		if err != nil {
+			return Member{}, err
+		}
+		members = append(members, m)
+	}
+	if len(members) == 1 {
+		return members[0], nil
+	}
+	return Or(members...), nil
+}
+
+// term parses factors joined by "and"
+func (p *policyParser) term(depth int) (Member, error) {
+	m, err := p.factor(depth)
+	if err != nil {
+		return Member{}, err
+	}
+	members := []Member{m}
+	for strings.EqualFold(p.peek(), "and") {
+		p.next()
+		m, err := p.factor(depth)
+		if err != nil {
+			return Member{}, err
+		}
+		members = append(members, m)
+	}
+	if len(members) == 1 {
+		return members[0], nil
+	}
+	return And(members...), nil
+}
+
+// factor parses a party, a parenthesized expression or "k of (...)"
+func (p *policyParser) factor(depth int) (Member, error) {
+	tok, off := p.next()
+	switch {
+	case tok == "":
+		return Member{}, &PolicyError{Offset: off, Msg: "unexpected end"}
+	case tok == "(":
+		m, err := p.expr(depth + 1)
+		if err != nil {
+			return Member{}, err
+		}
+		if err := p.expect(")"); err != nil {
+			return Member{}, err
+		}
+		return m, nil
+	case strings.EqualFold(p.peek(), "of"):
+		k, err := strconv.Atoi(tok)
+		if err != nil || k < 1 {
+			return Member{}, &PolicyError{Offset: off, Msg: fmt.Sprintf("invalid threshold %q", tok)}
+		}
+		p.next()
+		if err := p.expect("("); err != nil {
+			return Member{}, err
+		}
+		var members []Member
+		for {
+			m, err := p.expr(depth + 1)
+			if err != nil {
+				return Member{}, err
+			}
+			members = append(members, m)
+			if p.peek() != "," {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect(")"); err != nil {
+			return Member{}, err
+		}
+		if k > len(members) {
+			return Member{}, &PolicyError{Offset: off, Msg: fmt.Sprintf("threshold %d above %d members", k, len(members))}
+		}
+		return Group(k, members...), nil
+	case !isPolicyNameByte(tok[0]) || strings.EqualFold(tok, "and") || strings.EqualFold(tok, "or") || strings.EqualFold(tok, "of"):
+		return Member{}, &PolicyError{Offset: off, Msg: fmt.Sprintf("unexpected %q", tok)}
+	}
+	return Party(tok), nil
+}
+
+func (p *policyParser) expect(want string) error {
+	tok, off := p.next()
+	if tok != want {
+		if tok == "" {
+			return &PolicyError{Offset: off, Msg: fmt.Sprintf("missing %q", want)}
+		}
+		return &PolicyError{Offset: off, Msg: fmt.Sprintf("expected %q, got %q", want, tok)}
+	}
+	return nil
+}
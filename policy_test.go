@@ -0,0 +1,64 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := ParsePolicy("2 of (alice, bob, carol) and (it1 or it2)")
+	if err != nil {
+		failNow(t, err)
+	}
+	for _, c := range []struct {
+		parties []string
+		want    bool
+	}{
+		{[]string{"alice", "bob", "it2"}, true},
+		{[]string{"carol", "bob", "it1"}, true},
+		{[]string{"alice", "bob", "carol"}, false},
+		{[]string{"alice", "it1", "it2"}, false},
+	} {
+		if got := policy.Satisfied(c.parties...); got != c.want {
+			failNow(t, fmt.Errorf("%v: satisfied %v, want %v", c.parties, got, c.want))
+		}
+	}
+	secret := randomBytes(32)
+	bundles, err := policy.Split(secret)
+	if err != nil {
+		failNow(t, err)
+	}
+	if len(bundles) != 5 {
+		failNow(t, fmt.Errorf("%d bundles", len(bundles)))
+	}
+	got, err := policy.Recover([]PartyBundle{bundles[0], bundles[2], bundles[4]})
+	if err != nil || !bytes.Equal(got, secret) {
+		failNow(t, fmt.Errorf("secret mismatch %v", err))
+	}
+}
+
+func TestParsePolicyPrecedence(t *testing.T) {
+	policy, err := ParsePolicy("a or b and c")
+	if err != nil {
+		failNow(t, err)
+	}
+	if !policy.Satisfied("a") || policy.Satisfied("b") || !policy.Satisfied("b", "c") {
+		failNow(t, fmt.Errorf("and must bind tighter than or"))
+	}
+	single, err := ParsePolicy("alice")
+	if err != nil || !single.Satisfied("alice") {
+		failNow(t, fmt.Errorf("single party policy %v", err))
+	}
+}
+
+func TestParsePolicyErrors(t *testing.T) {
+	for _, policy := range []string{"", "a and", "3 of (a, b)", "(a or b", "a b", "0 of (a)", "a, b", "and"} {
+		_, err := ParsePolicy(policy)
+		var pe *PolicyError
+		if !errors.As(err, &pe) || !errors.Is(err, ErrInvalidAccessStructure) {
+			failNow(t, fmt.Errorf("%q: unexpected error %v", policy, err))
+		}
+	}
+}
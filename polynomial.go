@@ -0,0 +1,182 @@
+package tss
+
+import (
+	"crypto/subtle"
+	"io"
+)
+
+// PolynomialAES is the reduction polynomial of GF256, x^8+x^4+x^3+x+1, and
+// PolynomialRS the one of Reed-Solomon codes and some Shamir libraries,
+// x^8+x^4+x^3+x^2+1
+const (
+	PolynomialAES uint16 = 0x11b
+	PolynomialRS  uint16 = 0x11d
+)
+
+var (
+	ErrInvalidPolynomial = validationError("reducible or not degree 8 polynomial")
+)
+
+// NewGF256Field returns GF(2^8) with the reduction polynomial poly, x^8 being
+// bit 8, so shares of libraries using another polynomial than GF256 can be
+// created and recovered with CreateFieldShares and RecoverFieldSecret.
+// Shares over GF(2^8) are laid out as Share; UnmarshalShares reads those of
+// other layouts. PolynomialAES returns GF256 itself.
+func NewGF256Field(poly uint16) (Field, error) {
+	if poly == PolynomialAES {
+		return GF256, nil
+	}
+	if poly>>8 != 1 || !irreducible8(poly) {
+		return nil, ErrInvalidPolynomial
+	}
+	f := &gf256Poly{}
+	// the multiplicative group is cyclic, find a generator and log with it
+	for g := 2; g < 256; g++ {
+		var x byte = 1
+		order := 0
+		for {
+			x = reduceMul(x, byte(g), poly)
+			order++
+			if x == 1 {
+				break
+			}
+		}
+		if order != 255 {
+			continue
+		}
+		x = 1
+		for i := 0; i < 255; i++ {
+			f.exp[i], f.exp[i+255] = x, x
+			f.log[x] = i
+			x = reduceMul(x, byte(g), poly)
+		}
+		break
+	}
+	return f, nil
+}
+
+// irreducible8 reports whether the degree 8 polynomial poly has no factor of
+// degree 1 to 4 over GF(2)
+func irreducible8(poly uint16) bool {
+	for d := uint16(2); d < 32; d++ {
+		r := poly
+		for bitLen16(r) >= bitLen16(d) {
+			r ^= d << (bitLen16(r) - bitLen16(d))
+		}
+		if r == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func bitLen16(x uint16) int {
+	n := 0
+	for ; x != 0; x >>= 1 {
+		n++
+	}
+	return n
+}
+
+// reduceMul multiplies in GF(2^8) modulo poly by shifts and adds
+func reduceMul(x byte, y byte, poly uint16) byte {
+	var r byte
+	for i := 0; i < 8; i++ {
+		if y&1 == 1 {
+			r ^= x
+		}
+		y >>= 1
+		carry := x & 0x80
+		x <<= 1
+		if carry != 0 {
+			x ^= byte(poly)
+		}
+	}
+	return r
+}
+
+// gf256Poly is GF(2^8) with exp and log tables of another polynomial than
+// the one of GF256
+type gf256Poly struct {
+	exp [510]byte
+	log [256]int
+}
+
+func (f *gf256Poly) mul(x byte, y byte) byte {
+	if x == 0 || y == 0 {
+		return 0
+	}
+	return f.exp[f.log[x]+f.log[y]]
+}
+
+func (f *gf256Poly) div(x byte, y byte) byte {
+	if x == 0 {
+		return 0
+	}
+	return f.exp[255+f.log[x]-f.log[y]]
+}
+
+func (f *gf256Poly) ElementBytes() int {
+	return 1
+}
+
+func (f *gf256Poly) Add(dst []byte, a []byte, b []byte) {
+	subtle.XORBytes(dst[:len(a)], a, b)
+}
+
+func (f *gf256Poly) Mul(dst []byte, a []byte, b []byte) {
+	for i := range a {
+		dst[i] = f.mul(a[i], b[i])
+	}
+}
+
+func (f *gf256Poly) Div(dst []byte, a []byte, b []byte) error {
+	for i := range b {
+		if b[i] == 0 {
+			return ErrDivisionByZero
+		}
+	}
+	for i := range a {
+		dst[i] = f.div(a[i], b[i])
+	}
+	return nil
+}
+
+func (f *gf256Poly) Eval(dst []byte, x []byte, coefficients [][]byte) {
+	copy(dst, coefficients[0])
+	var xk byte = 1
+	for _, c := range coefficients[1:] {
+		xk = f.mul(xk, x[0])
+		for i := range c {
+			dst[i] ^= f.mul(xk, c[i])
+		}
+	}
+}
+
+func (f *gf256Poly) Interp(dst []byte, x []byte, xs []byte, ys [][]byte) error {
+	var seen [256]bool
+	for _, u := range xs {
+		if seen[u] {
+			return ErrDivisionByZero
+		}
+		seen[u] = true
+	}
+	fill(dst, 0)
+	for i, y := range ys {
+		var l byte = 1
+		for j := range xs {
+			if j != i {
+				l = f.mul(l, f.div(x[0]^xs[j], xs[j]^xs[i]))
+			}
+		}
+		for k := range y {
+			dst[k] ^= f.mul(l, y[k])
+		}
+	}
+	return nil
+}
+
+func (f *gf256Poly) Random(dst []byte, random io.Reader) error {
+	_, err := io.ReadFull(random, dst)
+	return err
+}
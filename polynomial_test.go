@@ -0,0 +1,44 @@
+package tss
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewGF256Field(t *testing.T) {
+	if f, _ := NewGF256Field(PolynomialAES); f != GF256 {
+		failNow(t, ErrInvalidPolynomial)
+	}
+	for _, poly := range []uint16{0x11a, 0xff, 0x211, 0x101} {
+		if _, err := NewGF256Field(poly); err != ErrInvalidPolynomial {
+			failNow(t, expected(ErrInvalidPolynomial, err))
+		}
+	}
+	f, err := NewGF256Field(PolynomialRS)
+	if err != nil {
+		failNow(t, err)
+	}
+	// 0x80·2 wraps around to the low bits of the polynomial
+	dst := make([]byte, 1)
+	f.Mul(dst, []byte{0x80}, []byte{0x02})
+	if dst[0] != 0x1d {
+		failNow(t, ErrInvalidPolynomial)
+	}
+	secret := randomBytes(40)
+	shares, err := CreateFieldShares(f, secret, 5, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	recovered, err := RecoverFieldSecret(f, [][]byte{shares[3], shares[0], shares[4]})
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, ErrIntegrity)
+	}
+	// with indexes 1, 2 and 3 the Lagrange weights at 0 are all 1 whatever the
+	// polynomial, other indexes tell the fields apart
+	if wrong, _ := RecoverFieldSecret(GF256, [][]byte{shares[1], shares[2], shares[4]}); bytes.Equal(wrong, secret) {
+		failNow(t, ErrInvalidPolynomial)
+	}
+}
@@ -0,0 +1,369 @@
+package tss
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+)
+
+var (
+	ErrInvalidPrime = validationError("invalid prime")
+)
+
+// PrimeShare is a share of the original Shamir scheme over the integers
+// modulo a prime, the point (X, Y) of the polynomial
+type PrimeShare struct {
+	X *big.Int
+	Y *big.Int
+}
+
+// MersennePrime127 returns 2^127-1, a prime commonly used to split secrets
+// of up to 126 bits
+func MersennePrime127() *big.Int {
+	return mersenne(127)
+}
+
+// MersennePrime521 returns 2^521-1, a prime commonly used to split secrets
+// of up to 520 bits, such as keys of 256 or 512 bits
+func MersennePrime521() *big.Int {
+	return mersenne(521)
+}
+
+func mersenne(n uint) *big.Int {
+	p := new(big.Int).Lsh(big.NewInt(1), n)
+	return p.Sub(p, big.NewInt(1))
+}
+
+// checkPrime checks prime is a prime large enough for sharesCount indexes
+func checkPrime(prime *big.Int, sharesCount int) error {
+	if prime == nil || prime.Cmp(big.NewInt(int64(sharesCount))) <= 0 || !prime.ProbablyPrime(20) {
+		return ErrInvalidPrime
+	}
+	return nil
+}
+
+// primeSharesLimit is the largest number of prime shares, WithMaxShares
+// lowering it
+func primeSharesLimit(cfg *config) int {
+	if cfg.maxShares > 0 {
+		return cfg.maxShares
+	}
+	return MaxWideShares
+}
+
+// CreatePrimeShares splits secret, an integer from 0 to prime-1, with the
+// original Shamir scheme: the shares are the points of a random polynomial
+// modulo prime of degree threshold-1 whose constant term is secret. Shares
+// get the X values 1 to sharesCount, or random ones with WithRandomIndexes.
+// The integers cannot be reliably wiped, prefer CreateShares for keys kept
+// in memory.
+func CreatePrimeShares(secret *big.Int, prime *big.Int, sharesCount int, threshold int, opts ...Option) ([]PrimeShare, error) {
+	cfg := newConfig(opts)
+	if err := checkPrime(prime, sharesCount); err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, ErrSecretRequired
+	}
+	if secret.Sign() < 0 || secret.Cmp(prime) >= 0 {
+		return nil, ErrSecretTooLarge
+	}
+	if sharesCount < MinShares {
+		return nil, ErrTooFewShares
+	}
+	if sharesCount > primeSharesLimit(cfg) {
+		return nil, ErrTooManyShares
+	}
+	if threshold > sharesCount || threshold < cfg.minThreshold() {
+		return nil, ErrInvalidThreshold
+	}
+	random, err := cfg.source(secret.Bytes(), threshold)
+	if err != nil {
+		return nil, err
+	}
+	xs := make([]*big.Int, sharesCount)
+	seen := make(map[string]bool, sharesCount)
+	for j := range xs {
+		xs[j] = big.NewInt(int64(j + 1))
+		for cfg.randomIndexes {
+			if xs[j], err = rand.Int(random, prime); err != nil {
+				return nil, err
+			}
+			if xs[j].Sign() != 0 && !seen[string(xs[j].Bytes())] {
+				seen[string(xs[j].Bytes())] = true
+				break
+			}
+		}
+	}
+	ys, err := primePoints(secret, prime, xs, threshold, random)
+	if err != nil {
+		return nil, err
+	}
+	shares := make([]PrimeShare, sharesCount)
+	for j := range shares {
+		shares[j] = PrimeShare{X: xs[j], Y: ys[j]}
+	}
+	return shares, nil
+}
+
+// primePoints returns the values at xs of a random polynomial modulo prime of
+// degree threshold-1 whose constant term is secret
+func primePoints(secret *big.Int, prime *big.Int, xs []*big.Int, threshold int, random io.Reader) ([]*big.Int, error) {
+	a, err := primePolynomial(secret, prime, threshold, random)
+	if err != nil {
+		return nil, err
+	}
+	defer eraseInts(a)
+	ys := make([]*big.Int, len(xs))
+	for j, x := range xs {
+		ys[j] = primeEval(x, a, prime)
+	}
+	return ys, nil
+}
+
+// primePolynomial returns the coefficients, lowest degree first, of a random
+// polynomial modulo prime of degree threshold-1 whose constant term is secret
+func primePolynomial(secret *big.Int, prime *big.Int, threshold int, random io.Reader) ([]*big.Int, error) {
+	a := make([]*big.Int, threshold)
+	a[0] = new(big.Int).Set(secret)
+	for k := 1; k < threshold; k++ {
+		var err error
+		if a[k], err = rand.Int(random, prime); err != nil {
+			eraseInts(a)
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// primeEval returns the polynomial of coefficients a, lowest degree first,
+// evaluated at x modulo prime
+func primeEval(x *big.Int, a []*big.Int, prime *big.Int) *big.Int {
+	y := new(big.Int)
+	for k := len(a) - 1; k >= 0; k-- {
+		y.Mul(y, x)
+		y.Add(y, a[k])
+		y.Mod(y, prime)
+	}
+	return y
+}
+
+// primeWeights returns the Lagrange weights at x of the distinct xs modulo
+// prime, the product of (x - x_j) / (x_i - x_j)
+func primeWeights(x *big.Int, xs []*big.Int, prime *big.Int) ([]*big.Int, error) {
+	weights := make([]*big.Int, len(xs))
+	num, den, t := new(big.Int), new(big.Int), new(big.Int)
+	for i := range xs {
+		num.SetInt64(1)
+		den.SetInt64(1)
+		for j := range xs {
+			if j == i {
+				continue
+			}
+			num.Mul(num, t.Sub(x, xs[j]))
+			num.Mod(num, prime)
+			den.Mul(den, t.Sub(xs[i], xs[j]))
+			den.Mod(den, prime)
+		}
+		if den.Sign() == 0 {
+			return nil, ErrDivisionByZero
+		}
+		weights[i] = new(big.Int).ModInverse(den, prime)
+		weights[i].Mul(weights[i], num)
+		weights[i].Mod(weights[i], prime)
+	}
+	return weights, nil
+}
+
+// RecoverPrimeSecret recovers a secret split by CreatePrimeShares, or by any
+// implementation of the original scheme, with the same prime
+func RecoverPrimeSecret(shares []PrimeShare, prime *big.Int, opts ...Option) (*big.Int, error) {
+	cfg := newConfig(opts)
+	n := len(shares)
+	if n < cfg.threshold {
+		return nil, &ThresholdError{Need: cfg.threshold, Have: n}
+	}
+	if n < cfg.minThreshold() {
+		return nil, ErrTooFewShares
+	}
+	if n > primeSharesLimit(cfg) {
+		return nil, ErrTooManyShares
+	}
+	if err := checkPrime(prime, 1); err != nil {
+		return nil, err
+	}
+	xs := make([]*big.Int, n)
+	seen := make(map[string]bool, n)
+	for i, s := range shares {
+		if s.X == nil || s.Y == nil || s.X.Sign() < 0 || s.X.Cmp(prime) >= 0 || s.Y.Sign() < 0 || s.Y.Cmp(prime) >= 0 {
+			return nil, &ShareError{Position: i + 1, Reason: ErrInvalidShare}
+		}
+		if s.X.Sign() == 0 {
+			return nil, &ShareError{Position: i + 1, Reason: ErrInvalidShareIndex}
+		}
+		if seen[string(s.X.Bytes())] {
+			return nil, &ShareError{Position: i + 1, Reason: ErrDuplicateShare}
+		}
+		seen[string(s.X.Bytes())] = true
+		xs[i] = s.X
+	}
+	weights, err := primeWeights(new(big.Int), xs, prime)
+	if err != nil {
+		return nil, err
+	}
+	secret := new(big.Int)
+	for i, s := range shares {
+		secret.Add(secret, weights[i].Mul(weights[i], s.Y))
+	}
+	return secret.Mod(secret, prime), nil
+}
+
+// eraseInt overwrites the words of x
+func eraseInt(x *big.Int) {
+	if x == nil {
+		return
+	}
+	words := x.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	x.SetInt64(0)
+}
+
+func eraseInts(a []*big.Int) {
+	for _, x := range a {
+		eraseInt(x)
+	}
+}
+
+// primeField is the Field of the integers modulo a prime, the elements being
+// big endian integers as long as the prime
+type primeField struct {
+	prime *big.Int
+	size  int
+}
+
+// NewPrimeField returns the integers modulo prime as a Field, for
+// CreateFieldShares. Each element carries (bitlen-1)/8 bytes of the secret,
+// so any secret splits; over a prime below 256 the elements are single
+// bytes, which must be below prime, CreateFieldShares failing with
+// ErrSecretTooLarge otherwise. CreatePrimeShares is simpler for a secret of a
+// single integer.
+func NewPrimeField(prime *big.Int) (Field, error) {
+	if err := checkPrime(prime, 2); err != nil {
+		return nil, err
+	}
+	return &primeField{prime: new(big.Int).Set(prime), size: (prime.BitLen() + 7) / 8}, nil
+}
+
+func (f *primeField) ElementBytes() int {
+	return f.size
+}
+
+// PayloadBytes is the number of secret bytes an element carries, the
+// elements below 2^(bitlen-1) being all below the prime
+func (f *primeField) PayloadBytes() int {
+	if f.size == 1 {
+		return 1
+	}
+	return (f.prime.BitLen() - 1) / 8
+}
+
+// element returns the i-th element of v
+func (f *primeField) element(v []byte, i int) *big.Int {
+	return new(big.Int).SetBytes(v[i*f.size : (i+1)*f.size])
+}
+
+// put stores x as the i-th element of v
+func (f *primeField) put(v []byte, i int, x *big.Int) {
+	x.FillBytes(v[i*f.size : (i+1)*f.size])
+}
+
+// Valid tells whether every element of v is below the prime
+func (f *primeField) Valid(v []byte) bool {
+	for i := 0; i < len(v)/f.size; i++ {
+		if f.element(v, i).Cmp(f.prime) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *primeField) Add(dst []byte, a []byte, b []byte) {
+	for i := 0; i < len(a)/f.size; i++ {
+		x := f.element(a, i)
+		x.Add(x, f.element(b, i))
+		f.put(dst, i, x.Mod(x, f.prime))
+	}
+}
+
+func (f *primeField) Mul(dst []byte, a []byte, b []byte) {
+	for i := 0; i < len(a)/f.size; i++ {
+		x := f.element(a, i)
+		x.Mul(x, f.element(b, i))
+		f.put(dst, i, x.Mod(x, f.prime))
+	}
+}
+
+func (f *primeField) Div(dst []byte, a []byte, b []byte) error {
+	inverses := make([]*big.Int, len(b)/f.size)
+	for i := range inverses {
+		y := f.element(b, i)
+		if y.Mod(y, f.prime).Sign() == 0 {
+			return ErrDivisionByZero
+		}
+		inverses[i] = y.ModInverse(y, f.prime)
+	}
+	for i := 0; i < len(a)/f.size; i++ {
+		x := f.element(a, i)
+		x.Mul(x, inverses[i])
+		f.put(dst, i, x.Mod(x, f.prime))
+	}
+	return nil
+}
+
+func (f *primeField) Eval(dst []byte, x []byte, coefficients [][]byte) {
+	at := f.element(x, 0)
+	a := make([]*big.Int, len(coefficients))
+	for i := 0; i < len(dst)/f.size; i++ {
+		for k, c := range coefficients {
+			a[k] = f.element(c, i)
+		}
+		y := primeEval(at, a, f.prime)
+		f.put(dst, i, y)
+		eraseInt(y)
+	}
+}
+
+func (f *primeField) Interp(dst []byte, x []byte, xs []byte, ys [][]byte) error {
+	u := make([]*big.Int, len(ys))
+	for i := range u {
+		u[i] = f.element(xs, i)
+	}
+	weights, err := primeWeights(f.element(x, 0), u, f.prime)
+	if err != nil {
+		return err
+	}
+	y, t := new(big.Int), new(big.Int)
+	defer eraseInt(y)
+	for i := 0; i < len(dst)/f.size; i++ {
+		y.SetInt64(0)
+		for k, v := range ys {
+			y.Add(y, t.Mul(weights[k], f.element(v, i)))
+		}
+		f.put(dst, i, y.Mod(y, f.prime))
+	}
+	return nil
+}
+
+func (f *primeField) Random(dst []byte, random io.Reader) error {
+	for i := 0; i < len(dst)/f.size; i++ {
+		x, err := rand.Int(random, f.prime)
+		if err != nil {
+			return err
+		}
+		f.put(dst, i, x)
+		eraseInt(x)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestPrimeShares(t *testing.T) {
+	prime := MersennePrime521()
+	secret := new(big.Int).SetBytes(randomBytes(64))
+	shares, err := CreatePrimeShares(secret, prime, 5, 3, WithRandomIndexes())
+	if err != nil {
+		failNow(t, err)
+	}
+	recovered, err := RecoverPrimeSecret([]PrimeShare{shares[4], shares[0], shares[2]}, prime)
+	if err != nil {
+		failNow(t, err)
+	}
+	if recovered.Cmp(secret) != 0 {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+	recovered, _ = RecoverPrimeSecret(shares[:2], prime)
+	if recovered.Cmp(secret) == 0 {
+		failNow(t, fmt.Errorf("recovered below threshold"))
+	}
+}
+
+// TestPrimeSharesVector recovers the shares of the example of the Wikipedia
+// article on Shamir's secret sharing
+func TestPrimeSharesVector(t *testing.T) {
+	prime := big.NewInt(1613)
+	shares := []PrimeShare{
+		{big.NewInt(2), big.NewInt(329)},
+		{big.NewInt(4), big.NewInt(176)},
+		{big.NewInt(6), big.NewInt(775)},
+	}
+	recovered, err := RecoverPrimeSecret(shares, prime)
+	if err != nil {
+		failNow(t, err)
+	}
+	if recovered.Int64() != 1234 {
+		failNow(t, fmt.Errorf("recovered %v, want 1234", recovered))
+	}
+}
+
+func TestPrimeSharesErrors(t *testing.T) {
+	prime := MersennePrime127()
+	if _, err := CreatePrimeShares(big.NewInt(1), big.NewInt(1000), 5, 3); err != ErrInvalidPrime {
+		failNow(t, expected(ErrInvalidPrime, err))
+	}
+	if _, err := CreatePrimeShares(prime, prime, 5, 3); err != ErrSecretTooLarge {
+		failNow(t, expected(ErrSecretTooLarge, err))
+	}
+	shares, _ := CreatePrimeShares(big.NewInt(42), prime, 5, 3)
+	if _, err := RecoverPrimeSecret([]PrimeShare{shares[0], shares[1], shares[0]}, prime); !errors.Is(err, ErrDuplicateShare) {
+		failNow(t, expected(ErrDuplicateShare, err))
+	}
+	if _, err := RecoverPrimeSecret([]PrimeShare{shares[0], {X: big.NewInt(0), Y: big.NewInt(1)}}, prime); !errors.Is(err, ErrInvalidShareIndex) {
+		failNow(t, expected(ErrInvalidShareIndex, err))
+	}
+}
+
+func TestPrimeField(t *testing.T) {
+	field, err := NewPrimeField(big.NewInt(65521))
+	if err != nil {
+		failNow(t, err)
+	}
+	secret := []byte("the secret is..")
+	shares, err := CreateFieldShares(field, secret, 5, 3, WithRandomIndexes())
+	if err != nil {
+		failNow(t, err)
+	}
+	recovered, err := RecoverFieldSecret(field, shares[1:4])
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+	partial, ok := field.(PartialField)
+	if !ok {
+		failNow(t, fmt.Errorf("prime field is not a PartialField"))
+	}
+	if partial.PayloadBytes() != 1 || !partial.Valid([]byte{0xff, 0xf0}) || partial.Valid([]byte{0xff, 0xf1}) {
+		failNow(t, fmt.Errorf("prime field bounds mismatch"))
+	}
+	small, err := NewPrimeField(big.NewInt(251))
+	if err != nil {
+		failNow(t, err)
+	}
+	if _, err := CreateFieldShares(small, []byte{0xff, 0xf2}, 5, 3); err != ErrSecretTooLarge {
+		failNow(t, expected(ErrSecretTooLarge, err))
+	}
+}
+
+func TestPrimeFieldKeys(t *testing.T) {
+	for _, prime := range []*big.Int{MersennePrime127(), MersennePrime521(), big.NewInt(65521)} {
+		field, err := NewPrimeField(prime)
+		if err != nil {
+			failNow(t, err)
+		}
+		for _, size := range []int{16, 32} {
+			for _, key := range [][]byte{bytes.Repeat([]byte{0xff}, size), randomBytes(size)} {
+				shares, err := CreateFieldShares(field, key, 5, 3)
+				if err != nil {
+					failNow(t, err)
+				}
+				recovered, err := RecoverFieldSecret(field, shares[2:])
+				if err != nil {
+					failNow(t, err)
+				}
+				if !bytes.Equal(recovered, key) {
+					failNow(t, fmt.Errorf("secret mismatch over %d bits prime", prime.BitLen()))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package tss
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"math/big"
+)
+
+var (
+	ErrInvalidPoint   = validationError("invalid curve point")
+	ErrInvalidProof   = integrityError("invalid proof")
+	ErrUnknownKey     = validationError("public key not in the transcript")
+	ErrInvalidDealing = integrityError("invalid dealing")
+)
+
+// pvssDomain separates the challenges of the proofs from other hashes
+const pvssDomain = "go-tss pvss dleq"
+
+// point is a P-256 point, the point at infinity having nil coordinates
+type point struct {
+	x, y *big.Int
+}
+
+func decodePoint(b []byte) (point, error) {
+	x, y := elliptic.UnmarshalCompressed(elliptic.P256(), b)
+	if x == nil {
+		return point{}, ErrInvalidPoint
+	}
+	return point{x, y}, nil
+}
+
+func (p point) bytes() []byte {
+	return elliptic.MarshalCompressed(elliptic.P256(), p.x, p.y)
+}
+
+func (p point) mul(k *big.Int) point {
+	x, y := elliptic.P256().ScalarMult(p.x, p.y, k.FillBytes(make([]byte, ScalarBytes)))
+	return point{x, y}
+}
+
+func (p point) add(q point) point {
+	x, y := elliptic.P256().Add(p.x, p.y, q.x, q.y)
+	return point{x, y}
+}
+
+func (p point) equal(q point) bool {
+	return p.x.Cmp(q.x) == 0 && p.y.Cmp(q.y) == 0
+}
+
+// basePoint returns the generator G of P-256
+func basePoint() point {
+	params := elliptic.P256().Params()
+	return point{params.Gx, params.Gy}
+}
+
+// pedersenPoint returns the generator H of pedersenH
+func pedersenPoint() point {
+	x, y := pedersenH()
+	return point{x, y}
+}
+
+// dleqChallenge hashes the points of a proof into a scalar
+func dleqChallenge(points ...point) *big.Int {
+	h := sha256.New()
+	h.Write([]byte(pvssDomain))
+	for _, p := range points {
+		h.Write(p.bytes())
+	}
+	c := new(big.Int).SetBytes(h.Sum(nil))
+	return c.Mod(c, elliptic.P256().Params().N)
+}
+
+// dleqProve proves without revealing alpha that h1 = alpha*g1 and
+// h2 = alpha*g2, with the Chaum-Pedersen protocol made non interactive
+func dleqProve(g1, h1, g2, h2 point, alpha *big.Int, random io.Reader) ([]byte, error) {
+	order := elliptic.P256().Params().N
+	w, err := rand.Int(random, order)
+	if err != nil {
+		return nil, err
+	}
+	defer eraseInt(w)
+	c := dleqChallenge(g1, h1, g2, h2, g1.mul(w), g2.mul(w))
+	// r = w - alpha*c
+	r := new(big.Int).Mul(alpha, c)
+	r.Sub(w, r)
+	r.Mod(r, order)
+	return append(c.FillBytes(make([]byte, ScalarBytes)), r.FillBytes(make([]byte, ScalarBytes))...), nil
+}
+
+// dleqVerify checks a proof of dleqProve
+func dleqVerify(g1, h1, g2, h2 point, proof []byte) bool {
+	if len(proof) != 2*ScalarBytes {
+		return false
+	}
+	c := new(big.Int).SetBytes(proof[:ScalarBytes])
+	r := new(big.Int).SetBytes(proof[ScalarBytes:])
+	a1 := g1.mul(r).add(h1.mul(c))
+	a2 := g2.mul(r).add(h2.mul(c))
+	return dleqChallenge(g1, h1, g2, h2, a1, a2).Cmp(c) == 0
+}
+
+// PVSSKey is the key pair of a shareholder of a publicly verifiable split,
+// its public key being x*H for the private x
+type PVSSKey struct {
+	private *big.Int
+	Public  []byte
+}
+
+// GeneratePVSSKey draws a shareholder key pair, random being crypto/rand
+// when nil
+func GeneratePVSSKey(random io.Reader) (*PVSSKey, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	x, err := rand.Int(random, elliptic.P256().Params().N)
+	if err != nil {
+		return nil, err
+	}
+	if x.Sign() == 0 {
+		return nil, ErrInvalidScalar
+	}
+	return &PVSSKey{private: x, Public: pedersenPoint().mul(x).bytes()}, nil
+}
+
+// Destroy erases the private key
+func (k *PVSSKey) Destroy() {
+	eraseInt(k.private)
+}
+
+// PVSSShare is the share of a shareholder encrypted to its public key, with
+// the proof the encrypted value is the committed one
+type PVSSShare struct {
+	Index     byte
+	PublicKey []byte
+	Encrypted []byte
+	Proof     []byte
+}
+
+// PVSSTranscript is the public record of a dealing: the commitments to the
+// polynomial and the encrypted shares. Anyone can check with Verify that
+// every shareholder got a share of the same secret, without learning it.
+type PVSSTranscript struct {
+	Commitments Commitments
+	Shares      []PVSSShare
+}
+
+// DealPVSS deals a random secret to the holders of publicKeys, threshold of
+// them being needed to recover it, with Schoenmakers' publicly verifiable
+// scheme. The shares are encrypted in the transcript, which may be
+// published; the holder of the i-th key gets index i+1. The secret returned
+// to the dealer is the SHA-256 of the point s*H, s being the constant term of
+// the polynomial, which is what the shareholders recover. WithRand applies.
+func DealPVSS(publicKeys [][]byte, threshold int, opts ...Option) (*PVSSTranscript, []byte, error) {
+	cfg := newConfig(opts)
+	if len(publicKeys) < MinShares {
+		return nil, nil, ErrTooFewShares
+	}
+	if len(publicKeys) > cfg.sharesLimit() {
+		return nil, nil, ErrTooManyShares
+	}
+	if threshold > len(publicKeys) || threshold < cfg.minThreshold() {
+		return nil, nil, ErrInvalidThreshold
+	}
+	keys := make([]point, len(publicKeys))
+	for i, pk := range publicKeys {
+		var err error
+		if keys[i], err = decodePoint(pk); err != nil {
+			return nil, nil, &ShareError{Position: i + 1, Index: byte(i + 1), Reason: err}
+		}
+	}
+	random := cfg.random()
+	order := elliptic.P256().Params().N
+	s, err := rand.Int(random, order)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer eraseInt(s)
+	a, err := primePolynomial(s, order, threshold, random)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer eraseInts(a)
+	t := &PVSSTranscript{
+		Commitments: make(Commitments, threshold),
+		Shares:      make([]PVSSShare, len(keys)),
+	}
+	g := basePoint()
+	for k, c := range a {
+		t.Commitments[k] = g.mul(c).bytes()
+	}
+	for i, key := range keys {
+		y := primeEval(big.NewInt(int64(i+1)), a, order)
+		encrypted := key.mul(y)
+		proof, err := dleqProve(g, g.mul(y), key, encrypted, y, random)
+		eraseInt(y)
+		if err != nil {
+			return nil, nil, err
+		}
+		t.Shares[i] = PVSSShare{Index: byte(i + 1), PublicKey: publicKeys[i], Encrypted: encrypted.bytes(), Proof: proof}
+	}
+	secret := sha256.Sum256(pedersenPoint().mul(s).bytes())
+	return t, secret[:], nil
+}
+
+// Verify checks every encrypted share holds the value of the committed
+// polynomial at its index
+func (t *PVSSTranscript) Verify() error {
+	if len(t.Shares) < len(t.Commitments) {
+		return ErrInvalidDealing
+	}
+	g := basePoint()
+	for i, s := range t.Shares {
+		if s.Index != byte(i+1) {
+			return &ShareError{Position: i + 1, Index: s.Index, Reason: ErrInvalidShareIndex}
+		}
+		key, err := decodePoint(s.PublicKey)
+		if err != nil {
+			return &ShareError{Position: i + 1, Index: s.Index, Reason: err}
+		}
+		encrypted, err := decodePoint(s.Encrypted)
+		if err != nil {
+			return &ShareError{Position: i + 1, Index: s.Index, Reason: err}
+		}
+		x, y, err := t.Commitments.evalAt(s.Index)
+		if err != nil {
+			return err
+		}
+		if !dleqVerify(g, point{x, y}, key, encrypted, s.Proof) {
+			return &ShareError{Position: i + 1, Index: s.Index, Reason: ErrInvalidDealing}
+		}
+	}
+	return nil
+}
+
+// PVSSDecryptedShare is a share decrypted by its holder, the point y*H for
+// the value y of the polynomial at Index, with the proof the decryption is
+// right
+type PVSSDecryptedShare struct {
+	Index byte
+	Share []byte
+	Proof []byte
+}
+
+// Decrypt verifies the transcript and decrypts the share of k. The decrypted
+// share may be published: it is useless without threshold-1 others.
+func (k *PVSSKey) Decrypt(t *PVSSTranscript) (*PVSSDecryptedShare, error) {
+	if err := t.Verify(); err != nil {
+		return nil, err
+	}
+	share, err := t.share(k.Public)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, _ := decodePoint(share.Encrypted)
+	order := elliptic.P256().Params().N
+	inverse := new(big.Int).ModInverse(k.private, order)
+	defer eraseInt(inverse)
+	decrypted := encrypted.mul(inverse)
+	key, _ := decodePoint(k.Public)
+	proof, err := dleqProve(pedersenPoint(), key, decrypted, encrypted, k.private, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &PVSSDecryptedShare{Index: share.Index, Share: decrypted.bytes(), Proof: proof}, nil
+}
+
+// share returns the encrypted share of the holder of publicKey
+func (t *PVSSTranscript) share(publicKey []byte) (*PVSSShare, error) {
+	for i := range t.Shares {
+		if string(t.Shares[i].PublicKey) == string(publicKey) {
+			return &t.Shares[i], nil
+		}
+	}
+	return nil, ErrUnknownKey
+}
+
+// VerifyDecryption checks d is the decryption of the share at its index by
+// the holder of the key
+func (t *PVSSTranscript) VerifyDecryption(d *PVSSDecryptedShare) error {
+	if d.Index == 0 || int(d.Index) > len(t.Shares) {
+		return ErrInvalidShareIndex
+	}
+	share := t.Shares[d.Index-1]
+	key, err := decodePoint(share.PublicKey)
+	if err != nil {
+		return err
+	}
+	encrypted, err := decodePoint(share.Encrypted)
+	if err != nil {
+		return err
+	}
+	decrypted, err := decodePoint(d.Share)
+	if err != nil {
+		return err
+	}
+	if !dleqVerify(pedersenPoint(), key, decrypted, encrypted, d.Proof) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// Recover verifies the transcript and the decrypted shares, and returns the
+// secret of the dealing from threshold of them, as DealPVSS returned it
+func (t *PVSSTranscript) Recover(shares []*PVSSDecryptedShare) ([]byte, error) {
+	if err := t.Verify(); err != nil {
+		return nil, err
+	}
+	if len(shares) < len(t.Commitments) {
+		return nil, &ThresholdError{Need: len(t.Commitments), Have: len(shares)}
+	}
+	var seen [256]bool
+	xs := make([]*big.Int, len(shares))
+	points := make([]point, len(shares))
+	for i, d := range shares {
+		if err := t.VerifyDecryption(d); err != nil {
+			return nil, &ShareError{Position: i + 1, Index: d.Index, Reason: err}
+		}
+		if seen[d.Index] {
+			return nil, &ShareError{Position: i + 1, Index: d.Index, Reason: ErrDuplicateShare}
+		}
+		seen[d.Index] = true
+		xs[i] = big.NewInt(int64(d.Index))
+		points[i], _ = decodePoint(d.Share)
+	}
+	weights, err := primeWeights(new(big.Int), xs, elliptic.P256().Params().N)
+	if err != nil {
+		return nil, err
+	}
+	s := points[0].mul(weights[0])
+	for i := 1; i < len(points); i++ {
+		s = s.add(points[i].mul(weights[i]))
+	}
+	secret := sha256.Sum256(s.bytes())
+	return secret[:], nil
+}
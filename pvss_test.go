@@ -0,0 +1,79 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func pvssKeys(t *testing.T, n int) ([]*PVSSKey, [][]byte) {
+	keys := make([]*PVSSKey, n)
+	public := make([][]byte, n)
+	for i := range keys {
+		var err error
+		if keys[i], err = GeneratePVSSKey(nil); err != nil {
+			failNow(t, err)
+		}
+		public[i] = keys[i].Public
+	}
+	return keys, public
+}
+
+func TestPVSS(t *testing.T) {
+	keys, public := pvssKeys(t, 5)
+	transcript, secret, err := DealPVSS(public, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	if err := transcript.Verify(); err != nil {
+		failNow(t, err)
+	}
+	var decrypted []*PVSSDecryptedShare
+	for _, k := range []*PVSSKey{keys[4], keys[0], keys[2]} {
+		d, err := k.Decrypt(transcript)
+		if err != nil {
+			failNow(t, err)
+		}
+		if err := transcript.VerifyDecryption(d); err != nil {
+			failNow(t, err)
+		}
+		decrypted = append(decrypted, d)
+	}
+	recovered, err := transcript.Recover(decrypted)
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+	if _, err := transcript.Recover(decrypted[:2]); !errors.Is(err, ErrThresholdNotMet) {
+		failNow(t, expected(ErrThresholdNotMet, err))
+	}
+}
+
+func TestPVSSCheating(t *testing.T) {
+	keys, public := pvssKeys(t, 4)
+	transcript, _, _ := DealPVSS(public, 2)
+	// a dealer giving the second holder the encryption meant for the third
+	forged := *transcript
+	forged.Shares = append([]PVSSShare{}, transcript.Shares...)
+	forged.Shares[1].Encrypted = transcript.Shares[2].Encrypted
+	if err := forged.Verify(); !errors.Is(err, ErrInvalidDealing) {
+		failNow(t, expected(ErrInvalidDealing, err))
+	}
+	if _, err := keys[1].Decrypt(&forged); !errors.Is(err, ErrInvalidDealing) {
+		failNow(t, expected(ErrInvalidDealing, err))
+	}
+	// a holder publishing a wrong decryption
+	d0, _ := keys[0].Decrypt(transcript)
+	d1, _ := keys[1].Decrypt(transcript)
+	d1.Share = d0.Share
+	if err := transcript.VerifyDecryption(d1); err != ErrInvalidProof {
+		failNow(t, expected(ErrInvalidProof, err))
+	}
+	other, _ := GeneratePVSSKey(nil)
+	if _, err := other.Decrypt(transcript); err != ErrUnknownKey {
+		failNow(t, expected(ErrUnknownKey, err))
+	}
+}
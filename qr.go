@@ -0,0 +1,164 @@
+package tss
+
+import (
+	"sort"
+)
+
+// QRLevel is the error correction level of the QR code the chunks are printed on
+type QRLevel int
+
+const (
+	// QRLevelL recovers about 7% of damaged codewords
+	QRLevelL QRLevel = iota
+	// QRLevelM recovers about 15% of damaged codewords
+	QRLevelM
+	// QRLevelQ recovers about 25% of damaged codewords
+	QRLevelQ
+	// QRLevelH recovers about 30% of damaged codewords
+	QRLevelH
+)
+
+const (
+	// MinQRVersion is the smallest QR code version
+	MinQRVersion = 1
+	// MaxQRVersion is the largest QR code version
+	MaxQRVersion = 40
+	// QRChunkHeaderBytes is the size of the header prepended to every chunk:
+	// share index, sequence number and chunk count
+	QRChunkHeaderBytes = 5
+)
+
+var (
+	ErrInvalidQRVersion = validationError("invalid qr version")
+	ErrInvalidQRLevel   = validationError("invalid qr level")
+	ErrQRTooSmall       = validationError("qr version too small for chunk header")
+	ErrTooManyChunks    = limitsError("too many chunks")
+	ErrInvalidChunk     = validationError("invalid chunk")
+	ErrMissingChunk     = validationError("missing chunk")
+)
+
+// qrECCPerBlock is the number of error correction codewords per block, indexed by level and version
+var qrECCPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+// qrECCBlocks is the number of error correction blocks, indexed by level and version
+var qrECCBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// qrRawModules returns the number of modules available for data and error
+// correction codewords once the function patterns of the version are placed
+func qrRawModules(version int) int {
+	r := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		r -= (25*align-10)*align - 55
+		if version >= 7 {
+			r -= 36
+		}
+	}
+	return r
+}
+
+// QRCapacity returns how many bytes a QR code of the given version and level
+// holds in byte mode
+func QRCapacity(version int, level QRLevel) (int, error) {
+	if version < MinQRVersion || version > MaxQRVersion {
+		return 0, ErrInvalidQRVersion
+	}
+	if level < QRLevelL || level > QRLevelH {
+		return 0, ErrInvalidQRLevel
+	}
+	data := qrRawModules(version)/8 - qrECCPerBlock[level][version]*qrECCBlocks[level][version]
+	// 4 bits of mode indicator plus the character count indicator
+	overhead := 4 + 8
+	if version >= 10 {
+		overhead = 4 + 16
+	}
+	return (data*8 - overhead) / 8, nil
+}
+
+// QRChunks splits a share into payloads that each fit a QR code of the given
+// version and level. Every chunk carries the share index, its sequence number
+// and the total number of chunks so they can be scanned in any order.
+func QRChunks(share Share, version int, level QRLevel) ([][]byte, error) {
+	if len(share) < MinShareBytes || len(share) > MaxShareBytes {
+		return nil, ErrInvalidShare
+	}
+	capacity, err := QRCapacity(version, level)
+	if err != nil {
+		return nil, err
+	}
+	size := capacity - QRChunkHeaderBytes
+	if size <= 0 {
+		return nil, ErrQRTooSmall
+	}
+	count := (len(share) + size - 1) / size
+	if count > 0xffff {
+		return nil, ErrTooManyChunks
+	}
+	chunks := make([][]byte, count)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * size
+		if end > len(share) {
+			end = len(share)
+		}
+		payload := share[i*size : end]
+		chunk := make([]byte, QRChunkHeaderBytes+len(payload))
+		chunk[0] = share[0]
+		chunk[1], chunk[2] = byte(i>>8), byte(i)
+		chunk[3], chunk[4] = byte(count>>8), byte(count)
+		copy(chunk[QRChunkHeaderBytes:], payload)
+		chunks[i] = chunk
+	}
+	return chunks, nil
+}
+
+// JoinQRChunks reassembles a share from the chunks returned by QRChunks.
+// Chunks may be given in any order and repeated; all of them must belong to
+// the same share.
+func JoinQRChunks(chunks [][]byte) (Share, error) {
+	if len(chunks) == 0 {
+		return nil, ErrMissingChunk
+	}
+	seen := make(map[int][]byte)
+	var index byte
+	count := -1
+	for _, chunk := range chunks {
+		if len(chunk) <= QRChunkHeaderBytes {
+			return nil, ErrInvalidChunk
+		}
+		seq := int(chunk[1])<<8 | int(chunk[2])
+		total := int(chunk[3])<<8 | int(chunk[4])
+		if count == -1 {
+			index, count = chunk[0], total
+		}
+		if chunk[0] != index || total != count || seq >= count {
+			return nil, ErrInvalidChunk
+		}
+		seen[seq] = chunk[QRChunkHeaderBytes:]
+	}
+	if len(seen) != count {
+		return nil, ErrMissingChunk
+	}
+	seqs := make([]int, 0, count)
+	for seq := range seen {
+		seqs = append(seqs, seq)
+	}
+	sort.Ints(seqs)
+	var share Share
+	for _, seq := range seqs {
+		share = append(share, seen[seq]...)
+	}
+	if len(share) < MinShareBytes || len(share) > MaxShareBytes || share[0] != index {
+		return nil, ErrInvalidShare
+	}
+	return share, nil
+}
@@ -0,0 +1,74 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestQRCapacity(t *testing.T) {
+	// byte mode capacities from ISO/IEC 18004 table 7
+	cases := []struct {
+		version  int
+		level    QRLevel
+		capacity int
+	}{
+		{1, QRLevelL, 17},
+		{1, QRLevelH, 7},
+		{10, QRLevelM, 213},
+		{25, QRLevelQ, 715},
+		{40, QRLevelL, 2953},
+		{40, QRLevelH, 1273},
+	}
+	for _, c := range cases {
+		capacity, err := QRCapacity(c.version, c.level)
+		if err != nil {
+			failNow(t, err)
+		}
+		if capacity != c.capacity {
+			failNow(t, fmt.Errorf("capacity %d-%d is %d, want %d", c.version, c.level, capacity, c.capacity))
+		}
+	}
+	if _, err := QRCapacity(MaxQRVersion+1, QRLevelL); err != ErrInvalidQRVersion {
+		failNow(t, expected(ErrInvalidQRVersion, err))
+	}
+	if _, err := QRCapacity(1, QRLevelH+1); err != ErrInvalidQRLevel {
+		failNow(t, expected(ErrInvalidQRLevel, err))
+	}
+}
+
+func TestQRChunks(t *testing.T) {
+	shares, err := CreateShares(randomBytes(1000), 3, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	chunks, err := QRChunks(shares[1], 5, QRLevelQ)
+	if err != nil {
+		failNow(t, err)
+	}
+	capacity, _ := QRCapacity(5, QRLevelQ)
+	for _, chunk := range chunks {
+		if len(chunk) > capacity {
+			failNow(t, fmt.Errorf("chunk of %d bytes exceeds capacity %d", len(chunk), capacity))
+		}
+	}
+	// scanned out of order, with a repeated frame
+	scanned := append([][]byte{chunks[len(chunks)-1], chunks[0]}, chunks...)
+	share, err := JoinQRChunks(scanned)
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(share, shares[1]) {
+		failNow(t, fmt.Errorf("share mismatch"))
+	}
+	if _, err := JoinQRChunks(chunks[1:]); err != ErrMissingChunk {
+		failNow(t, expected(ErrMissingChunk, err))
+	}
+	other, _ := QRChunks(shares[2], 5, QRLevelQ)
+	if _, err := JoinQRChunks(append(chunks[1:], other[0])); err != ErrInvalidChunk {
+		failNow(t, expected(ErrInvalidChunk, err))
+	}
+	if _, err := QRChunks(shares[0], 1, QRLevelH); err != nil {
+		failNow(t, err)
+	}
+}
@@ -0,0 +1,6 @@
+//go:build race
+
+package tss
+
+// raceEnabled is set when testing with the race detector, which allocates
+const raceEnabled = true
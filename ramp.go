@@ -0,0 +1,166 @@
+package tss
+
+import "io"
+
+var (
+	ErrInvalidPacking = validationError("invalid packing")
+)
+
+// CreateRampShares splits secret with a ramp scheme: every polynomial carries
+// packing secret bytes in its low coefficients instead of one, so shares are
+// about 1/packing of the secret size. The price is a gap between the
+// thresholds: threshold shares recover the secret, threshold-packing shares
+// reveal nothing, and sets in between leak part of it.
+// packing must be at least 1 and below threshold, 1 being the regular scheme.
+// The secret is padded, 0x80 then zeros, to a multiple of packing bytes.
+func CreateRampShares(secret []byte, sharesCount int, threshold int, packing int, opts ...Option) (ShareSet, error) {
+	if threshold > sharesCount || threshold < MinThreshold {
+		return nil, ErrInvalidThreshold
+	}
+	if packing < 1 || packing >= threshold {
+		return nil, ErrInvalidPacking
+	}
+	return createPacked(secret, sharesCount, threshold, packing, newConfig(opts))
+}
+
+// createPacked splits secret with polynomials of threshold coefficients, the
+// packing low ones carrying the secret and the others random
+func createPacked(secret []byte, sharesCount int, threshold int, packing int, cfg *config) (ShareSet, error) {
+	if len(secret) == 0 {
+		return nil, ErrSecretRequired
+	}
+	if len(secret) < cfg.minSecretBytes {
+		return nil, ErrSecretTooShort
+	}
+	if sharesCount < MinShares {
+		return nil, ErrTooFewShares
+	}
+	if sharesCount > cfg.sharesLimit() {
+		return nil, ErrTooManyShares
+	}
+	blocks := len(secret)/packing + 1
+	if blocks > cfg.secretLimit() {
+		return nil, ErrSecretTooLarge
+	}
+	padded := make([]byte, blocks*packing)
+	defer erase(padded)
+	copy(padded, secret)
+	padded[len(secret)] = 0x80
+
+	random, err := cfg.source(secret, threshold)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]byte, sharesCount)
+	if cfg.randomIndexes {
+		if err := randomIndexes(random, indexes); err != nil {
+			return nil, err
+		}
+	} else {
+		for i := range indexes {
+			indexes[i] = byte(i + 1)
+		}
+	}
+	shares := make(ShareSet, sharesCount)
+	for j := range shares {
+		shares[j] = make(Share, blocks+1)
+		shares[j][0] = indexes[j]
+	}
+	a := make([]byte, threshold)
+	defer erase(a)
+	for b := 0; b < blocks; b++ {
+		if _, err := io.ReadFull(random, a[packing:]); err != nil {
+			eraseShares(shares)
+			return nil, err
+		}
+		copy(a, padded[b*packing:(b+1)*packing])
+		for j := range shares {
+			shares[j][b+1] = eval(shares[j][0], a)
+		}
+	}
+	return shares, nil
+}
+
+// RecoverRampSecret recovers a secret split by CreateRampShares with the same
+// threshold and packing, from at least threshold shares
+func RecoverRampSecret(shares ShareSet, threshold int, packing int, opts ...Option) ([]byte, error) {
+	if threshold < MinThreshold || threshold > MaxShares {
+		return nil, ErrInvalidThreshold
+	}
+	if packing < 1 || packing >= threshold {
+		return nil, ErrInvalidPacking
+	}
+	return recoverPacked(shares, threshold, packing, newConfig(opts))
+}
+
+// recoverPacked recovers a secret split by createPacked
+func recoverPacked(shares ShareSet, threshold int, packing int, cfg *config) ([]byte, error) {
+	if len(shares) < threshold {
+		return nil, ErrTooFewShares
+	}
+	shares = shares[:threshold]
+	cfg.minSecretBytes = 1
+	blocks, err := checkShares(shares, cfg)
+	if err != nil {
+		return nil, err
+	}
+	inverse := vandermondeInverse(shares.Indexes())
+	padded := make([]byte, blocks*packing)
+	v := make([]byte, threshold)
+	defer erase(v)
+	for b := 0; b < blocks; b++ {
+		for i, s := range shares {
+			v[i] = s[b+1]
+		}
+		// only the low coefficients carry the secret
+		for k := 0; k < packing; k++ {
+			var c byte
+			for i := range v {
+				c = add(c, mul(inverse[k][i], v[i]))
+			}
+			padded[b*packing+k] = c
+		}
+	}
+	return unpad(padded)
+}
+
+// vandermondeInverse inverts the matrix of the powers of the distinct non
+// zero u, so the coefficients of a polynomial follow from its values at u
+func vandermondeInverse(u []byte) [][]byte {
+	n := len(u)
+	m := make([][]byte, n)
+	inv := make([][]byte, n)
+	for i := range m {
+		m[i] = make([]byte, n)
+		inv[i] = make([]byte, n)
+		for k := range m[i] {
+			m[i][k] = gfPow(u[i], k)
+		}
+		inv[i][i] = 1
+	}
+	// Gauss-Jordan elimination, the matrix is invertible as u are distinct
+	for col := 0; col < n; col++ {
+		pivot := col
+		for m[pivot][col] == 0 {
+			pivot++
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		inv[col], inv[pivot] = inv[pivot], inv[col]
+		scale := div(1, m[col][col])
+		for k := 0; k < n; k++ {
+			m[col][k] = mul(m[col][k], scale)
+			inv[col][k] = mul(inv[col][k], scale)
+		}
+		for row := 0; row < n; row++ {
+			if row == col || m[row][col] == 0 {
+				continue
+			}
+			f := m[row][col]
+			for k := 0; k < n; k++ {
+				m[row][k] = add(m[row][k], mul(f, m[col][k]))
+				inv[row][k] = add(inv[row][k], mul(f, inv[col][k]))
+			}
+		}
+	}
+	return inv
+}
@@ -0,0 +1,40 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestRampShares(t *testing.T) {
+	for _, c := range []struct{ size, n, t, packing int }{
+		{1, 2, 2, 1}, {32, 5, 3, 2}, {1000, 10, 8, 4}, {99, 3, 3, 2},
+	} {
+		secret := randomBytes(c.size)
+		shares, err := CreateRampShares(secret, c.n, c.t, c.packing, WithRandomIndexes())
+		if err != nil {
+			failNow(t, err)
+		}
+		if want := c.size/c.packing + 2; len(shares[0]) != want {
+			failNow(t, fmt.Errorf("share size %d, want %d", len(shares[0]), want))
+		}
+		got, err := RecoverRampSecret(shares[c.n-c.t:], c.t, c.packing)
+		if err != nil {
+			failNow(t, err)
+		}
+		if !bytes.Equal(got, secret) {
+			failNow(t, fmt.Errorf("secret mismatch for %+v", c))
+		}
+	}
+}
+
+func TestRampSharesErrors(t *testing.T) {
+	secret := randomBytes(32)
+	if _, err := CreateRampShares(secret, 5, 3, 3); err != ErrInvalidPacking {
+		failNow(t, expected(ErrInvalidPacking, err))
+	}
+	shares, _ := CreateRampShares(secret, 5, 3, 2)
+	if _, err := RecoverRampSecret(shares[:2], 3, 2); err != ErrTooFewShares {
+		failNow(t, expected(ErrTooFewShares, err))
+	}
+}
@@ -0,0 +1,67 @@
+package tss
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// fingerprint identifies b in logs without revealing it
+func fingerprint(b []byte) string {
+	digest := sha256.Sum256(b)
+	return hex.EncodeToString(digest[:4]) + "…"
+}
+
+// String returns a fingerprint of the share, never its value
+func (s Share) String() string {
+	return fmt.Sprintf("tss.Share{idx:%d, len:%d, sha256:%s}", s.Index(), len(s), fingerprint(s))
+}
+
+// GoString is String, so %#v is redacted too
+func (s Share) GoString() string {
+	return s.String()
+}
+
+// Format prints String whatever the verb, %x and %s included
+func (s Share) Format(f fmt.State, verb rune) {
+	io.WriteString(f, s.String())
+}
+
+// String returns the fingerprint of every share of the set
+func (ss ShareSet) String() string {
+	parts := make([]string, len(ss))
+	for i, s := range ss {
+		parts[i] = s.String()
+	}
+	return "tss.ShareSet[" + strings.Join(parts, ", ") + "]"
+}
+
+// GoString is String, so %#v is redacted too
+func (ss ShareSet) GoString() string {
+	return ss.String()
+}
+
+// Format prints String whatever the verb, %x and %s included
+func (ss ShareSet) Format(f fmt.State, verb rune) {
+	io.WriteString(f, ss.String())
+}
+
+// String returns a fingerprint of the secret, never its value
+func (s Secret) String() string {
+	if s.b == nil {
+		return "tss.Secret{destroyed}"
+	}
+	return fmt.Sprintf("tss.Secret{len:%d, sha256:%s}", len(s.b), fingerprint(s.b))
+}
+
+// GoString is String, so %#v is redacted too
+func (s Secret) GoString() string {
+	return s.String()
+}
+
+// Format prints String whatever the verb, %x and %s included
+func (s Secret) Format(f fmt.State, verb rune) {
+	io.WriteString(f, s.String())
+}
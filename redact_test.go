@@ -0,0 +1,33 @@
+package tss
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRedactedFormat(t *testing.T) {
+	secret := randomBytes(32)
+	shares, _ := CreateShares(secret, 3, 2)
+	value := hex.EncodeToString(shares[2][1:])
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%x", "%X", "%q", "%d"} {
+		for _, out := range []string{
+			fmt.Sprintf(format, shares[2]),
+			fmt.Sprintf(format, shares),
+			fmt.Sprintf(format, NewSecret(secret)),
+			fmt.Sprintf(format, *NewSecret(secret)),
+			fmt.Sprintf(format, []interface{}{shares[2]}),
+		} {
+			if strings.Contains(strings.ToLower(out), value[:16]) || strings.Contains(out, hex.EncodeToString(secret)[:16]) || strings.Contains(out, string(secret)) {
+				failNow(t, fmt.Errorf("%s leaks the value: %s", format, out))
+			}
+		}
+	}
+	if got := fmt.Sprint(shares[2]); !strings.HasPrefix(got, "tss.Share{idx:3, len:33, sha256:") {
+		failNow(t, fmt.Errorf("unexpected share format %s", got))
+	}
+	if got := fmt.Sprint(NewSecret(secret)); !strings.HasPrefix(got, "tss.Secret{len:32, sha256:") {
+		failNow(t, fmt.Errorf("unexpected secret format %s", got))
+	}
+}
@@ -0,0 +1,276 @@
+package tss
+
+const (
+	// MinParityBytes is the smallest parity overhead per Reed-Solomon block
+	MinParityBytes = 2
+	// MaxParityBytes is the largest parity overhead per Reed-Solomon block
+	MaxParityBytes = 128
+	// rsBlockBytes is the length of a full Reed-Solomon codeword in GF(256)
+	rsBlockBytes = 255
+	// rsHeaderParity is the parity of the block protecting the container header
+	rsHeaderParity = 8
+	// rsHeaderBytes is the length of the encoded container header
+	rsHeaderBytes = 3 + rsHeaderParity
+)
+
+var (
+	ErrInvalidParity = validationError("invalid parity")
+	ErrUncorrectable = integrityError("too many errors to correct")
+)
+
+// EncodeShareRS wraps a share in a Reed-Solomon coded container. The share is
+// cut in blocks of 255-parity bytes, each followed by parity bytes, so up to
+// parity/2 corrupted bytes per block are corrected by DecodeShareRS.
+func EncodeShareRS(share Share, parity int) ([]byte, error) {
+	if len(share) < MinShareBytes || len(share) > MaxShareBytes {
+		return nil, ErrInvalidShare
+	}
+	if parity < MinParityBytes || parity > MaxParityBytes {
+		return nil, ErrInvalidParity
+	}
+	gen := rsGenerator(parity)
+	out := rsEncode([]byte{byte(parity), byte(len(share) >> 8), byte(len(share))}, rsGenerator(rsHeaderParity))
+	k := rsBlockBytes - parity
+	for i := 0; i < len(share); i += k {
+		end := i + k
+		if end > len(share) {
+			end = len(share)
+		}
+		out = append(out, rsEncode(share[i:end], gen)...)
+	}
+	return out, nil
+}
+
+// DecodeShareRS extracts the share of a container written by EncodeShareRS,
+// correcting corrupted bytes on the way
+func DecodeShareRS(data []byte) (Share, error) {
+	if len(data) < rsHeaderBytes {
+		return nil, ErrInvalidShare
+	}
+	header, err := rsDecode(data[:rsHeaderBytes], rsHeaderParity)
+	if err != nil {
+		return nil, err
+	}
+	parity := int(header[0])
+	size := int(header[1])<<8 | int(header[2])
+	if parity < MinParityBytes || parity > MaxParityBytes {
+		return nil, ErrInvalidParity
+	}
+	if size < MinShareBytes || size > MaxShareBytes {
+		return nil, ErrInvalidShare
+	}
+	k := rsBlockBytes - parity
+	blocks := (size + k - 1) / k
+	if len(data) != rsHeaderBytes+size+blocks*parity {
+		return nil, ErrInvalidShare
+	}
+	share := make(Share, 0, size)
+	data = data[rsHeaderBytes:]
+	for len(data) > 0 {
+		n := rsBlockBytes
+		if n > len(data) {
+			n = len(data)
+		}
+		block, err := rsDecode(data[:n], parity)
+		if err != nil {
+			return nil, err
+		}
+		share = append(share, block...)
+		data = data[n:]
+	}
+	return share, nil
+}
+
+// rsGenerator returns the generator polynomial of a code with nsym parity
+// symbols, coefficients from the highest degree
+func rsGenerator(nsym int) []byte {
+	g := []byte{1}
+	for i := 0; i < nsym; i++ {
+		g = polyMul(g, []byte{1, expOp[i]})
+	}
+	return g
+}
+
+// rsEncode returns msg followed by its parity symbols
+func rsEncode(msg []byte, gen []byte) []byte {
+	nsym := len(gen) - 1
+	out := make([]byte, len(msg)+nsym)
+	copy(out, msg)
+	for i := range msg {
+		coef := out[i]
+		if coef != 0 {
+			for j := 1; j < len(gen); j++ {
+				out[i+j] ^= mul(gen[j], coef)
+			}
+		}
+	}
+	copy(out, msg)
+	return out
+}
+
+// rsDecode corrects up to nsym/2 errors in codeword and returns the message part
+func rsDecode(codeword []byte, nsym int) ([]byte, error) {
+	if len(codeword) <= nsym {
+		return nil, ErrInvalidShare
+	}
+	msg := append([]byte{}, codeword...)
+	synd := rsSyndromes(msg, nsym)
+	if !allZero(synd) {
+		loc, err := rsErrorLocator(synd, nsym)
+		if err != nil {
+			return nil, err
+		}
+		pos, err := rsFindErrors(reverse(loc), len(msg))
+		if err != nil {
+			return nil, err
+		}
+		rsCorrect(msg, synd, pos)
+		if !allZero(rsSyndromes(msg, nsym)) {
+			return nil, ErrUncorrectable
+		}
+	}
+	return msg[:len(msg)-nsym], nil
+}
+
+// rsSyndromes returns the syndromes of msg, padded with a leading zero
+func rsSyndromes(msg []byte, nsym int) []byte {
+	synd := make([]byte, nsym+1)
+	for i := 0; i < nsym; i++ {
+		synd[i+1] = polyEval(msg, expOp[i])
+	}
+	return synd
+}
+
+// rsErrorLocator computes the error locator polynomial with Berlekamp-Massey
+func rsErrorLocator(synd []byte, nsym int) ([]byte, error) {
+	loc := []byte{1}
+	old := []byte{1}
+	shift := len(synd) - nsym
+	for i := 0; i < nsym; i++ {
+		k := i + shift
+		delta := synd[k]
+		for j := 1; j < len(loc); j++ {
+			delta ^= mul(loc[len(loc)-j-1], synd[k-j])
+		}
+		old = append(old, 0)
+		if delta != 0 {
+			if len(old) > len(loc) {
+				next := polyScale(old, delta)
+				old = polyScale(loc, div(1, delta))
+				loc = next
+			}
+			loc = polyAdd(loc, polyScale(old, delta))
+		}
+	}
+	for len(loc) > 0 && loc[0] == 0 {
+		loc = loc[1:]
+	}
+	if (len(loc)-1)*2 > nsym {
+		return nil, ErrUncorrectable
+	}
+	return loc, nil
+}
+
+// rsFindErrors returns the positions of the errors, the roots of the error
+// locator found by a Chien search
+func rsFindErrors(loc []byte, n int) ([]int, error) {
+	errs := len(loc) - 1
+	var pos []int
+	for i := 0; i < n; i++ {
+		if polyEval(loc, expOp[i%255]) == 0 {
+			pos = append(pos, n-1-i)
+		}
+	}
+	if len(pos) != errs {
+		return nil, ErrUncorrectable
+	}
+	return pos, nil
+}
+
+// rsCorrect fixes msg in place computing the error magnitudes with Forney's algorithm
+func rsCorrect(msg []byte, synd []byte, pos []int) {
+	coef := make([]int, len(pos))
+	loc := []byte{1}
+	for i, p := range pos {
+		coef[i] = len(msg) - 1 - p
+		loc = polyMul(loc, polyAdd([]byte{1}, []byte{gfPow(3, coef[i]), 0}))
+	}
+	product := polyMul(reverse(synd), loc)
+	eval := reverse(product[len(product)-len(loc):])
+	x := make([]byte, len(coef))
+	for i, c := range coef {
+		x[i] = gfPow(3, c)
+	}
+	for i, xi := range x {
+		inv := div(1, xi)
+		var prime byte = 1
+		for j := range x {
+			if j != i {
+				prime = mul(prime, add(1, mul(inv, x[j])))
+			}
+		}
+		y := mul(xi, polyEval(reverse(eval), inv))
+		msg[pos[i]] ^= div(y, prime)
+	}
+}
+
+func gfPow(x byte, power int) byte {
+	return expOp[((logOp[x]*power)%255+255)%255]
+}
+
+func polyScale(p []byte, x byte) []byte {
+	r := make([]byte, len(p))
+	for i := range p {
+		r[i] = mul(p[i], x)
+	}
+	return r
+}
+
+func polyAdd(p []byte, q []byte) []byte {
+	n := len(p)
+	if len(q) > n {
+		n = len(q)
+	}
+	r := make([]byte, n)
+	copy(r[n-len(p):], p)
+	for i := range q {
+		r[i+n-len(q)] ^= q[i]
+	}
+	return r
+}
+
+func polyMul(p []byte, q []byte) []byte {
+	r := make([]byte, len(p)+len(q)-1)
+	for j := range q {
+		for i := range p {
+			r[i+j] ^= mul(p[i], q[j])
+		}
+	}
+	return r
+}
+
+// polyEval evaluates p at x with Horner's scheme, coefficients from the highest degree
+func polyEval(p []byte, x byte) byte {
+	y := p[0]
+	for i := 1; i < len(p); i++ {
+		y = mul(y, x) ^ p[i]
+	}
+	return y
+}
+
+func reverse(p []byte) []byte {
+	r := make([]byte, len(p))
+	for i := range p {
+		r[len(p)-1-i] = p[i]
+	}
+	return r
+}
+
+func allZero(p []byte) bool {
+	for _, b := range p {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
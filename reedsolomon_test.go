@@ -0,0 +1,67 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	mrand "math/rand"
+	"testing"
+)
+
+func TestShareRS(t *testing.T) {
+	for _, parity := range []int{MinParityBytes, 10, 32, MaxParityBytes} {
+		for _, size := range []int{1, 100, 1000} {
+			t.Run(fmt.Sprintf("%d-%d", parity, size), func(t *testing.T) {
+				testShareRS(t, size, parity)
+			})
+		}
+	}
+}
+
+func testShareRS(t *testing.T, secretSize int, parity int) {
+	shares, err := CreateShares(randomBytes(secretSize), 2, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	data, err := EncodeShareRS(shares[0], parity)
+	if err != nil {
+		failNow(t, err)
+	}
+	// damage parity/2 bytes of every block, and the header
+	corrupt(data[:rsHeaderBytes], rsHeaderParity/2)
+	for i := rsHeaderBytes; i < len(data); i += rsBlockBytes {
+		end := i + rsBlockBytes
+		if end > len(data) {
+			end = len(data)
+		}
+		corrupt(data[i:end], parity/2)
+	}
+	share, err := DecodeShareRS(data)
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(share, shares[0]) {
+		failNow(t, fmt.Errorf("share mismatch"))
+	}
+}
+
+func TestShareRSErrors(t *testing.T) {
+	share := randomBytes(200)
+	if _, err := EncodeShareRS(share, 1); err != ErrInvalidParity {
+		failNow(t, expected(ErrInvalidParity, err))
+	}
+	data, _ := EncodeShareRS(share, 16)
+	corrupt(data[rsHeaderBytes:], 12)
+	if _, err := DecodeShareRS(data); err != ErrUncorrectable {
+		failNow(t, expected(ErrUncorrectable, err))
+	}
+	if _, err := DecodeShareRS(data[:rsHeaderBytes+10]); err != ErrInvalidShare {
+		failNow(t, expected(ErrInvalidShare, err))
+	}
+}
+
+// corrupt flips n distinct random bytes of b
+func corrupt(b []byte, n int) {
+	for _, i := range mrand.Perm(len(b))[:n] {
+		b[i] ^= byte(1 + mrand.Intn(255))
+	}
+}
@@ -0,0 +1,23 @@
+package tss
+
+import "context"
+
+// RecoverShare regenerates the share at index from a set of shares, for
+// instance to replace the share of a custodian who lost it. The set must hold
+// at least threshold shares of the split, the secret itself is never
+// reconstructed. Fewer shares give a wrong share, which cannot be detected.
+func RecoverShare(shares ShareSet, index byte, opts ...Option) (Share, error) {
+	if index == 0 {
+		return nil, ErrInvalidShareIndex
+	}
+	secretSize, err := checkShares(shares, newConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	share := make(Share, secretSize+1)
+	share[0] = index
+	if err := recoverInto(context.Background(), share[1:], shares, index); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
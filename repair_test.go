@@ -0,0 +1,46 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestRecoverShare(t *testing.T) {
+	secret := randomBytes(32)
+	shares, _ := CreateShares(secret, 5, 3)
+	for _, lost := range []int{0, 3} {
+		repaired, err := RecoverShare(ShareSet{shares[(lost+1)%5], shares[(lost+2)%5], shares[(lost+3)%5]}, shares[lost].Index())
+		if err != nil {
+			failNow(t, err)
+		}
+		if !bytes.Equal(repaired, shares[lost]) {
+			failNow(t, fmt.Errorf("share %d not repaired", lost+1))
+		}
+	}
+	// a share for a new custodian works with the others
+	extra, _ := RecoverShare(shares[:3], 42)
+	testRecover(t, secret, ShareSet{extra, shares[3], shares[4]})
+	if _, err := RecoverShare(shares, 0); err != ErrInvalidShareIndex {
+		failNow(t, expected(ErrInvalidShareIndex, err))
+	}
+}
+
+func TestEvaluateAt(t *testing.T) {
+	secret := randomBytes(32)
+	shares, _ := CreateShares(secret, 4, 2)
+	value, err := EvaluateAt(shares[2:], 0)
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(value, secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+	value, _ = EvaluateAt(shares[2:], 2)
+	if !bytes.Equal(value, shares[1].Value()) {
+		failNow(t, fmt.Errorf("share 2 mismatch"))
+	}
+	if _, err := EvaluateAt(shares[:1], 3); err != ErrTooFewShares {
+		failNow(t, expected(ErrTooFewShares, err))
+	}
+}
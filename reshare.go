@@ -0,0 +1,118 @@
+package tss
+
+import "io"
+
+// Reshare turns a quorum of shares into a fresh split of the same secret
+// among sharesCount custodians, threshold of them being required, for
+// instance when custodians leave. The secret is never reconstructed, not even
+// internally: every old share is split on its own and the new shares combine
+// these sub-shares with the Lagrange coefficients of the old indexes.
+// The old shares must hold at least the old threshold, fewer give a split of
+// a wrong secret. WithRandomIndexes, WithRand, WithDeterministic,
+// WithStrictMode and WithMaxShares apply, the old shares standing in for the
+// secret in deterministic mode: the same shares in the same order give the
+// same new split.
+func Reshare(shares ShareSet, sharesCount int, threshold int, opts ...Option) (ShareSet, error) {
+	cfg := newConfig(opts)
+	secretSize, err := checkShares(shares, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if sharesCount < MinShares {
+		return nil, ErrTooFewShares
+	}
+	if sharesCount > cfg.sharesLimit() {
+		return nil, ErrTooManyShares
+	}
+	random, err := reshareSource(shares, threshold, cfg)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]byte, sharesCount)
+	if cfg.randomIndexes {
+		if err := randomIndexes(random, indexes); err != nil {
+			return nil, err
+		}
+	} else {
+		for i := range indexes {
+			indexes[i] = byte(i + 1)
+		}
+	}
+	return reshare(shares, secretSize, indexes, threshold, random)
+}
+
+// ChangeThreshold raises or lowers the threshold of a split without
+// reconstructing the secret: from a quorum of shares it issues new shares at
+// indexes, typically the indexes of every custodian including the ones absent.
+// The new shares do not mix with the old ones, which must be destroyed for
+// the new threshold to hold. The options apply as for Reshare.
+func ChangeThreshold(shares ShareSet, indexes []byte, threshold int, opts ...Option) (ShareSet, error) {
+	cfg := newConfig(opts)
+	secretSize, err := checkShares(shares, cfg)
+	if err != nil {
+		return nil, err
+	}
+	var seen [256]bool
+	for _, x := range indexes {
+		if x == 0 || seen[x] {
+			return nil, ErrInvalidIndexes
+		}
+		seen[x] = true
+	}
+	if len(indexes) < MinShares {
+		return nil, ErrTooFewShares
+	}
+	if len(indexes) > cfg.sharesLimit() {
+		return nil, ErrTooManyShares
+	}
+	random, err := reshareSource(shares, threshold, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return reshare(shares, secretSize, indexes, threshold, random)
+}
+
+// reshareSource is the source of the coefficients of a reshare, the old
+// shares keying the derivation in deterministic mode as the secret is never
+// reconstructed
+func reshareSource(shares ShareSet, threshold int, cfg *config) (io.Reader, error) {
+	var key []byte
+	for _, s := range shares {
+		key = append(key, s...)
+	}
+	defer erase(key)
+	return cfg.source(key, threshold)
+}
+
+// reshare issues shares at indexes from shares checked by checkShares,
+// drawing the coefficients from random
+func reshare(shares ShareSet, secretSize int, indexes []byte, threshold int, random io.Reader) (ShareSet, error) {
+	if threshold > len(indexes) || threshold < MinThreshold {
+		return nil, ErrInvalidThreshold
+	}
+	u := shares.Indexes()
+	lambda := make([]byte, len(shares))
+	for i := range shares {
+		lambda[i] = poly(i, u)
+	}
+	out := make(ShareSet, len(indexes))
+	for k := range out {
+		out[k] = make(Share, secretSize+1)
+		out[k][0] = indexes[k]
+	}
+	a := make([]byte, threshold)
+	defer erase(a)
+	for j := 0; j < secretSize; j++ {
+		for i, s := range shares {
+			if _, err := io.ReadFull(random, a); err != nil {
+				eraseShares(out)
+				return nil, err
+			}
+			a[0] = s[j+1]
+			for k := range out {
+				out[k][j+1] = add(out[k][j+1], mul(lambda[i], eval(indexes[k], a)))
+			}
+		}
+	}
+	return out, nil
+}
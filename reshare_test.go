@@ -0,0 +1,92 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestReshare(t *testing.T) {
+	secret := randomBytes(32)
+	shares, _ := CreateShares(secret, 5, 3)
+	fresh, err := Reshare(shares[1:4], 7, 4)
+	if err != nil {
+		failNow(t, err)
+	}
+	if len(fresh) != 7 {
+		failNow(t, fmt.Errorf("got %d shares", len(fresh)))
+	}
+	testRecover(t, secret, fresh[3:])
+	testRecover(t, secret, ShareSet{fresh[0], fresh[2], fresh[4], fresh[6]})
+	// the old threshold is not enough anymore
+	recovered, _ := RecoverSecret(fresh[:3])
+	if bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("recovered with 3 shares of a 4 threshold split"))
+	}
+	// old and new shares do not mix
+	for i := range fresh[:5] {
+		if bytes.Equal(fresh[i], shares[i]) {
+			failNow(t, fmt.Errorf("share %d unchanged", i+1))
+		}
+	}
+}
+
+func TestReshareErrors(t *testing.T) {
+	shares, _ := CreateShares(randomBytes(32), 3, 2)
+	if _, err := Reshare(shares, 3, 4); err != ErrInvalidThreshold {
+		failNow(t, expected(ErrInvalidThreshold, err))
+	}
+	if _, err := Reshare(shares[:1], 3, 2); err != ErrTooFewShares {
+		failNow(t, expected(ErrTooFewShares, err))
+	}
+}
+
+func TestReshareOptions(t *testing.T) {
+	secret := randomBytes(32)
+	shares, _ := CreateShares(secret, 5, 3)
+	if _, err := Reshare(shares[:3], 5, 3, WithRand(bytes.NewReader(randomBytes(1000))), WithStrictMode()); err != ErrNotAllowed {
+		failNow(t, expected(ErrNotAllowed, err))
+	}
+	if _, err := Reshare(shares[:3], 5, 3, WithStrictMode()); err != nil {
+		failNow(t, err)
+	}
+	opts := []Option{WithDeterministic([]byte("salt"), "reshare"), WithRandomIndexes()}
+	a, _ := Reshare(shares[:3], 5, 3, opts...)
+	b, err := Reshare(shares[:3], 5, 3, opts...)
+	if err != nil {
+		failNow(t, err)
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			failNow(t, fmt.Errorf("share %d differs between deterministic reshares", i+1))
+		}
+	}
+	testRecover(t, secret, a[2:])
+}
+
+func TestChangeThreshold(t *testing.T) {
+	secret := randomBytes(32)
+	shares, _ := CreateShares(secret, 5, 3)
+	// tightened after an incident, custodian 2 being away
+	tight, err := ChangeThreshold(ShareSet{shares[0], shares[2], shares[4]}, shares.Indexes(), 4)
+	if err != nil {
+		failNow(t, err)
+	}
+	for i, s := range tight {
+		if s.Index() != shares[i].Index() {
+			failNow(t, fmt.Errorf("share %d has index %d", i+1, s.Index()))
+		}
+	}
+	testRecover(t, secret, tight[1:])
+	loose, _ := ChangeThreshold(tight[:4], tight.Indexes(), 2)
+	testRecover(t, secret, loose[3:])
+	if _, err := ChangeThreshold(shares[:3], []byte{1, 1, 2}, 2); err != ErrInvalidIndexes {
+		failNow(t, expected(ErrInvalidIndexes, err))
+	}
+	if _, err := ChangeThreshold(shares[:3], shares.Indexes(), 2, WithRand(bytes.NewReader(randomBytes(1000))), WithStrictMode()); err != ErrNotAllowed {
+		failNow(t, expected(ErrNotAllowed, err))
+	}
+	if _, err := ChangeThreshold(shares[:3], shares.Indexes(), 2, WithStrictMode()); err != nil {
+		failNow(t, err)
+	}
+}
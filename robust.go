@@ -0,0 +1,125 @@
+package tss
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+
+	"github.com/antik10ud/go-comb/comb"
+)
+
+// MaxRecoverySubsets is the largest number of share subsets
+// RecoverSecretRobust tries
+const MaxRecoverySubsets = 1 << 16
+
+var (
+	ErrNoConsistentSubset = integrityError("no subset of the shares matches the secret digest")
+	ErrTooManySubsets     = limitsError("too many share subsets to search")
+)
+
+// SecretDigest returns the SHA-256 digest of secret RecoverSecretRobust checks
+// candidates against. The digest allows to test guesses of the secret, keep it
+// with the shares only for secrets that cannot be guessed, such as keys.
+func SecretDigest(secret []byte) []byte {
+	digest := sha256.Sum256(secret)
+	return digest[:]
+}
+
+// RecoverSecretRobust recovers the secret from more than threshold shares when
+// some of them may be corrupt. It tries subsets of threshold shares until one
+// gives a secret matching digest, as returned by SecretDigest, and returns the
+// indexes of the fewest shares inconsistent with it. The secret is found
+// while threshold shares are intact, the corrupt shares are told apart while
+// at most (len(shares)-threshold+1)/2 are corrupt. Up to MaxRecoverySubsets
+// subsets are tried.
+func RecoverSecretRobust(shares ShareSet, threshold int, digest []byte, opts ...Option) (secret []byte, corrupt []byte, err error) {
+	cfg := newConfig(opts)
+	if threshold < cfg.minThreshold() || threshold > MaxShares {
+		return nil, nil, ErrInvalidThreshold
+	}
+	if len(digest) != sha256.Size {
+		return nil, nil, ErrInvalidParams
+	}
+	if len(shares) < threshold {
+		return nil, nil, &ThresholdError{Need: threshold, Have: len(shares)}
+	}
+	secretSize, err := checkShares(shares, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if binomial(len(shares), threshold) > MaxRecoverySubsets {
+		return nil, nil, ErrTooManySubsets
+	}
+	cmb, err := comb.NewNoRepLex(len(shares), threshold)
+	if err != nil {
+		return nil, nil, ErrInvalidThreshold
+	}
+	ctx := context.Background()
+	subset := make(ShareSet, threshold)
+	value := make([]byte, secretSize)
+	defer erase(value)
+	for v := cmb.Next(); v != nil; v = cmb.Next() {
+		for i, pick := range *v {
+			subset[i] = shares[pick]
+		}
+		if err := recoverInto(ctx, value, subset, 0); err != nil {
+			erase(secret)
+			return nil, nil, err
+		}
+		candidate := sha256.Sum256(value)
+		if subtle.ConstantTimeCompare(candidate[:], digest) != 1 {
+			continue
+		}
+		inconsistent, err := inconsistentShares(ctx, shares, subset)
+		if err != nil {
+			erase(secret)
+			return nil, nil, err
+		}
+		if secret == nil {
+			secret = append([]byte{}, value...)
+		}
+		if corrupt == nil || len(inconsistent) < len(corrupt) {
+			corrupt = inconsistent
+		}
+		if len(corrupt) == 0 {
+			break
+		}
+	}
+	if secret == nil {
+		return nil, nil, ErrNoConsistentSubset
+	}
+	return secret, corrupt, nil
+}
+
+// inconsistentShares returns the indexes of the shares not lying on the
+// polynomial defined by subset
+func inconsistentShares(ctx context.Context, shares ShareSet, subset ShareSet) ([]byte, error) {
+	corrupt := []byte{}
+	value := make([]byte, len(shares[0])-1)
+	defer erase(value)
+	for _, s := range shares {
+		if err := recoverInto(ctx, value, subset, s[0]); err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(value, s[1:]) {
+			corrupt = append(corrupt, s[0])
+		}
+	}
+	return corrupt, nil
+}
+
+// binomial returns n choose k, or MaxRecoverySubsets+1 when it is larger
+func binomial(n int, k int) int {
+	if n-k < k {
+		k = n - k
+	}
+	r := 1
+	for i := 1; i <= k; i++ {
+		r = r * (n - k + i) / i
+		if r > MaxRecoverySubsets {
+			return MaxRecoverySubsets + 1
+		}
+	}
+	return r
+}
@@ -0,0 +1,45 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRecoverSecretRobust(t *testing.T) {
+	secret := randomBytes(32)
+	shares, _ := CreateShares(secret, 6, 3)
+	shares[1][5] ^= 1
+	shares[4][1] ^= 0x80
+	recovered, corrupt, err := RecoverSecretRobust(shares, 3, SecretDigest(secret))
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+	if !bytes.Equal(corrupt, []byte{2, 5}) {
+		failNow(t, fmt.Errorf("corrupt shares %v, want [2 5]", corrupt))
+	}
+}
+
+func TestRecoverSecretRobustErrors(t *testing.T) {
+	secret := randomBytes(32)
+	shares, _ := CreateShares(secret, 4, 3)
+	shares[0][1] ^= 1
+	shares[1][1] ^= 2
+	if _, _, err := RecoverSecretRobust(shares, 3, SecretDigest(secret)); err != ErrNoConsistentSubset {
+		failNow(t, expected(ErrNoConsistentSubset, err))
+	}
+	if _, _, err := RecoverSecretRobust(shares[:2], 3, SecretDigest(secret)); !errors.Is(err, ErrThresholdNotMet) {
+		failNow(t, expected(ErrThresholdNotMet, err))
+	}
+	if _, _, err := RecoverSecretRobust(shares, 3, secret[:8]); err != ErrInvalidParams {
+		failNow(t, expected(ErrInvalidParams, err))
+	}
+	many, _ := CreateShares(secret, 40, 20)
+	if _, _, err := RecoverSecretRobust(many, 20, SecretDigest(secret)); err != ErrTooManySubsets {
+		failNow(t, expected(ErrTooManySubsets, err))
+	}
+}
@@ -0,0 +1,207 @@
+package tss
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+// Hash algorithms of the robust shares of draft-mcgrew-tss, section 5. The
+// hash of the secret is appended to it before splitting and checked after
+// recovery.
+const (
+	RTSSHashNone   byte = 0
+	RTSSHashSHA1   byte = 1
+	RTSSHashSHA256 byte = 2
+)
+
+// rtssHeaderBytes is the size of the share header of the draft: identifier,
+// hash algorithm, threshold and share length
+const rtssHeaderBytes = MaxIdentifierBytes + 1 + 1 + 2
+
+var (
+	ErrUnknownHash = validationError("unknown RTSS hash algorithm")
+)
+
+// rtssHashBytes returns the size of the hash with the algorithm id
+func rtssHashBytes(id byte) int {
+	switch id {
+	case RTSSHashSHA1:
+		return sha1.Size
+	case RTSSHashSHA256:
+		return sha256.Size
+	}
+	return 0
+}
+
+// rtssHash returns the hash of secret with the algorithm id, nil for
+// RTSSHashNone
+func rtssHash(id byte, secret []byte) ([]byte, error) {
+	switch id {
+	case RTSSHashNone:
+		return nil, nil
+	case RTSSHashSHA1:
+		sum := sha1.Sum(secret)
+		return sum[:], nil
+	case RTSSHashSHA256:
+		sum := sha256.Sum256(secret)
+		return sum[:], nil
+	}
+	return nil, ErrUnknownHash
+}
+
+// MarshalRTSS writes share with the header of draft-mcgrew-tss: the
+// identifier padded with zeros to 16 bytes, the hash algorithm, the threshold
+// and the length of the share, index included.
+func MarshalRTSS(share Share, params ShareParams, hash byte) ([]byte, error) {
+	if !share.Valid() {
+		return nil, ErrInvalidShare
+	}
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	if _, err := rtssHash(hash, nil); err != nil {
+		return nil, err
+	}
+	b := make([]byte, rtssHeaderBytes, rtssHeaderBytes+len(share))
+	copy(b, params.Identifier)
+	b[MaxIdentifierBytes], b[MaxIdentifierBytes+1] = hash, byte(params.Threshold)
+	b[MaxIdentifierBytes+2], b[MaxIdentifierBytes+3] = byte(len(share)>>8), byte(len(share))
+	return append(b, share...), nil
+}
+
+// UnmarshalRTSS reads a share written with the header of draft-mcgrew-tss,
+// by MarshalRTSS or another implementation. It accepts the quirks found in
+// the wild: identifiers padded with spaces rather than zeros, and share
+// lengths counting the index or not.
+func UnmarshalRTSS(data []byte) (share Share, params ShareParams, hash byte, err error) {
+	if len(data) < rtssHeaderBytes+MinShareBytes {
+		return nil, ShareParams{}, 0, ErrInvalidShare
+	}
+	header, body := data[:rtssHeaderBytes], data[rtssHeaderBytes:]
+	hash = header[MaxIdentifierBytes]
+	if _, err := rtssHash(hash, nil); err != nil {
+		return nil, ShareParams{}, 0, err
+	}
+	length := int(header[MaxIdentifierBytes+2])<<8 | int(header[MaxIdentifierBytes+3])
+	if length != len(body) && length != len(body)-1 {
+		return nil, ShareParams{}, 0, ErrShareSize
+	}
+	params = ShareParams{
+		Identifier: bytes.TrimRight(append([]byte(nil), header[:MaxIdentifierBytes]...), "\x00 "),
+		Threshold:  int(header[MaxIdentifierBytes+1]),
+	}
+	if err := params.validate(); err != nil {
+		return nil, ShareParams{}, 0, err
+	}
+	share = append(Share{}, body...)
+	if !share.Valid() {
+		return nil, ShareParams{}, 0, ErrInvalidShare
+	}
+	return share, params, hash, nil
+}
+
+// ParseRTSSText reads the human format of the Ruby tss gem,
+// tss~v1~identifier~threshold~base64, into the binary share it holds. The
+// identifier and threshold of the text must match the ones of the header.
+func ParseRTSSText(s string) ([]byte, error) {
+	fields := strings.Split(strings.TrimSpace(s), "~")
+	if len(fields) != 5 || fields[0] != "tss" || fields[1] != "v1" {
+		return nil, ErrInvalidShare
+	}
+	encoded := strings.TrimRight(fields[4], "=")
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		if data, err = base64.RawStdEncoding.DecodeString(encoded); err != nil {
+			return nil, ErrInvalidShare
+		}
+	}
+	_, params, _, err := UnmarshalRTSS(data)
+	if err != nil {
+		return nil, err
+	}
+	if threshold, err := strconv.Atoi(fields[3]); err != nil || threshold != params.Threshold || fields[2] != string(params.Identifier) {
+		return nil, ErrInvalidParams
+	}
+	return data, nil
+}
+
+// CreateRTSSShares splits secret followed by its hash with the algorithm
+// hash, and writes the shares with MarshalRTSS
+func CreateRTSSShares(secret []byte, sharesCount int, threshold int, identifier []byte, hash byte, opts ...Option) ([][]byte, error) {
+	sum, err := rtssHash(hash, secret)
+	if err != nil {
+		return nil, err
+	}
+	params := ShareParams{Identifier: identifier, Threshold: threshold}
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	robust := append(append(make([]byte, 0, len(secret)+len(sum)), secret...), sum...)
+	defer erase(robust)
+	shares, err := CreateShares(robust, sharesCount, threshold, append(opts, WithConsumeSecret(false))...)
+	if err != nil {
+		return nil, err
+	}
+	defer eraseShares(shares)
+	if newConfig(opts).consume {
+		erase(secret)
+	}
+	out := make([][]byte, len(shares))
+	for i, s := range shares {
+		if out[i], err = MarshalRTSS(s, params, hash); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// RecoverRTSSSecret recovers the secret of shares written with the header of
+// draft-mcgrew-tss, by this package or another implementation. The shares
+// must agree on identifier, hash algorithm and threshold, and the recovered
+// hash must match, else ErrChecksum.
+func RecoverRTSSSecret(data [][]byte, opts ...Option) ([]byte, error) {
+	shares := make(ShareSet, len(data))
+	defer eraseShares(shares)
+	ids := make([][]byte, len(data))
+	var first ShareParams
+	var hash byte
+	for i, d := range data {
+		share, params, h, err := UnmarshalRTSS(d)
+		if err != nil {
+			return nil, &ShareError{Position: i + 1, Reason: err}
+		}
+		if i == 0 {
+			first, hash = params, h
+		} else if h != hash || params.Threshold != first.Threshold {
+			return nil, &ShareError{Position: i + 1, Index: share[0], Reason: ErrInvalidParams}
+		}
+		shares[i], ids[i] = share, params.Identifier
+	}
+	if err := mixedShares(ids...); err != nil {
+		return nil, err
+	}
+	if len(shares) == 0 {
+		return nil, ErrTooFewShares
+	}
+	robust, err := RecoverSecret(shares, append(opts, WithThreshold(first.Threshold))...)
+	if err != nil {
+		return nil, err
+	}
+	defer erase(robust)
+	hashBytes := rtssHashBytes(hash)
+	if len(robust) <= hashBytes {
+		return nil, ErrChecksum
+	}
+	secret := append([]byte{}, robust[:len(robust)-hashBytes]...)
+	sum, _ := rtssHash(hash, secret)
+	if hashBytes > 0 && subtle.ConstantTimeCompare(sum, robust[len(secret):]) != 1 {
+		erase(secret)
+		return nil, ErrChecksum
+	}
+	return secret, nil
+}
@@ -0,0 +1,62 @@
+package tss
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRTSS(t *testing.T) {
+	for _, hash := range []byte{RTSSHashNone, RTSSHashSHA1, RTSSHashSHA256} {
+		secret := randomBytes(24)
+		data, err := CreateRTSSShares(secret, 5, 3, []byte("backup"), hash)
+		if err != nil {
+			failNow(t, err)
+		}
+		recovered, err := RecoverRTSSSecret([][]byte{data[4], data[1], data[2]})
+		if err != nil {
+			failNow(t, err)
+		}
+		if !bytes.Equal(recovered, secret) {
+			failNow(t, fmt.Errorf("hash %d: recovered %x, want %x", hash, recovered, secret))
+		}
+		if _, err := RecoverRTSSSecret(data[:2]); !errors.Is(err, ErrThresholdNotMet) {
+			failNow(t, fmt.Errorf("two shares: %v", err))
+		}
+		if hash != RTSSHashNone {
+			data[0][len(data[0])-1] ^= 1
+			if _, err := RecoverRTSSSecret(data[:3]); err != ErrChecksum {
+				failNow(t, expected(ErrChecksum, err))
+			}
+		}
+	}
+}
+
+// TestRTSSQuirks reads headers padded with spaces and with a share length
+// leaving the index out, and the text format of the Ruby gem
+func TestRTSSQuirks(t *testing.T) {
+	secret := randomBytes(16)
+	data, _ := CreateRTSSShares(secret, 3, 2, []byte("id"), RTSSHashSHA256)
+	for _, d := range data {
+		copy(d[2:MaxIdentifierBytes], bytes.Repeat([]byte(" "), MaxIdentifierBytes-2))
+		length := len(d) - rtssHeaderBytes - 1
+		d[MaxIdentifierBytes+2], d[MaxIdentifierBytes+3] = byte(length>>8), byte(length)
+	}
+	text := "tss~v1~id~2~" + base64.URLEncoding.EncodeToString(data[2])
+	parsed, err := ParseRTSSText(text)
+	if err != nil {
+		failNow(t, err)
+	}
+	recovered, err := RecoverRTSSSecret([][]byte{data[0], parsed})
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, ErrChecksum)
+	}
+	if _, err := ParseRTSSText("tss~v1~other~2~" + base64.URLEncoding.EncodeToString(data[2])); err != ErrInvalidParams {
+		failNow(t, expected(ErrInvalidParams, err))
+	}
+}
@@ -0,0 +1,241 @@
+package tss
+
+import (
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha512"
+	"math/big"
+)
+
+var (
+	ErrInvalidScalar = validationError("invalid scalar")
+	ErrUnknownCurve  = validationError("unknown curve")
+)
+
+// ScalarBytes is the size of an encoded scalar of the supported curves
+const ScalarBytes = 32
+
+// Curve is an elliptic curve whose private scalars can be split in its order
+// field, with SplitScalar
+type Curve int
+
+const (
+	// Ed25519 scalars are little endian, modulo
+	// 2^252+27742317777372353535851937790883648493
+	Ed25519 Curve = iota + 1
+	// Secp256k1 scalars are big endian, modulo
+	// 0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141
+	Secp256k1
+	// P256 scalars are big endian, modulo the order of NIST P-256
+	P256
+)
+
+var (
+	ed25519Order, _   = new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	secp256k1Order, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+)
+
+func (c Curve) String() string {
+	switch c {
+	case Ed25519:
+		return "Ed25519"
+	case Secp256k1:
+		return "secp256k1"
+	case P256:
+		return "P-256"
+	}
+	return "unknown"
+}
+
+// Order returns the order of the group of the curve, the modulus of its
+// scalars
+func (c Curve) Order() *big.Int {
+	switch c {
+	case Ed25519:
+		return new(big.Int).Set(ed25519Order)
+	case Secp256k1:
+		return new(big.Int).Set(secp256k1Order)
+	case P256:
+		return new(big.Int).Set(elliptic.P256().Params().N)
+	}
+	return nil
+}
+
+// decode returns the integer encoded by scalar, which must be ScalarBytes
+// long and below the order
+func (c Curve) decode(scalar []byte) (*big.Int, error) {
+	if len(scalar) != ScalarBytes {
+		return nil, ErrInvalidScalar
+	}
+	b := append([]byte{}, scalar...)
+	if c == Ed25519 {
+		b = reverse(scalar)
+	}
+	defer erase(b)
+	x := new(big.Int).SetBytes(b)
+	if x.Cmp(c.Order()) >= 0 {
+		eraseInt(x)
+		return nil, ErrInvalidScalar
+	}
+	return x, nil
+}
+
+// encode returns the ScalarBytes encoding of x
+func (c Curve) encode(x *big.Int) []byte {
+	b := x.FillBytes(make([]byte, ScalarBytes))
+	if c == Ed25519 {
+		r := reverse(b)
+		erase(b)
+		return r
+	}
+	return b
+}
+
+// ScalarShare is a share of a private scalar: the value at Index of a
+// polynomial over the order field of the curve, encoded like a scalar
+type ScalarShare struct {
+	Index byte
+	Value []byte
+}
+
+// Ed25519Scalar returns the private scalar of key, the clamped first half of
+// the SHA-512 of its seed reduced modulo the order. Splitting the seed would
+// not give shares usable by threshold signing protocols, which work on the
+// scalar.
+func Ed25519Scalar(key ed25519.PrivateKey) []byte {
+	h := sha512.Sum512(key.Seed())
+	defer erase(h[:])
+	h[0] &= 248
+	h[31] &= 127
+	h[31] |= 64
+	le := reverse(h[:32])
+	defer erase(le)
+	x := new(big.Int).SetBytes(le)
+	defer eraseInt(x)
+	return Ed25519.encode(x.Mod(x, ed25519Order))
+}
+
+// SplitScalar splits a private scalar of curve with the Shamir scheme over
+// the order field, so that the shares are the secret shares threshold
+// signing protocols expect, the index of a share being its identifier.
+// Shares get indexes 1 to sharesCount, or random ones with WithRandomIndexes.
+func SplitScalar(curve Curve, scalar []byte, sharesCount int, threshold int, opts ...Option) ([]ScalarShare, error) {
+	cfg := newConfig(opts)
+	order := curve.Order()
+	if order == nil {
+		return nil, ErrUnknownCurve
+	}
+	secret, err := curve.decode(scalar)
+	if err != nil {
+		return nil, err
+	}
+	defer eraseInt(secret)
+	if sharesCount < MinShares {
+		return nil, ErrTooFewShares
+	}
+	if sharesCount > cfg.sharesLimit() {
+		return nil, ErrTooManyShares
+	}
+	if threshold > sharesCount || threshold < cfg.minThreshold() {
+		return nil, ErrInvalidThreshold
+	}
+	random, err := cfg.source(scalar, threshold)
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := shareIndexes(random, sharesCount, cfg.randomIndexes)
+	if err != nil {
+		return nil, err
+	}
+	xs := make([]*big.Int, sharesCount)
+	for i, x := range indexes {
+		xs[i] = big.NewInt(int64(x))
+	}
+	ys, err := primePoints(secret, order, xs, threshold, random)
+	if err != nil {
+		return nil, err
+	}
+	shares := make([]ScalarShare, sharesCount)
+	for i, y := range ys {
+		shares[i] = ScalarShare{Index: indexes[i], Value: curve.encode(y)}
+		eraseInt(y)
+	}
+	return shares, nil
+}
+
+// scalarPoints checks shares and returns their indexes and values
+func scalarPoints(curve Curve, shares []ScalarShare, cfg *config) ([]*big.Int, []*big.Int, error) {
+	if curve.Order() == nil {
+		return nil, nil, ErrUnknownCurve
+	}
+	n := len(shares)
+	if n < cfg.threshold {
+		return nil, nil, &ThresholdError{Need: cfg.threshold, Have: n}
+	}
+	if n < cfg.minThreshold() {
+		return nil, nil, ErrTooFewShares
+	}
+	if n > cfg.sharesLimit() {
+		return nil, nil, ErrTooManyShares
+	}
+	var seen [256]bool
+	xs := make([]*big.Int, n)
+	ys := make([]*big.Int, n)
+	for i, s := range shares {
+		if s.Index == 0 {
+			return nil, nil, &ShareError{Position: i + 1, Reason: ErrInvalidShareIndex}
+		}
+		if seen[s.Index] {
+			return nil, nil, &ShareError{Position: i + 1, Index: s.Index, Reason: ErrDuplicateShare}
+		}
+		seen[s.Index] = true
+		y, err := curve.decode(s.Value)
+		if err != nil {
+			return nil, nil, &ShareError{Position: i + 1, Index: s.Index, Reason: err}
+		}
+		xs[i], ys[i] = big.NewInt(int64(s.Index)), y
+	}
+	return xs, ys, nil
+}
+
+// RecoverScalar recovers a scalar split by SplitScalar
+func RecoverScalar(curve Curve, shares []ScalarShare, opts ...Option) ([]byte, error) {
+	additive, err := AdditiveShares(curve, shares, opts...)
+	if err != nil {
+		return nil, err
+	}
+	order := curve.Order()
+	secret, y := new(big.Int), new(big.Int)
+	defer eraseInt(secret)
+	defer eraseInt(y)
+	for _, a := range additive {
+		decoded, _ := curve.decode(a)
+		secret.Add(secret, y.Set(decoded))
+		eraseInt(decoded)
+		erase(a)
+	}
+	return curve.encode(secret.Mod(secret, order)), nil
+}
+
+// AdditiveShares turns the shares of the signers taking part into additive
+// shares: each is the share scaled by its Lagrange coefficient for the set,
+// and they sum to the scalar modulo the order, as threshold signing
+// protocols need.
+func AdditiveShares(curve Curve, shares []ScalarShare, opts ...Option) ([][]byte, error) {
+	xs, ys, err := scalarPoints(curve, shares, newConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	order := curve.Order()
+	weights, err := primeWeights(new(big.Int), xs, order)
+	if err != nil {
+		return nil, err
+	}
+	additive := make([][]byte, len(ys))
+	for i, y := range ys {
+		y.Mul(y, weights[i])
+		additive[i] = curve.encode(y.Mod(y, order))
+		eraseInt(y)
+	}
+	return additive, nil
+}
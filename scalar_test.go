@@ -0,0 +1,65 @@
+package tss
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestSplitScalar(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(nil)
+	for _, c := range []struct {
+		curve  Curve
+		scalar []byte
+	}{
+		{Ed25519, Ed25519Scalar(key)},
+		{Secp256k1, Secp256k1.encode(new(big.Int).SetBytes(randomBytes(31)))},
+		{P256, P256.encode(new(big.Int).SetBytes(randomBytes(31)))},
+	} {
+		shares, err := SplitScalar(c.curve, c.scalar, 5, 3, WithRandomIndexes())
+		if err != nil {
+			failNow(t, err)
+		}
+		subset := []ScalarShare{shares[3], shares[0], shares[4]}
+		recovered, err := RecoverScalar(c.curve, subset)
+		if err != nil {
+			failNow(t, err)
+		}
+		if !bytes.Equal(recovered, c.scalar) {
+			failNow(t, fmt.Errorf("%v: scalar mismatch", c.curve))
+		}
+		additive, err := AdditiveShares(c.curve, subset)
+		if err != nil {
+			failNow(t, err)
+		}
+		sum := new(big.Int)
+		for _, a := range additive {
+			x, err := c.curve.decode(a)
+			if err != nil {
+				failNow(t, err)
+			}
+			sum.Add(sum, x)
+		}
+		if !bytes.Equal(c.curve.encode(sum.Mod(sum, c.curve.Order())), c.scalar) {
+			failNow(t, fmt.Errorf("%v: additive shares do not sum to the scalar", c.curve))
+		}
+	}
+}
+
+func TestSplitScalarErrors(t *testing.T) {
+	if _, err := SplitScalar(Curve(9), randomBytes(32), 5, 3); err != ErrUnknownCurve {
+		failNow(t, expected(ErrUnknownCurve, err))
+	}
+	order := Secp256k1.Order()
+	if _, err := SplitScalar(Secp256k1, order.FillBytes(make([]byte, 32)), 5, 3); err != ErrInvalidScalar {
+		failNow(t, expected(ErrInvalidScalar, err))
+	}
+	shares, _ := SplitScalar(Secp256k1, Secp256k1.encode(big.NewInt(7)), 5, 3)
+	shares[1].Value = order.FillBytes(make([]byte, 32))
+	if _, err := RecoverScalar(Secp256k1, shares[:3]); !errors.Is(err, ErrInvalidScalar) {
+		failNow(t, expected(ErrInvalidScalar, err))
+	}
+}
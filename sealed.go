@@ -0,0 +1,133 @@
+package tss
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+)
+
+const (
+	// sealedMagic starts a share sealed to a recipient
+	sealedMagic = "TSSSEAL"
+	// sealedVersion is the version of the sealed shares
+	sealedVersion = 1
+	// sealedHeaderBytes is the size of the header of a sealed share: magic,
+	// version, index and the ephemeral X25519 public key
+	sealedHeaderBytes = len(sealedMagic) + 1 + 1 + 32
+	// sealedInfo is the HKDF info of the key of a sealed share
+	sealedInfo = "go-tss sealed share X25519"
+)
+
+var (
+	ErrInvalidRecipient = validationError("invalid recipient key")
+)
+
+// CreateSealedShares splits secret into one share per recipient and seals
+// share i to recipients[i], so shares can travel over untrusted channels.
+func CreateSealedShares(secret []byte, recipients []*ecdh.PublicKey, threshold int, opts ...Option) ([][]byte, error) {
+	shares, err := CreateShares(secret, len(recipients), threshold, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer eraseShares(shares)
+	return SealShares(shares, recipients, opts...)
+}
+
+// SealShares encrypts shares[i] to the X25519 public key recipients[i], with
+// an ephemeral key agreement and AES-256-GCM, so only the holder of the
+// matching private key can open it. The index stays in the clear.
+// The ephemeral keys always come from crypto/rand, crypto/ecdh ignoring any
+// other reader, so WithRand and WithDeterministic do not apply to them and
+// are refused in strict mode.
+func SealShares(shares ShareSet, recipients []*ecdh.PublicKey, opts ...Option) ([][]byte, error) {
+	if err := newConfig(opts).checkStrict(true); err != nil {
+		return nil, err
+	}
+	if len(shares) != len(recipients) {
+		return nil, ErrInvalidRecipient
+	}
+	sealed := make([][]byte, len(shares))
+	for i, share := range shares {
+		if !share.Valid() {
+			return nil, ErrInvalidShare
+		}
+		if recipients[i] == nil || recipients[i].Curve() != ecdh.X25519() {
+			return nil, ErrInvalidRecipient
+		}
+		ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		header := append([]byte(sealedMagic), sealedVersion, share[0])
+		header = append(header, ephemeral.PublicKey().Bytes()...)
+		aead, err := sealedAEAD(ephemeral, recipients[i], recipients[i], header)
+		if err != nil {
+			return nil, err
+		}
+		sealed[i] = aead.Seal(header, make([]byte, aead.NonceSize()), share[1:], header)
+	}
+	return sealed, nil
+}
+
+// sealedAEAD derives the AEAD of a sealed share from the key agreement of
+// private and public and the key of its recipient. The key is fresh for every
+// share, so the nonce is zero.
+func sealedAEAD(private *ecdh.PrivateKey, public *ecdh.PublicKey, recipient *ecdh.PublicKey, header []byte) (cipher.AEAD, error) {
+	shared, err := private.ECDH(public)
+	if err != nil {
+		return nil, ErrInvalidRecipient
+	}
+	defer erase(shared)
+	salt := append(append([]byte{}, header[len(sealedMagic)+2:]...), recipient.Bytes()...)
+	key, err := hkdf.Key(sha256.New, shared, salt, sealedInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	defer erase(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// SealedShareIndex returns the index of a share sealed by SealShares
+func SealedShareIndex(sealed []byte) (byte, error) {
+	if len(sealed) < sealedHeaderBytes || !bytes.HasPrefix(sealed, []byte(sealedMagic)) {
+		return 0, ErrInvalidCiphertext
+	}
+	if sealed[len(sealedMagic)] != sealedVersion {
+		return 0, ErrUnsupportedVersion
+	}
+	return sealed[len(sealedMagic)+1], nil
+}
+
+// OpenShare decrypts a share sealed by SealShares with the private key of
+// its recipient. A share sealed to another key gives ErrDecryption.
+func OpenShare(sealed []byte, key *ecdh.PrivateKey) (Share, error) {
+	index, err := SealedShareIndex(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || key.Curve() != ecdh.X25519() {
+		return nil, ErrInvalidRecipient
+	}
+	header := sealed[:sealedHeaderBytes]
+	ephemeral, err := ecdh.X25519().NewPublicKey(header[len(sealedMagic)+2:])
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	aead, err := sealedAEAD(key, ephemeral, key.PublicKey(), header)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	share, err := aead.Open(Share{index}, make([]byte, aead.NonceSize()), sealed[sealedHeaderBytes:], header)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	return share, nil
+}
@@ -0,0 +1,49 @@
+package tss
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"testing"
+)
+
+func TestSealedShares(t *testing.T) {
+	secret := randomBytes(32)
+	keys := make([]*ecdh.PrivateKey, 4)
+	recipients := make([]*ecdh.PublicKey, len(keys))
+	for i := range keys {
+		keys[i], _ = ecdh.X25519().GenerateKey(rand.Reader)
+		recipients[i] = keys[i].PublicKey()
+	}
+	sealed, err := CreateSealedShares(secret, recipients, 3, WithConsumeSecret(false))
+	if err != nil {
+		failNow(t, err)
+	}
+	shares := make(ShareSet, 0, 3)
+	for i := 1; i < 4; i++ {
+		share, err := OpenShare(sealed[i], keys[i])
+		if err != nil {
+			failNow(t, err)
+		}
+		if index, _ := SealedShareIndex(sealed[i]); index != share[0] {
+			failNow(t, ErrIndexMismatch)
+		}
+		shares = append(shares, share)
+	}
+	testRecover(t, secret, shares)
+	if _, err := OpenShare(sealed[0], keys[1]); err != ErrDecryption {
+		failNow(t, expected(ErrDecryption, err))
+	}
+	sealed[0][sealedHeaderBytes] ^= 1
+	if _, err := OpenShare(sealed[0], keys[0]); err != ErrDecryption {
+		failNow(t, expected(ErrDecryption, err))
+	}
+	if _, err := SealShares(shares, recipients); err != ErrInvalidRecipient {
+		failNow(t, expected(ErrInvalidRecipient, err))
+	}
+	if _, err := SealShares(shares, recipients[1:], WithRand(rand.Reader), WithStrictMode()); err != ErrNotAllowed {
+		failNow(t, expected(ErrNotAllowed, err))
+	}
+	if _, err := SealShares(shares, recipients[1:], WithStrictMode()); err != nil {
+		failNow(t, err)
+	}
+}
@@ -0,0 +1,51 @@
+package tss
+
+import "context"
+
+// Secret holds a recovered secret under caller control: Bytes exposes it and
+// Destroy wipes it. It prints redacted, so logging it by accident does not
+// leak it.
+type Secret struct {
+	b    []byte
+	free func([]byte)
+}
+
+// NewSecret returns a Secret holding a copy of b
+func NewSecret(b []byte) *Secret {
+	return &Secret{b: append(make([]byte, 0, len(b)), b...)}
+}
+
+// Bytes returns the secret, nil once destroyed. The returned slice aliases
+// the Secret and is wiped by Destroy, do not keep it longer.
+func (s *Secret) Bytes() []byte {
+	return s.b
+}
+
+// Len returns the size of the secret, 0 once destroyed
+func (s *Secret) Len() int {
+	return len(s.b)
+}
+
+// Destroy wipes the secret, it is safe to call more than once
+func (s *Secret) Destroy() {
+	if s.free != nil && s.b != nil {
+		s.free(s.b)
+	} else {
+		erase(s.b)
+	}
+	s.b = nil
+}
+
+// Recover is RecoverSecret returning a *Secret, call Destroy on it as soon as
+// the secret is no longer needed
+func Recover(shares ShareSet, opts ...Option) (*Secret, error) {
+	b, err := RecoverSecretContext(context.Background(), shares, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cfg := newConfig(opts)
+	if cfg.allocator != nil {
+		return &Secret{b: b, free: cfg.allocator.Free}, nil
+	}
+	return &Secret{b: b}, nil
+}
@@ -0,0 +1,36 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestSecretDestroy(t *testing.T) {
+	secret := randomBytes(32)
+	shares, _ := CreateShares(secret, 3, 2)
+	s, err := Recover(shares[1:])
+	if err != nil {
+		failNow(t, err)
+	}
+	b := s.Bytes()
+	if !bytes.Equal(b, secret) || s.Len() != 32 {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+	s.Destroy()
+	s.Destroy()
+	if s.Bytes() != nil || s.Len() != 0 || bytes.Equal(b, secret) {
+		failNow(t, fmt.Errorf("secret not wiped"))
+	}
+	if got := fmt.Sprint(s); got != "tss.Secret{destroyed}" {
+		failNow(t, fmt.Errorf("unexpected format %s", got))
+	}
+	if _, err := Recover(shares[:1]); err != ErrTooFewShares {
+		failNow(t, expected(ErrTooFewShares, err))
+	}
+	kept := append([]byte{}, secret...)
+	NewSecret(secret).Destroy()
+	if !bytes.Equal(secret, kept) {
+		failNow(t, fmt.Errorf("NewSecret does not copy"))
+	}
+}
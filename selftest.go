@@ -0,0 +1,134 @@
+package tss
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"sync"
+)
+
+var (
+	ErrSelfTest   = integrityError("self-test failed")
+	ErrNotAllowed = validationError("option not allowed in strict mode")
+)
+
+// selfTest caches the outcome of SelfTest, the tests run once per process
+var selfTest struct {
+	once sync.Once
+	err  error
+}
+
+// SelfTest runs the known answer tests of recovery and of deterministic
+// splitting, checks the field arithmetic, vector kernels included, against
+// its definition and that crypto/rand delivers. The tests run once, later
+// calls return the first outcome. It returns nil or ErrSelfTest.
+func SelfTest() error {
+	selfTest.once.Do(func() {
+		if !selfTestKAT() || !selfTestField() || !selfTestRand() {
+			selfTest.err = ErrSelfTest
+		}
+	})
+	return selfTest.err
+}
+
+// WithStrictMode makes splitting and recovery fail closed, for regulated
+// environments: they run SelfTest first and refuse to proceed when it fails,
+// and splitting refuses the non approved sources of WithRand and
+// WithDeterministic with ErrNotAllowed.
+func WithStrictMode() Option {
+	return func(c *config) {
+		c.strict = true
+	}
+}
+
+// checkStrict returns the error refusing an operation in strict mode
+func (c *config) checkStrict(split bool) error {
+	if !c.strict {
+		return nil
+	}
+	if split && (c.rand != nil || c.deterministic) {
+		return ErrNotAllowed
+	}
+	return SelfTest()
+}
+
+// Known answer vectors: shares 1 and 2 of katSecret, and the shares of
+// katSecret split 3 ways with threshold 2 with WithDeterministic(katSalt,
+// katInfo)
+const (
+	katSecret = "a217525ab5cab096e455acba00a4032c0cc1a1ef7ccd280642d994cdee7694ca"
+	katShare1 = "016fbb11a9264bdf4188e3911827ea30e27a283cbea177d7c421524fb448bbfedc"
+	katShare2 = "022354d4a788d36e233c22d6e54e3865abe008804ddda2cd9984d4393fb9f740e6"
+	katSalt   = "go-tss self-test"
+	katInfo   = "kat"
+)
+
+var katDeterministic = [...]string{
+	"017c84ed8d2fd81e7b2c4767bae5e37ed52006441773b042df9e5fde4bc8fd13b1",
+	"02052a37ef9aeef7576f7121bad12af9c5545470046237fcafe1ce00daa27b813c",
+	"03dbb9883800fc59baa763eaba346d843c789395fc6d4a96763d484a5c84f00647",
+}
+
+func selfTestKAT() bool {
+	secret, _ := hex.DecodeString(katSecret)
+	share1, _ := hex.DecodeString(katShare1)
+	share2, _ := hex.DecodeString(katShare2)
+	recovered, err := RecoverSecret(ShareSet{share1, share2})
+	if err != nil || !bytes.Equal(recovered, secret) {
+		return false
+	}
+	shares, err := CreateShares(secret, len(katDeterministic), 2, WithDeterministic([]byte(katSalt), katInfo), WithConsumeSecret(false))
+	if err != nil {
+		return false
+	}
+	for i, s := range shares {
+		if hex.EncodeToString(s) != katDeterministic[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// selfTestField checks every product against the shift and add definition,
+// every quotient against its product, and the vector kernel against the
+// scalar code
+func selfTestField() bool {
+	for x := 0; x < 256; x++ {
+		for y := 0; y < 256; y++ {
+			p := mul(byte(x), byte(y))
+			if p != ctMul(byte(x), byte(y)) {
+				return false
+			}
+			if y != 0 && div(p, byte(y)) != byte(x) {
+				return false
+			}
+		}
+	}
+	in := make([]byte, 96)
+	for i := range in {
+		in[i] = byte(i*37 + 11)
+	}
+	for _, c := range []byte{0x02, 0x53, 0xca, 0xff} {
+		out := make([]byte, len(in))
+		mulAddSlice(c, in, out)
+		for i, b := range in {
+			if out[i] != ctMul(c, b) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// selfTestRand checks crypto/rand delivers, and not a stuck output
+func selfTestRand() bool {
+	var a, b [32]byte
+	if _, err := io.ReadFull(rand.Reader, a[:]); err != nil {
+		return false
+	}
+	if _, err := io.ReadFull(rand.Reader, b[:]); err != nil {
+		return false
+	}
+	return a != b && !allZero(a[:])
+}
@@ -0,0 +1,11 @@
+//go:build tss_selftest
+
+package tss
+
+// Built with -tags tss_selftest, the self-test runs when the package is
+// loaded and a failure stops the program before any secret is handled.
+func init() {
+	if err := SelfTest(); err != nil {
+		panic("tss: " + err.Error())
+	}
+}
@@ -0,0 +1,36 @@
+package tss
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSelfTest(t *testing.T) {
+	if err := SelfTest(); err != nil {
+		failNow(t, err)
+	}
+	if !selfTestKAT() || !selfTestField() || !selfTestRand() {
+		failNow(t, ErrSelfTest)
+	}
+}
+
+func TestStrictMode(t *testing.T) {
+	secret := randomBytes(32)
+	shares, err := CreateShares(secret, 3, 2, WithStrictMode(), WithConsumeSecret(false))
+	if err != nil {
+		failNow(t, err)
+	}
+	recovered, err := RecoverSecret(shares[1:], WithStrictMode())
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, ErrSelfTest)
+	}
+	if _, err := CreateShares(secret, 3, 2, WithStrictMode(), WithRand(bytes.NewReader(make([]byte, 1024)))); err != ErrNotAllowed {
+		failNow(t, expected(ErrNotAllowed, err))
+	}
+	if _, err := CreateShares(secret, 3, 2, WithStrictMode(), WithDeterministic(nil, "")); err != ErrNotAllowed {
+		failNow(t, expected(ErrNotAllowed, err))
+	}
+}
@@ -0,0 +1,201 @@
+package tss
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// NewShare builds a share from its index and value, the value is copied
+func NewShare(index byte, value []byte) Share {
+	s := make(Share, 1+len(value))
+	s[0] = index
+	copy(s[1:], value)
+	return s
+}
+
+// Index returns the x coordinate of the share, 0 for an empty share
+func (s Share) Index() byte {
+	if len(s) == 0 {
+		return 0
+	}
+	return s[0]
+}
+
+// Value returns the share bytes, one per secret byte. The returned slice
+// aliases the share.
+func (s Share) Value() []byte {
+	if len(s) == 0 {
+		return nil
+	}
+	return s[1:]
+}
+
+// SecretBytes returns the size of the secret the share recovers to
+func (s Share) SecretBytes() int {
+	return len(s.Value())
+}
+
+// Valid reports whether the share is structurally sound: a non zero index
+// and a value within the secret size limits
+func (s Share) Valid() bool {
+	return len(s) >= MinShareBytes && len(s) <= MaxShareBytes && s[0] != 0
+}
+
+// Indexes returns the index of every share of the set
+func (ss ShareSet) Indexes() []byte {
+	indexes := make([]byte, len(ss))
+	for i, s := range ss {
+		indexes[i] = s.Index()
+	}
+	return indexes
+}
+
+// Subset returns the shares at the given positions of the set, in that
+// order. The shares are not copied.
+func (ss ShareSet) Subset(positions ...int) ShareSet {
+	subset := make(ShareSet, len(positions))
+	for i, pos := range positions {
+		subset[i] = ss[pos]
+	}
+	return subset
+}
+
+// Shuffle puts the shares of the set in random order, drawing from random or
+// from crypto/rand when random is nil
+func (ss ShareSet) Shuffle(random io.Reader) error {
+	if random == nil {
+		random = rand.Reader
+	}
+	var r [2]byte
+	for i := len(ss) - 1; i > 0; i-- {
+		if _, err := io.ReadFull(random, r[:]); err != nil {
+			return err
+		}
+		// modulo bias is negligible for i < 255 with 16 random bits
+		j := int(uint16(r[0])<<8|uint16(r[1])) % (i + 1)
+		ss[i], ss[j] = ss[j], ss[i]
+	}
+	return nil
+}
+
+// SortByIndex sorts the shares of the set by increasing index
+func (ss ShareSet) SortByIndex() {
+	sort.SliceStable(ss, func(i, j int) bool {
+		return ss[i].Index() < ss[j].Index()
+	})
+}
+
+// Dedup returns the set without the shares equal to an earlier one, reusing
+// its storage. Different shares with the same index are all kept, recovery
+// reports them as duplicates.
+func (ss ShareSet) Dedup() ShareSet {
+	out := ss[:0]
+next:
+	for _, s := range ss {
+		for _, kept := range out {
+			if bytes.Equal(kept, s) {
+				continue next
+			}
+		}
+		out = append(out, s)
+	}
+	for i := len(out); i < len(ss); i++ {
+		ss[i] = nil
+	}
+	return out
+}
+
+// ShareSetError gathers every problem found by ShareSet.Validate, errors.Is
+// and errors.As look through all of them
+type ShareSetError struct {
+	Errors []error
+}
+
+func (e *ShareSetError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d invalid share set problems: %s", len(msgs), strings.Join(msgs, "; "))
+}
+
+func (e *ShareSetError) Unwrap() []error {
+	return e.Errors
+}
+
+// Validate runs the structural checks of recovery on the whole set without
+// stopping at the first problem: share count, sizes, zero and duplicate
+// indexes. It returns nil or a *ShareSetError listing every problem, problems
+// of a single share being reported as a *ShareError.
+func (ss ShareSet) Validate(opts ...Option) error {
+	return ss.ValidateWithParams(nil, opts...)
+}
+
+// ValidateWithParams is Validate for shares decoded along their params,
+// params[i] being the params of ss[i]. It also reports shares of different
+// splits as a *MixedSharesError and too few shares for the recorded threshold
+// as a *ThresholdError. With WithDealerKey it reports shares without a valid
+// dealer signature.
+func (ss ShareSet) ValidateWithParams(params []ShareParams, opts ...Option) error {
+	cfg := newConfig(opts)
+	var errs []error
+	if params != nil {
+		if len(params) != len(ss) {
+			return &ShareSetError{Errors: []error{ErrInvalidParams}}
+		}
+		ids := make([][]byte, len(params))
+		for i, p := range params {
+			if err := p.validate(); err != nil {
+				errs = append(errs, &ShareError{Position: i + 1, Index: ss[i].Index(), Reason: err})
+			} else if cfg.dealerKey != nil {
+				if err := VerifyShareSignature(ss[i], p, cfg.dealerKey); err != nil {
+					errs = append(errs, &ShareError{Position: i + 1, Index: ss[i].Index(), Reason: err})
+				}
+			}
+			ids[i] = p.Identifier
+		}
+		if err := mixedShares(ids...); err != nil {
+			errs = append(errs, err)
+		} else if len(params) > 0 && params[0].Threshold > len(ss) {
+			errs = append(errs, &ThresholdError{Need: params[0].Threshold, Have: len(ss)})
+		}
+	} else if cfg.dealerKey != nil {
+		errs = append(errs, ErrUnsignedShare)
+	}
+	if len(ss) < cfg.threshold {
+		errs = append(errs, &ThresholdError{Need: cfg.threshold, Have: len(ss)})
+	} else if len(ss) < cfg.minThreshold() {
+		errs = append(errs, ErrTooFewShares)
+	}
+	if len(ss) > cfg.sharesLimit() {
+		errs = append(errs, ErrTooManyShares)
+	}
+	var seen [256]bool
+	size := 0
+	for i, s := range ss {
+		switch {
+		case len(s) < cfg.minSecretBytes+1 || len(s) > cfg.secretLimit()+1:
+			errs = append(errs, &ShareError{Position: i + 1, Index: s.Index(), Reason: ErrShareSize})
+			continue
+		case size == 0:
+			size = len(s)
+		case len(s) != size:
+			errs = append(errs, &ShareError{Position: i + 1, Index: s[0], Reason: ErrShareSize})
+		}
+		switch {
+		case s[0] == 0:
+			errs = append(errs, &ShareError{Position: i + 1, Reason: ErrInvalidShareIndex})
+		case seen[s[0]]:
+			errs = append(errs, &ShareError{Position: i + 1, Index: s[0], Reason: ErrDuplicateShare})
+		}
+		seen[s[0]] = true
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ShareSetError{Errors: errs}
+}
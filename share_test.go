@@ -0,0 +1,86 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestShareAccessors(t *testing.T) {
+	shares, _ := CreateShares(randomBytes(20), 3, 2)
+	for i, s := range shares {
+		if s.Index() != byte(i+1) || s.SecretBytes() != 20 || !s.Valid() {
+			failNow(t, fmt.Errorf("unexpected accessors for share %d", i))
+		}
+		if !bytes.Equal(NewShare(s.Index(), s.Value()), s) {
+			failNow(t, fmt.Errorf("NewShare mismatch for share %d", i))
+		}
+	}
+	if !bytes.Equal(shares.Indexes(), []byte{1, 2, 3}) {
+		failNow(t, fmt.Errorf("indexes %v", shares.Indexes()))
+	}
+	var empty Share
+	if empty.Index() != 0 || empty.Value() != nil || empty.Valid() || NewShare(0, []byte{1}).Valid() {
+		failNow(t, fmt.Errorf("unexpected accessors for invalid shares"))
+	}
+}
+
+func TestShareSetValidate(t *testing.T) {
+	shares, _ := CreateShares(randomBytes(20), 5, 3)
+	if err := shares.Validate(); err != nil {
+		failNow(t, err)
+	}
+	bad := ShareSet{shares[0], shares[1][:10], NewShare(0, shares[2][1:]), shares[0]}
+	err := bad.Validate(WithThreshold(5))
+	var se *ShareSetError
+	if !errors.As(err, &se) || len(se.Errors) != 4 {
+		failNow(t, fmt.Errorf("unexpected error %v", err))
+	}
+	for _, target := range []error{ErrThresholdNotMet, ErrShareSize, ErrInvalidShareIndex, ErrDuplicateShare} {
+		if !errors.Is(err, target) {
+			failNow(t, expected(target, err))
+		}
+	}
+
+	params := []ShareParams{{Identifier: []byte("a"), Threshold: 3}, {Identifier: []byte("b"), Threshold: 3}}
+	err = shares[:2].ValidateWithParams(params)
+	var mixed *MixedSharesError
+	if !errors.As(err, &mixed) || len(mixed.Identifiers) != 2 {
+		failNow(t, expected(ErrMixedShares, err))
+	}
+	params[1].Identifier = []byte("a")
+	if err := shares[:2].ValidateWithParams(params); !errors.Is(err, ErrThresholdNotMet) {
+		failNow(t, expected(ErrThresholdNotMet, err))
+	}
+	if err := shares[:2].ValidateWithParams(params[:1]); !errors.Is(err, ErrInvalidParams) {
+		failNow(t, expected(ErrInvalidParams, err))
+	}
+}
+
+func TestShareSetUtilities(t *testing.T) {
+	secret := randomBytes(20)
+	shares, _ := CreateShares(secret, 5, 3)
+	subset := shares.Subset(4, 0, 2)
+	if !bytes.Equal(subset.Indexes(), []byte{5, 1, 3}) {
+		failNow(t, fmt.Errorf("subset indexes %v", subset.Indexes()))
+	}
+	subset.SortByIndex()
+	if !bytes.Equal(subset.Indexes(), []byte{1, 3, 5}) {
+		failNow(t, fmt.Errorf("sorted indexes %v", subset.Indexes()))
+	}
+	shuffled := append(ShareSet{}, shares...)
+	if err := shuffled.Shuffle(nil); err != nil {
+		failNow(t, err)
+	}
+	shuffled.SortByIndex()
+	if !bytes.Equal(shuffled.Indexes(), shares.Indexes()) {
+		failNow(t, fmt.Errorf("shuffle lost shares %v", shuffled.Indexes()))
+	}
+	conflicting := NewShare(2, randomBytes(20))
+	deduped := ShareSet{shares[0], shares[1], append(Share{}, shares[0]...), conflicting, shares[1]}.Dedup()
+	if len(deduped) != 3 || !bytes.Equal(deduped[2], conflicting) {
+		failNow(t, fmt.Errorf("dedup kept %v", deduped.Indexes()))
+	}
+	testRecover(t, secret, ShareSet{shares[0], shares[0], shares[3], shares[4]}.Dedup())
+}
@@ -0,0 +1,88 @@
+package tss
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+)
+
+// signatureDomain starts the message signed by SignShare
+const signatureDomain = "go-tss dealer signature v1"
+
+var (
+	ErrUnsignedShare    = integrityError("share not signed by the dealer")
+	ErrInvalidSignature = integrityError("invalid dealer signature")
+)
+
+// WithDealerKey makes the Combiner and ShareSet.ValidateWithParams require
+// shares signed with SignShare by the private key of key, so shares forged or
+// dealt again by someone else are rejected.
+func WithDealerKey(key ed25519.PublicKey) Option {
+	return func(c *config) {
+		if len(key) == ed25519.PublicKeySize {
+			c.dealerKey = key
+		}
+	}
+}
+
+// SignShare signs the header of share, its identifier, index, threshold,
+// extensions and the hash of the share, with the Ed25519 key of the dealer.
+// It returns params with the signature added as an ExtDealerSignature
+// extension, replacing any previous one, ready for MarshalContainer.
+func SignShare(share Share, params ShareParams, key ed25519.PrivateKey) (ShareParams, error) {
+	if !share.Valid() {
+		return ShareParams{}, ErrInvalidShare
+	}
+	if len(key) != ed25519.PrivateKeySize {
+		return ShareParams{}, ErrInvalidParams
+	}
+	params.Extensions = unsignedExtensions(params.Extensions)
+	if err := params.validate(); err != nil {
+		return ShareParams{}, err
+	}
+	signature := ed25519.Sign(key, signedHeader(share, params))
+	params.Extensions = append(params.Extensions, Extension{Type: ExtDealerSignature, Value: signature})
+	if err := params.validate(); err != nil {
+		return ShareParams{}, err
+	}
+	return params, nil
+}
+
+// VerifyShareSignature checks the dealer signature SignShare added to params
+func VerifyShareSignature(share Share, params ShareParams, key ed25519.PublicKey) error {
+	signature, ok := params.Extension(ExtDealerSignature)
+	if !ok {
+		return ErrUnsignedShare
+	}
+	if !share.Valid() {
+		return ErrInvalidShare
+	}
+	if len(key) != ed25519.PublicKeySize || len(signature) != ed25519.SignatureSize {
+		return ErrInvalidSignature
+	}
+	params.Extensions = unsignedExtensions(params.Extensions)
+	if !ed25519.Verify(key, signedHeader(share, params), signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// signedHeader returns the message signed by the dealer for share
+func signedHeader(share Share, params ShareParams) []byte {
+	digest := sha256.Sum256(share)
+	msg := append([]byte(signatureDomain), byte(len(params.Identifier)))
+	msg = append(msg, params.Identifier...)
+	msg = append(msg, share[0], byte(params.Threshold))
+	msg = append(msg, digest[:]...)
+	return appendExtensions(msg, params.Extensions)
+}
+
+// unsignedExtensions returns exts without the dealer signatures
+func unsignedExtensions(exts []Extension) []Extension {
+	out := make([]Extension, 0, len(exts))
+	for _, e := range exts {
+		if e.Type != ExtDealerSignature {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package tss
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestSignShare(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	secret := randomBytes(32)
+	shares, _ := CreateShares(secret, 4, 3, WithConsumeSecret(false))
+	params := make([]ShareParams, len(shares))
+	for i, s := range shares {
+		var err error
+		base := ShareParams{Identifier: []byte("vault"), Threshold: 3, Extensions: []Extension{{Type: ExtCustodian, Value: []byte("alice")}}}
+		if params[i], err = SignShare(s, base, private); err != nil {
+			failNow(t, err)
+		}
+	}
+	c := NewCombiner(WithDealerKey(public))
+	for i := 0; i < 3; i++ {
+		data, _ := MarshalContainer(shares[i], params[i])
+		share, p, err := UnmarshalContainer(data)
+		if err != nil {
+			failNow(t, err)
+		}
+		if err := c.AddShareWithParams(share, p); err != nil {
+			failNow(t, err)
+		}
+	}
+	recovered, err := c.Recover()
+	if err != nil {
+		failNow(t, err)
+	}
+	testRecover(t, recovered, shares[:3])
+	if err := shares.ValidateWithParams(params, WithDealerKey(public)); err != nil {
+		failNow(t, err)
+	}
+
+	forged := params[3]
+	forged.Threshold = 2
+	if err := VerifyShareSignature(shares[3], forged, public); err != ErrInvalidSignature {
+		failNow(t, expected(ErrInvalidSignature, err))
+	}
+	other, _, _ := ed25519.GenerateKey(rand.Reader)
+	if err := VerifyShareSignature(shares[3], params[3], other); err != ErrInvalidSignature {
+		failNow(t, expected(ErrInvalidSignature, err))
+	}
+	c.Reset()
+	if err := c.AddShare(shares[3]); !errors.Is(err, ErrUnsignedShare) {
+		failNow(t, expected(ErrUnsignedShare, err))
+	}
+	tampered := append(Share{}, shares[3]...)
+	tampered[1] ^= 1
+	if err := c.AddShareWithParams(tampered, params[3]); !errors.Is(err, ErrInvalidSignature) {
+		failNow(t, expected(ErrInvalidSignature, err))
+	}
+}
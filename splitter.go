@@ -0,0 +1,157 @@
+package tss
+
+import "bytes"
+
+// Splitter splits secrets with a fixed set of options, so callers configure
+// format, limits and the like once instead of on every call
+type Splitter struct {
+	opts []Option
+}
+
+// NewSplitter creates a Splitter applying opts to every split
+func NewSplitter(opts ...Option) *Splitter {
+	return &Splitter{opts: append([]Option{}, opts...)}
+}
+
+// Split is CreateShares with the options of the Splitter
+func (s *Splitter) Split(secret []byte, sharesCount int, threshold int) (ShareSet, error) {
+	return CreateShares(secret, sharesCount, threshold, s.opts...)
+}
+
+// SplitChunked is CreateChunkedShares with the options of the Splitter
+func (s *Splitter) SplitChunked(secret []byte, sharesCount int, threshold int) ([]ChunkedShare, error) {
+	return CreateChunkedShares(secret, sharesCount, threshold, s.opts...)
+}
+
+// Combiner recovers secrets with a fixed set of options, either from a
+// whole share set with Combine or from shares fed one at a time with AddShare
+type Combiner struct {
+	opts      []Option
+	cfg       *config
+	shares    ShareSet
+	params    *ShareParams
+	threshold int
+}
+
+// NewCombiner creates a Combiner applying opts to every recovery
+func NewCombiner(opts ...Option) *Combiner {
+	c := &Combiner{opts: append([]Option{}, opts...)}
+	c.cfg = newConfig(c.opts)
+	c.threshold = c.cfg.threshold
+	return c
+}
+
+// Combine is RecoverSecret with the options of the Combiner
+func (c *Combiner) Combine(shares ShareSet) ([]byte, error) {
+	return RecoverSecret(shares, c.opts...)
+}
+
+// CombineChunked is RecoverChunkedSecret with the options of the Combiner
+func (c *Combiner) CombineChunked(shares []ChunkedShare) ([]byte, error) {
+	return RecoverChunkedSecret(shares, c.opts...)
+}
+
+// AddShare validates a share against the shares added so far and keeps it.
+// Adding the same share twice is harmless, a different share with the index
+// of a share already added is reported as a *ShareError.
+func (c *Combiner) AddShare(share Share) error {
+	if c.cfg.dealerKey != nil {
+		return &ShareError{Position: len(c.shares) + 1, Index: share.Index(), Reason: ErrUnsignedShare}
+	}
+	return c.addShare(share)
+}
+
+func (c *Combiner) addShare(share Share) error {
+	pos := len(c.shares) + 1
+	if len(share) < c.cfg.minSecretBytes+1 || len(share) > c.cfg.secretLimit()+1 {
+		return &ShareError{Position: pos, Index: share.Index(), Reason: ErrShareSize}
+	}
+	if share[0] == 0 {
+		return &ShareError{Position: pos, Reason: ErrInvalidShareIndex}
+	}
+	if len(c.shares) > 0 && len(share) != len(c.shares[0]) {
+		return &ShareError{Position: pos, Index: share[0], Reason: ErrShareSize}
+	}
+	for _, s := range c.shares {
+		if s[0] == share[0] {
+			if bytes.Equal(s, share) {
+				return nil
+			}
+			return &ShareError{Position: pos, Index: share[0], Reason: ErrDuplicateShare}
+		}
+	}
+	if len(c.shares) >= c.cfg.sharesLimit() {
+		return ErrTooManyShares
+	}
+	c.shares = append(c.shares, append(Share{}, share...))
+	return nil
+}
+
+// AddShareWithParams is AddShare for a share decoded along its params, such
+// as from a container. The params must match the ones of the shares added
+// before and set the threshold CanRecover waits for, a share of another split
+// is reported as a *MixedSharesError. With WithDealerKey the dealer signature
+// of the share is checked first.
+func (c *Combiner) AddShareWithParams(share Share, params ShareParams) error {
+	if err := params.validate(); err != nil {
+		return err
+	}
+	if c.cfg.dealerKey != nil {
+		if err := VerifyShareSignature(share, params, c.cfg.dealerKey); err != nil {
+			return &ShareError{Position: len(c.shares) + 1, Index: share.Index(), Reason: err}
+		}
+	}
+	if c.params != nil {
+		if err := mixedShares(c.params.Identifier, params.Identifier); err != nil {
+			return err
+		}
+		if c.params.Threshold != params.Threshold {
+			return ErrInvalidParams
+		}
+	}
+	if err := c.addShare(share); err != nil {
+		return err
+	}
+	if c.params == nil {
+		c.params = &params
+		c.threshold = params.Threshold
+	}
+	return nil
+}
+
+// Len returns the number of shares added so far
+func (c *Combiner) Len() int {
+	return len(c.shares)
+}
+
+// CanRecover reports whether enough shares were added to recover the secret.
+// The threshold comes from WithThreshold or from the params of the shares;
+// when it is unknown CanRecover only tells whether recovery can be attempted.
+func (c *Combiner) CanRecover() bool {
+	need := c.threshold
+	if need < c.cfg.minThreshold() {
+		need = c.cfg.minThreshold()
+	}
+	return len(c.shares) >= need
+}
+
+// Recover reconstructs the secret from the shares added so far
+func (c *Combiner) Recover() ([]byte, error) {
+	if c.threshold > 0 && len(c.shares) < c.threshold {
+		return nil, &ThresholdError{Need: c.threshold, Have: len(c.shares)}
+	}
+	if !c.CanRecover() {
+		return nil, ErrTooFewShares
+	}
+	return RecoverSecret(c.shares, c.opts...)
+}
+
+// Reset forgets the shares added so far
+func (c *Combiner) Reset() {
+	for _, s := range c.shares {
+		erase(s)
+	}
+	c.shares = nil
+	c.params = nil
+	c.threshold = c.cfg.threshold
+}
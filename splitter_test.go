@@ -0,0 +1,103 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSplitterCombiner(t *testing.T) {
+	splitter := NewSplitter(WithMinSecretBytes(16), WithMaxShares(10))
+	combiner := NewCombiner(WithMaxShares(3))
+	secret := randomBytes(16)
+	shares, err := splitter.Split(secret, 10, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	recovered, err := combiner.Combine(shares[2:5])
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+	if _, err := combiner.Combine(shares[:4]); err != ErrTooManyShares {
+		failNow(t, expected(ErrTooManyShares, err))
+	}
+	if _, err := splitter.Split(randomBytes(8), 5, 3); err != ErrSecretTooShort {
+		failNow(t, expected(ErrSecretTooShort, err))
+	}
+	chunked, err := splitter.SplitChunked(secret, 3, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	if recovered, err = combiner.CombineChunked(chunked[1:]); err != nil || !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("chunked recovery failed: %v", err))
+	}
+}
+
+func TestCombinerIncremental(t *testing.T) {
+	secret := randomBytes(32)
+	shares, _ := CreateShares(secret, 5, 3)
+	c := NewCombiner(WithThreshold(3))
+	for i, share := range shares[1:4] {
+		if c.CanRecover() {
+			failNow(t, fmt.Errorf("can recover with %d shares", i))
+		}
+		if err := c.AddShare(share); err != nil {
+			failNow(t, err)
+		}
+		// repeated scans are ignored
+		if err := c.AddShare(share); err != nil {
+			failNow(t, err)
+		}
+	}
+	if !c.CanRecover() || c.Len() != 3 {
+		failNow(t, fmt.Errorf("cannot recover with %d shares", c.Len()))
+	}
+	recovered, err := c.Recover()
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+	forged := append(Share{}, shares[1]...)
+	forged[1] ^= 1
+	if err := c.AddShare(forged); !errors.Is(err, ErrDuplicateShare) {
+		failNow(t, expected(ErrDuplicateShare, err))
+	}
+	if err := c.AddShare(shares[4][:10]); !errors.Is(err, ErrShareSize) {
+		failNow(t, expected(ErrInvalidShare, err))
+	}
+	c.Reset()
+	if c.Len() != 0 || c.CanRecover() {
+		failNow(t, fmt.Errorf("not reset"))
+	}
+}
+
+func TestCombinerParams(t *testing.T) {
+	secret := randomBytes(32)
+	shares, _ := CreateShares(secret, 5, 4)
+	params := ShareParams{Identifier: []byte("id"), Threshold: 4}
+	c := NewCombiner()
+	for _, share := range shares[:3] {
+		if err := c.AddShareWithParams(share, params); err != nil {
+			failNow(t, err)
+		}
+	}
+	if c.CanRecover() {
+		failNow(t, fmt.Errorf("can recover below the threshold of the params"))
+	}
+	if err := c.AddShareWithParams(shares[3], ShareParams{Identifier: []byte("other"), Threshold: 4}); !errors.Is(err, ErrMixedShares) {
+		failNow(t, expected(ErrMixedShares, err))
+	}
+	if err := c.AddShareWithParams(shares[3], params); err != nil {
+		failNow(t, err)
+	}
+	recovered, err := c.Recover()
+	if err != nil || !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("recovery failed: %v", err))
+	}
+}
@@ -0,0 +1,335 @@
+package tss
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// The ssss(1) tool of B. Poettering splits a secret of n bytes as a single
+// element of GF(2^(8n)), with the pentanomials of ssssIrreducible, and a monic
+// polynomial: the coefficient of x^threshold is 1. The secret goes through an
+// XTEA based diffusion layer first when it is 8 bytes or more. Shares are
+// written one per line as [token-]index-hex.
+
+// MaxSSSSBytes is the largest secret ssss splits
+const MaxSSSSBytes = 128
+
+var (
+	ErrInvalidSSSSShare = validationError("invalid ssss share")
+)
+
+// WithSSSSDiffusion turns the diffusion layer of SplitSSSS and CombineSSSS on
+// or off, on by default as in ssss; shares made with ssss-split -D need it
+// off.
+func WithSSSSDiffusion(enabled bool) Option {
+	return func(c *config) {
+		c.ssssPlain = !enabled
+	}
+}
+
+// ssssIrreducible holds the three middle exponents of the pentanomial
+// x^d + x^a + x^b + x^c + 1 of each degree d = 8, 16, ..., 1024
+var ssssIrreducible = [...]byte{
+	4, 3, 1, 5, 3, 1, 4, 3, 1, 7, 3, 2, 5, 4, 3, 5, 3, 2, 7, 4, 2, 4, 3, 1, 10, 9, 3, 9, 4, 2, 7, 6, 2, 10, 9,
+	6, 4, 3, 1, 5, 4, 3, 4, 3, 1, 7, 2, 1, 5, 3, 2, 7, 4, 2, 6, 3, 2, 5, 3, 2, 15, 3, 2, 11, 3, 2, 9, 8, 7, 7,
+	2, 1, 5, 3, 2, 9, 3, 1, 7, 3, 1, 9, 8, 3, 9, 4, 2, 8, 5, 3, 15, 14, 10, 10, 5, 2, 9, 6, 2, 9, 3, 2, 9, 5,
+	2, 11, 10, 1, 7, 3, 2, 11, 2, 1, 9, 7, 4, 4, 3, 1, 8, 3, 1, 7, 4, 1, 7, 2, 1, 13, 11, 6, 5, 3, 2, 7, 3, 2,
+	8, 7, 5, 12, 3, 2, 13, 10, 6, 5, 3, 2, 5, 3, 2, 9, 5, 2, 9, 7, 2, 13, 4, 3, 4, 3, 1, 11, 6, 4, 18, 9, 6,
+	19, 18, 13, 11, 3, 2, 15, 9, 6, 4, 3, 1, 16, 5, 2, 15, 14, 6, 8, 5, 2, 15, 11, 2, 11, 6, 2, 7, 5, 3, 8,
+	3, 1, 19, 16, 9, 11, 9, 6, 15, 7, 6, 13, 4, 3, 14, 13, 3, 13, 6, 3, 9, 5, 2, 19, 13, 6, 19, 10, 3, 11,
+	6, 5, 9, 2, 1, 14, 3, 2, 13, 3, 1, 7, 5, 4, 11, 9, 8, 11, 6, 5, 23, 16, 9, 19, 14, 6, 23, 10, 2, 8, 3,
+	2, 5, 4, 3, 9, 6, 4, 4, 3, 2, 13, 8, 6, 13, 11, 1, 13, 10, 3, 11, 6, 5, 19, 17, 4, 15, 14, 7, 13, 9, 6,
+	9, 7, 3, 9, 7, 1, 14, 3, 2, 11, 8, 2, 11, 6, 4, 13, 5, 2, 11, 5, 1, 11, 4, 1, 19, 10, 3, 21, 10, 6, 13,
+	3, 1, 15, 7, 5, 19, 18, 10, 7, 5, 3, 12, 7, 2, 7, 5, 1, 14, 9, 6, 10, 3, 2, 15, 13, 12, 12, 11, 9, 16,
+	9, 7, 12, 9, 3, 9, 5, 2, 17, 10, 6, 24, 9, 3, 17, 15, 13, 5, 4, 3, 19, 17, 8, 15, 6, 3, 19, 6, 1,
+}
+
+// ssssField is GF(2^degree) as ssss defines it, elements being big.Int
+// polynomials over GF(2)
+type ssssField struct {
+	degree int
+	poly   *big.Int
+}
+
+func newSSSSField(degree int) ssssField {
+	c := ssssIrreducible[3*(degree/8-1):]
+	poly := new(big.Int).SetBit(new(big.Int), degree, 1)
+	for _, e := range []int{int(c[0]), int(c[1]), int(c[2]), 0} {
+		poly.SetBit(poly, e, 1)
+	}
+	return ssssField{degree: degree, poly: poly}
+}
+
+func (f ssssField) mul(a, b *big.Int) *big.Int {
+	r := new(big.Int)
+	for i := b.BitLen() - 1; i >= 0; i-- {
+		r.Lsh(r, 1)
+		if r.Bit(f.degree) == 1 {
+			r.Xor(r, f.poly)
+		}
+		if b.Bit(i) == 1 {
+			r.Xor(r, a)
+		}
+	}
+	return r
+}
+
+// inv inverts a non zero element with the extended Euclidean algorithm over
+// GF(2)[x]
+func (f ssssField) inv(a *big.Int) *big.Int {
+	u, v := new(big.Int).Set(a), new(big.Int).Set(f.poly)
+	g1, g2 := big.NewInt(1), new(big.Int)
+	t := new(big.Int)
+	for u.BitLen() > 1 {
+		j := u.BitLen() - v.BitLen()
+		if j < 0 {
+			u, v = v, u
+			g1, g2 = g2, g1
+			j = -j
+		}
+		u.Xor(u, t.Lsh(v, uint(j)))
+		g1.Xor(g1, t.Lsh(g2, uint(j)))
+	}
+	eraseInts([]*big.Int{u, v, g2, t})
+	return g1
+}
+
+// ssssXTEA runs the 32 cycles of XTEA with a zero key on v, forward or back
+func ssssXTEA(v *[2]uint32, forward bool) {
+	const delta = 0x9e3779b9
+	v0, v1 := v[0], v[1]
+	if forward {
+		var sum uint32
+		for i := 0; i < 32; i++ {
+			v0 += (v1<<4 ^ v1>>5) + v1 ^ sum
+			sum += delta
+			v1 += (v0<<4 ^ v0>>5) + v0 ^ sum
+		}
+	} else {
+		var sum uint32 = 0xc6ef3720
+		for i := 0; i < 32; i++ {
+			v1 -= (v0<<4 ^ v0>>5) + v0 ^ sum
+			sum -= delta
+			v0 -= (v1<<4 ^ v1>>5) + v1 ^ sum
+		}
+	}
+	v[0], v[1] = v0, v1
+}
+
+// ssssDiffuse runs the diffusion layer of ssss on the element x of
+// GF(2^degree), forward when splitting and back when combining. The element
+// is laid out as 16 bit big endian words, least significant first, the top
+// byte taking the place of the missing one when degree is not a multiple of
+// 16, and 8 byte windows of it are enciphered at every even offset 40 times
+// around.
+func ssssDiffuse(x *big.Int, degree int, forward bool) *big.Int {
+	n := degree / 8
+	words := (degree + 8) / 16
+	data := make([]byte, 2*words)
+	defer erase(data)
+	var le [MaxSSSSBytes + 2]byte
+	defer erase(le[:])
+	x.FillBytes(le[:2*words])
+	// FillBytes is big endian, turn it into words least significant first
+	for w := 0; w < words; w++ {
+		data[2*w], data[2*w+1] = le[2*(words-1-w)], le[2*(words-1-w)+1]
+	}
+	if degree%16 == 8 {
+		data[n-1] = data[n]
+	}
+	slice := func(idx int) {
+		var v [2]uint32
+		var b [8]byte
+		for i := range b {
+			b[i] = data[(idx+i)%n]
+		}
+		v[0], v[1] = binary.BigEndian.Uint32(b[:4]), binary.BigEndian.Uint32(b[4:])
+		ssssXTEA(&v, forward)
+		binary.BigEndian.PutUint32(b[:4], v[0])
+		binary.BigEndian.PutUint32(b[4:], v[1])
+		for i := range b {
+			data[(idx+i)%n] = b[i]
+		}
+	}
+	if forward {
+		for i := 0; i < 40*n; i += 2 {
+			slice(i)
+		}
+	} else {
+		for i := 40*n - 2; i >= 0; i -= 2 {
+			slice(i)
+		}
+	}
+	if degree%16 == 8 {
+		data[n] = data[n-1]
+		data[n-1] = 0
+	}
+	for w := 0; w < words; w++ {
+		le[2*(words-1-w)], le[2*(words-1-w)+1] = data[2*w], data[2*w+1]
+	}
+	return new(big.Int).SetBytes(le[:2*words])
+}
+
+// SplitSSSS splits secret as ssss-split does with the security level of the
+// secret size, its default, and returns the lines it would print. The token,
+// if any, prefixes every share. The secret is at most MaxSSSSBytes.
+func SplitSSSS(secret []byte, sharesCount int, threshold int, token string, opts ...Option) ([]string, error) {
+	cfg := newConfig(opts)
+	if len(secret) == 0 {
+		return nil, ErrSecretRequired
+	}
+	if len(secret) > MaxSSSSBytes {
+		return nil, ErrSecretTooLarge
+	}
+	if sharesCount > cfg.sharesLimit() {
+		return nil, ErrTooManyShares
+	}
+	if threshold < cfg.minThreshold() || threshold > sharesCount {
+		return nil, ErrInvalidThreshold
+	}
+	random, err := cfg.source(secret, threshold)
+	if err != nil {
+		return nil, err
+	}
+	degree := 8 * len(secret)
+	f := newSSSSField(degree)
+	coefficients := make([]*big.Int, threshold)
+	defer eraseInts(coefficients)
+	coefficients[0] = new(big.Int).SetBytes(secret)
+	if degree >= 64 && !cfg.ssssPlain {
+		plain := coefficients[0]
+		coefficients[0] = ssssDiffuse(plain, degree, true)
+		eraseInt(plain)
+	}
+	buf := make([]byte, len(secret))
+	defer erase(buf)
+	for i := 1; i < threshold; i++ {
+		if _, err := io.ReadFull(random, buf); err != nil {
+			return nil, err
+		}
+		coefficients[i] = new(big.Int).SetBytes(buf)
+	}
+	width := len(strconv.Itoa(sharesCount))
+	lines := make([]string, sharesCount)
+	for i := range lines {
+		x := big.NewInt(int64(i + 1))
+		// Horner's rule on the monic polynomial, as ssss evaluates it
+		y := new(big.Int).Set(x)
+		for j := threshold - 1; j > 0; j-- {
+			y = f.mul(y.Xor(y, coefficients[j]), x)
+		}
+		y.Xor(y, coefficients[0])
+		y.FillBytes(buf)
+		prefix := ""
+		if token != "" {
+			prefix = token + "-"
+		}
+		lines[i] = fmt.Sprintf("%s%0*d-%x", prefix, width, i+1, buf)
+		eraseInt(y)
+	}
+	return lines, nil
+}
+
+// parseSSSSShare reads a [token-]index-hex line of ssss
+func parseSSSSShare(line string) (index int64, y []byte, err error) {
+	line = strings.TrimSpace(line)
+	cut := strings.LastIndexByte(line, '-')
+	if cut < 0 {
+		return 0, nil, ErrInvalidSSSSShare
+	}
+	digits := line[:cut]
+	if i := strings.LastIndexByte(digits, '-'); i >= 0 {
+		digits = digits[i+1:]
+	}
+	index, err = strconv.ParseInt(digits, 10, 64)
+	if err != nil || index < 1 {
+		return 0, nil, ErrInvalidShareIndex
+	}
+	payload := line[cut+1:]
+	if len(payload) == 0 || len(payload)%2 != 0 || len(payload) > 2*MaxSSSSBytes {
+		return 0, nil, ErrShareSize
+	}
+	if y, err = hex.DecodeString(payload); err != nil {
+		return 0, nil, ErrInvalidSSSSShare
+	}
+	return index, y, nil
+}
+
+// CombineSSSS recovers the secret of shares written by ssss-split, as
+// ssss-combine -t threshold does from the first threshold of them. The secret
+// is returned with the size of the shares, ssss prints it without its
+// leading zero bytes. A share failing to parse is reported as a *ShareError.
+func CombineSSSS(shares []string, threshold int, opts ...Option) ([]byte, error) {
+	cfg := newConfig(opts)
+	if threshold < cfg.minThreshold() {
+		return nil, ErrInvalidThreshold
+	}
+	if len(shares) < threshold {
+		return nil, &ThresholdError{Need: threshold, Have: len(shares)}
+	}
+	var f ssssField
+	xs := make([]*big.Int, threshold)
+	ys := make([]*big.Int, threshold)
+	defer eraseInts(ys)
+	for i, line := range shares[:threshold] {
+		index, y, err := parseSSSSShare(line)
+		if err != nil {
+			return nil, &ShareError{Position: i + 1, Reason: err}
+		}
+		if i == 0 {
+			f = newSSSSField(8 * len(y))
+		} else if 8*len(y) != f.degree {
+			return nil, &ShareError{Position: i + 1, Reason: ErrShareSize}
+		}
+		xs[i] = big.NewInt(index)
+		if xs[i].BitLen() > f.degree {
+			return nil, &ShareError{Position: i + 1, Reason: ErrInvalidShareIndex}
+		}
+		for _, x := range xs[:i] {
+			if x.Cmp(xs[i]) == 0 {
+				return nil, &ShareError{Position: i + 1, Reason: ErrDuplicateShare}
+			}
+		}
+		// drop the x^threshold term of the monic polynomial
+		power := big.NewInt(1)
+		for j := 0; j < threshold; j++ {
+			power = f.mul(power, xs[i])
+		}
+		ys[i] = new(big.Int).SetBytes(y)
+		ys[i].Xor(ys[i], power)
+		erase(y)
+	}
+	// Lagrange interpolation at 0, subtraction being addition
+	secret := new(big.Int)
+	defer func() {
+		eraseInt(secret)
+	}()
+	for i := range xs {
+		num, den := big.NewInt(1), big.NewInt(1)
+		for j := range xs {
+			if i != j {
+				num = f.mul(num, xs[j])
+				den = f.mul(den, new(big.Int).Xor(xs[i], xs[j]))
+			}
+		}
+		term := f.mul(ys[i], f.mul(num, f.inv(den)))
+		secret.Xor(secret, term)
+		eraseInt(term)
+	}
+	if f.degree >= 64 && !cfg.ssssPlain {
+		diffused := secret
+		secret = ssssDiffuse(diffused, f.degree, false)
+		eraseInt(diffused)
+	}
+	out, err := cfg.alloc(f.degree / 8)
+	if err != nil {
+		return nil, err
+	}
+	secret.FillBytes(out)
+	return out, nil
+}
@@ -0,0 +1,62 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestSSSS(t *testing.T) {
+	for _, size := range []int{1, 7, 8, 9, 16, 17, 32, MaxSSSSBytes} {
+		for _, diffusion := range []bool{true, false} {
+			secret := randomBytes(size)
+			lines, err := SplitSSSS(secret, 12, 3, "vault", WithSSSSDiffusion(diffusion))
+			if err != nil {
+				failNow(t, err)
+			}
+			if want := fmt.Sprintf("vault-05-%0*x", 2*size, 0); len(lines[4]) != len(want) || lines[4][:9] != want[:9] {
+				failNow(t, fmt.Errorf("share line %q", lines[4]))
+			}
+			recovered, err := CombineSSSS([]string{lines[11], lines[4], lines[0]}, 3, WithSSSSDiffusion(diffusion))
+			if err != nil {
+				failNow(t, err)
+			}
+			if !bytes.Equal(recovered, secret) {
+				failNow(t, fmt.Errorf("size %d diffusion %v: recovered %x, want %x", size, diffusion, recovered, secret))
+			}
+		}
+	}
+}
+
+// TestSSSSMonic recovers shares of the monic polynomial x^2 + 0x53·x + 0x2a,
+// over the field of degree 8 which is GF(256)
+func TestSSSSMonic(t *testing.T) {
+	y := func(x byte) byte {
+		return mul(x, x) ^ mul(0x53, x) ^ 0x2a
+	}
+	lines := []string{fmt.Sprintf("3-%02x", y(3)), fmt.Sprintf("7-%02x", y(7))}
+	recovered, err := CombineSSSS(lines, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, []byte{0x2a}) {
+		failNow(t, fmt.Errorf("recovered %x", recovered))
+	}
+	if _, err := CombineSSSS([]string{lines[0], lines[0]}, 2); err == nil {
+		failNow(t, expected(ErrDuplicateShare, err))
+	}
+}
+
+func TestSSSSDiffusion(t *testing.T) {
+	for _, degree := range []int{64, 72, 1024} {
+		x := new(big.Int).SetBytes(randomBytes(degree / 8))
+		diffused := ssssDiffuse(x, degree, true)
+		if diffused.BitLen() > degree || diffused.Cmp(x) == 0 {
+			failNow(t, fmt.Errorf("degree %d: diffused %x", degree, diffused))
+		}
+		if ssssDiffuse(diffused, degree, false).Cmp(x) != 0 {
+			failNow(t, fmt.Errorf("degree %d: diffusion not inverted", degree))
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package tss
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// StoreManifestName is the name of the manifest of a DirStore
+const StoreManifestName = "manifest.json"
+
+var (
+	ErrShareNotFound   = validationError("share not found in store")
+	ErrInvalidLabel    = validationError("invalid share label")
+	ErrInvalidManifest = validationError("invalid store manifest")
+)
+
+// ShareStore keeps shares under a label, on disk, in an HSM or elsewhere.
+// Put replaces the share of an existing label, Get and Delete return
+// ErrShareNotFound for a missing one.
+type ShareStore interface {
+	Put(label string, share Share) error
+	Get(label string) (Share, error)
+	Delete(label string) error
+}
+
+// BlobStore keeps opaque bytes under a label, such as shares encrypted by
+// the wrapping stores of the pkcs11, tpm and kms packages, which are no
+// shares and are not bound by their size limits. PutBlob replaces the blob
+// of an existing label, GetBlob and Delete return ErrShareNotFound for a
+// missing one.
+type BlobStore interface {
+	PutBlob(label string, blob []byte) error
+	GetBlob(label string) ([]byte, error)
+	Delete(label string) error
+}
+
+// DirStore is a ShareStore writing each share to a file of its directory,
+// named after the label, with ShareFileMode. Files are written atomically
+// and synced, the directory is created with mode 0700. A manifest,
+// StoreManifestName, lists the share files with their digest, checked by
+// Get. Shares written before the manifest existed, missing from it, are
+// read unchecked. Several processes must not write to the same directory.
+type DirStore struct {
+	Dir string
+}
+
+// StoreManifest is the manifest of a DirStore, the share files by label
+type StoreManifest struct {
+	Shares map[string]ShareFile `json:"shares"`
+	// Pending are the digests of the shares being written by Put, by label,
+	// so a crash before their entry is updated leaves them readable
+	Pending map[string]string `json:"pending,omitempty"`
+}
+
+// dirStoreMu serializes the manifest updates of the DirStores of the process
+var dirStoreMu sync.Mutex
+
+// Manifest reads the manifest of the store, empty when there is none yet
+func (d DirStore) Manifest() (StoreManifest, error) {
+	m := StoreManifest{Shares: map[string]ShareFile{}, Pending: map[string]string{}}
+	data, err := os.ReadFile(filepath.Join(d.Dir, StoreManifestName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil || m.Shares == nil {
+		return StoreManifest{}, ErrInvalidManifest
+	}
+	if m.Pending == nil {
+		m.Pending = map[string]string{}
+	}
+	return m, nil
+}
+
+// update applies change to the manifest and writes it back
+func (d DirStore) update(change func(m StoreManifest)) error {
+	m, err := d.Manifest()
+	if err != nil {
+		return err
+	}
+	change(m)
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(d.Dir, StoreManifestName), append(data, '\n'))
+}
+
+// path returns the file of label, which must be a plain file name
+func (d DirStore) path(label string) (string, error) {
+	if label == "" || label == "." || label == ".." || strings.ContainsAny(label, `/\`) || strings.HasPrefix(label, ".") {
+		return "", ErrInvalidLabel
+	}
+	return filepath.Join(d.Dir, label+".share"), nil
+}
+
+// Put writes the share through a temporary file renamed in place. Its
+// digest is recorded as pending in the manifest first and moved to its entry
+// once the file is written, so a crash in between leaves either share
+// readable.
+func (d DirStore) Put(label string, share Share) error {
+	if !share.Valid() {
+		return ErrInvalidShare
+	}
+	return d.put(label, share, share.Index())
+}
+
+// PutBlob writes blob as Put writes a share, its manifest entry having
+// index 0
+func (d DirStore) PutBlob(label string, blob []byte) error {
+	return d.put(label, blob, 0)
+}
+
+func (d DirStore) put(label string, data []byte, index byte) error {
+	path, err := d.path(label)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(d.Dir, 0700); err != nil {
+		return err
+	}
+	dirStoreMu.Lock()
+	defer dirStoreMu.Unlock()
+	digest := sha256.Sum256(data)
+	sum := hex.EncodeToString(digest[:])
+	if err := d.update(func(m StoreManifest) {
+		m.Pending[label] = sum
+	}); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return err
+	}
+	return d.update(func(m StoreManifest) {
+		m.Shares[label] = ShareFile{Index: index, Path: filepath.Base(path), SHA256: sum}
+		delete(m.Pending, label)
+	})
+}
+
+// Get reads the share of label, which must match its digest in the manifest
+// or the pending one when it has any
+func (d DirStore) Get(label string) (Share, error) {
+	data, err := d.GetBlob(label)
+	if err != nil {
+		return nil, err
+	}
+	share := Share(data)
+	if !share.Valid() {
+		erase(data)
+		return nil, ErrInvalidShare
+	}
+	return share, nil
+}
+
+// GetBlob reads the blob of label, checked against the manifest as by Get
+func (d DirStore) GetBlob(label string) ([]byte, error) {
+	path, err := d.path(label)
+	if err != nil {
+		return nil, err
+	}
+	dirStoreMu.Lock()
+	defer dirStoreMu.Unlock()
+	m, err := d.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrShareNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(data)
+	sum := hex.EncodeToString(digest[:])
+	entry, listed := m.Shares[label]
+	pending, put := m.Pending[label]
+	if (listed || put) && sum != entry.SHA256 && sum != pending {
+		erase(data)
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), ErrShareFileDigest)
+	}
+	return data, nil
+}
+
+// Delete removes the file of label, erasing its content first, and its
+// manifest entry
+func (d DirStore) Delete(label string) error {
+	path, err := d.path(label)
+	if err != nil {
+		return err
+	}
+	dirStoreMu.Lock()
+	defer dirStoreMu.Unlock()
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if errors.Is(err, fs.ErrNotExist) {
+		// drop the entry of a file removed behind the store's back
+		m, err := d.Manifest()
+		if err != nil {
+			return err
+		}
+		_, listed := m.Shares[label]
+		_, pending := m.Pending[label]
+		if listed || pending {
+			if err := d.forget(label); err != nil {
+				return err
+			}
+		}
+		return ErrShareNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if info, err := f.Stat(); err == nil {
+		f.Write(make([]byte, info.Size()))
+		f.Sync()
+	}
+	f.Close()
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	if err := syncDir(d.Dir); err != nil {
+		return err
+	}
+	return d.forget(label)
+}
+
+// forget removes the manifest entry of label
+func (d DirStore) forget(label string) error {
+	return d.update(func(m StoreManifest) {
+		delete(m.Shares, label)
+		delete(m.Pending, label)
+	})
+}
@@ -0,0 +1,181 @@
+package tss
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirStore(t *testing.T) {
+	store := DirStore{Dir: filepath.Join(t.TempDir(), "shares")}
+	shares, err := CreateShares(randomBytes(32), 3, 2, WithConsumeSecret(false))
+	if err != nil {
+		failNow(t, err)
+	}
+	for i, s := range shares {
+		if err := store.Put(string(rune('a'+i)), s); err != nil {
+			failNow(t, err)
+		}
+	}
+	info, err := os.Stat(store.Dir + "/a.share")
+	if err != nil {
+		failNow(t, err)
+	}
+	if info.Mode().Perm() != ShareFileMode {
+		t.Fatalf("share file mode %v", info.Mode().Perm())
+	}
+	if info, err := os.Stat(store.Dir); err != nil || info.Mode().Perm() != 0700 {
+		t.Fatalf("store directory mode %v", info.Mode().Perm())
+	}
+	m, err := store.Manifest()
+	if err != nil {
+		failNow(t, err)
+	}
+	if len(m.Shares) != 3 || m.Shares["c"].Index != shares[2].Index() || m.Shares["c"].Path != "c.share" {
+		t.Fatalf("manifest %v", m)
+	}
+	entries, _ := os.ReadDir(store.Dir)
+	if len(entries) != 4 {
+		t.Fatalf("%d files in store directory", len(entries))
+	}
+	s, err := store.Get("b")
+	if err != nil {
+		failNow(t, err)
+	}
+	if string(s) != string(shares[1]) {
+		failNow(t, ErrInvalidShare)
+	}
+	if err := store.Delete("b"); err != nil {
+		failNow(t, err)
+	}
+	if _, err := store.Get("b"); err != ErrShareNotFound {
+		failNow(t, expected(ErrShareNotFound, err))
+	}
+	if err := store.Delete("b"); err != ErrShareNotFound {
+		failNow(t, expected(ErrShareNotFound, err))
+	}
+	if m, _ := store.Manifest(); len(m.Shares) != 2 {
+		t.Fatalf("manifest %v", m)
+	}
+	if err := store.Put("../a", shares[0]); err != ErrInvalidLabel {
+		failNow(t, expected(ErrInvalidLabel, err))
+	}
+}
+
+func TestDirStoreBlob(t *testing.T) {
+	store := DirStore{Dir: t.TempDir()}
+	blob := randomBytes(MaxShareBytes + 100)
+	if err := store.PutBlob("a", blob); err != nil {
+		failNow(t, err)
+	}
+	got, err := store.GetBlob("a")
+	if err != nil {
+		failNow(t, err)
+	}
+	if string(got) != string(blob) {
+		failNow(t, fmt.Errorf("blob mismatch"))
+	}
+	if m, _ := store.Manifest(); m.Shares["a"].Index != 0 {
+		t.Fatalf("manifest %v", m)
+	}
+	if _, err := store.Get("a"); err != ErrInvalidShare {
+		failNow(t, expected(ErrInvalidShare, err))
+	}
+	if err := store.Delete("a"); err != nil {
+		failNow(t, err)
+	}
+	if _, err := store.GetBlob("a"); err != ErrShareNotFound {
+		failNow(t, expected(ErrShareNotFound, err))
+	}
+}
+
+func TestDirStoreDigest(t *testing.T) {
+	store := DirStore{Dir: t.TempDir()}
+	if err := store.Put("a", Share{1, 2, 3}); err != nil {
+		failNow(t, err)
+	}
+	if err := os.WriteFile(filepath.Join(store.Dir, "a.share"), []byte{1, 2, 4}, ShareFileMode); err != nil {
+		failNow(t, err)
+	}
+	if _, err := store.Get("a"); !errors.Is(err, ErrShareFileDigest) {
+		failNow(t, expected(ErrShareFileDigest, err))
+	}
+	if err := os.WriteFile(filepath.Join(store.Dir, StoreManifestName), []byte("[]"), ShareFileMode); err != nil {
+		failNow(t, err)
+	}
+	if _, err := store.Get("a"); err != ErrInvalidManifest {
+		failNow(t, expected(ErrInvalidManifest, err))
+	}
+}
+
+func TestDirStoreDeleteMissing(t *testing.T) {
+	store := DirStore{Dir: t.TempDir()}
+	if err := store.Put("a", Share{1, 2, 3}); err != nil {
+		failNow(t, err)
+	}
+	if err := os.Remove(filepath.Join(store.Dir, "a.share")); err != nil {
+		failNow(t, err)
+	}
+	if err := store.Delete("a"); err != ErrShareNotFound {
+		failNow(t, expected(ErrShareNotFound, err))
+	}
+	m, err := store.Manifest()
+	if err != nil {
+		failNow(t, err)
+	}
+	if _, ok := m.Shares["a"]; ok {
+		failNow(t, fmt.Errorf("stale manifest entry"))
+	}
+}
+
+func TestDirStoreLegacy(t *testing.T) {
+	store := DirStore{Dir: t.TempDir()}
+	share := Share{1, 2, 3}
+	if err := os.WriteFile(filepath.Join(store.Dir, "old.share"), share, ShareFileMode); err != nil {
+		failNow(t, err)
+	}
+	if s, err := store.Get("old"); err != nil || string(s) != string(share) {
+		failNow(t, fmt.Errorf("legacy share without manifest: %v", err))
+	}
+	if err := store.Put("new", Share{2, 5, 6}); err != nil {
+		failNow(t, err)
+	}
+	if s, err := store.Get("old"); err != nil || string(s) != string(share) {
+		failNow(t, fmt.Errorf("legacy share missing from manifest: %v", err))
+	}
+}
+
+func TestDirStoreInterruptedPut(t *testing.T) {
+	store := DirStore{Dir: t.TempDir()}
+	old, share := Share{1, 2, 3}, Share{1, 7, 8}
+	if err := store.Put("a", old); err != nil {
+		failNow(t, err)
+	}
+	// the pending digest is recorded, the share file not written yet
+	digest := sha256.Sum256(share)
+	if err := store.update(func(m StoreManifest) {
+		m.Pending["a"] = hex.EncodeToString(digest[:])
+	}); err != nil {
+		failNow(t, err)
+	}
+	if s, err := store.Get("a"); err != nil || string(s) != string(old) {
+		failNow(t, fmt.Errorf("share before the write: %v", err))
+	}
+	// the share file written, its manifest entry not updated yet
+	if err := writeFileAtomic(filepath.Join(store.Dir, "a.share"), share); err != nil {
+		failNow(t, err)
+	}
+	if s, err := store.Get("a"); err != nil || string(s) != string(share) {
+		failNow(t, fmt.Errorf("share after the write: %v", err))
+	}
+	if err := store.Put("a", share); err != nil {
+		failNow(t, err)
+	}
+	if m, _ := store.Manifest(); len(m.Pending) != 0 || m.Shares["a"].SHA256 != hex.EncodeToString(digest[:]) {
+		t.Fatalf("manifest %v", m)
+	}
+}
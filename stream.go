@@ -0,0 +1,210 @@
+package tss
+
+import (
+	"io"
+	"strconv"
+)
+
+// StreamBlockBytes is the number of secret bytes processed at a time by the
+// streaming split and combine
+const StreamBlockBytes = 4096
+
+// SplitWriter splits a secret read from a stream into share streams, one per
+// writer, holding a single block of the secret in memory at a time.
+// Each share stream is the share index followed by one byte per secret byte,
+// so a share stream of a secret up to MaxSecretBytes is a regular share.
+type SplitWriter struct {
+	secret    io.Reader
+	writers   []io.Writer
+	threshold int
+	opts      []Option
+	cfg       *config
+}
+
+// NewSplitWriter prepares the split of secret into len(writers) shares,
+// threshold of them being required to recover it. The coefficients come from
+// the source of the options, as for CreateShares.
+func NewSplitWriter(secret io.Reader, writers []io.Writer, threshold int, opts ...Option) (*SplitWriter, error) {
+	if len(writers) < MinShares {
+		return nil, ErrTooFewShares
+	}
+	if len(writers) > MaxShares {
+		return nil, ErrTooManyShares
+	}
+	if threshold > len(writers) || threshold < MinThreshold {
+		return nil, ErrInvalidThreshold
+	}
+	opts = append([]Option{}, opts...)
+	return &SplitWriter{secret: secret, writers: writers, threshold: threshold, opts: opts, cfg: newConfig(opts)}, nil
+}
+
+// Split reads the secret until EOF and writes the shares, returning the
+// number of secret bytes processed
+func (s *SplitWriter) Split() (int64, error) {
+	for i, w := range s.writers {
+		if _, err := w.Write([]byte{byte(i + 1)}); err != nil {
+			return 0, err
+		}
+	}
+	block := make([]byte, StreamBlockBytes)
+	defer erase(block)
+	out := make(ShareSet, len(s.writers))
+	for j := range out {
+		out[j] = make(Share, 1+StreamBlockBytes)
+		out[j][0] = byte(j + 1)
+	}
+	coefficients := make([]byte, 2*StreamBlockBytes*s.threshold)
+	defer erase(coefficients)
+	coefficients, rows := coefficients[:len(coefficients)/2], coefficients[len(coefficients)/2:]
+	field := s.cfg.field()
+	var total int64
+	for seq := 0; ; seq++ {
+		n, err := io.ReadFull(s.secret, block)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return total, err
+		}
+		if n > 0 {
+			random, err := s.source(block[:n], seq)
+			if err != nil {
+				return total, err
+			}
+			if _, err := io.ReadFull(random, coefficients[:n*s.threshold]); err != nil {
+				return total, err
+			}
+			evalBatch(field, out, block[:n], 0, coefficients, rows, s.threshold)
+		}
+		for j, w := range s.writers {
+			if _, err := w.Write(out[j][1 : 1+n]); err != nil {
+				return total, err
+			}
+		}
+		total += int64(n)
+		if err != nil {
+			break
+		}
+	}
+	if total == 0 {
+		return 0, ErrSecretRequired
+	}
+	return total, nil
+}
+
+// source returns the source of the coefficients of block seq of the secret
+func (s *SplitWriter) source(block []byte, seq int) (io.Reader, error) {
+	if !s.cfg.deterministic {
+		return s.cfg.source(block, s.threshold)
+	}
+	// equal blocks must not get equal shares
+	opts := append(s.opts[:len(s.opts):len(s.opts)], WithDeterministic(s.cfg.salt, s.cfg.info+"\x00block"+strconv.Itoa(seq)))
+	return newConfig(opts).source(block, s.threshold)
+}
+
+// CombineReader recovers a secret from share streams written by SplitWriter,
+// holding a single block of every share in memory at a time
+type CombineReader struct {
+	readers []io.Reader
+	u       []byte
+	v       []byte
+	blocks  [][]byte
+	buf     []byte
+	pending []byte
+	err     error
+}
+
+// NewCombineReader prepares the recovery of a secret from share streams
+func NewCombineReader(readers []io.Reader) (*CombineReader, error) {
+	if len(readers) < MinShares {
+		return nil, ErrTooFewShares
+	}
+	if len(readers) > MaxShares {
+		return nil, ErrTooManyShares
+	}
+	return &CombineReader{readers: readers}, nil
+}
+
+// Read reads the recovered secret. Share streams of different lengths make
+// Read fail with ErrInvalidShare, and a zero or repeated share index with a
+// *ShareError.
+func (c *CombineReader) Read(p []byte) (int, error) {
+	for len(c.pending) == 0 {
+		if c.err != nil {
+			return 0, c.err
+		}
+		c.err = c.next()
+		if c.err != nil {
+			c.wipe()
+		}
+	}
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+// next recovers the following block of the secret into pending
+func (c *CombineReader) next() error {
+	if c.u == nil {
+		if err := c.start(); err != nil {
+			return err
+		}
+	}
+	size := -1
+	for i, r := range c.readers {
+		n, err := io.ReadFull(r, c.blocks[i])
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return err
+		}
+		if size != -1 && n != size {
+			return ErrInvalidShare
+		}
+		size = n
+	}
+	if size == 0 {
+		return io.EOF
+	}
+	ys := make([][]byte, len(c.blocks))
+	for i := range c.blocks {
+		ys[i] = c.blocks[i][:size]
+	}
+	gf256{}.combine(c.buf[:size], c.v, ys)
+	c.pending = c.buf[:size]
+	return nil
+}
+
+// start reads and checks the share indexes heading the share streams and
+// computes the weights of the shares
+func (c *CombineReader) start() error {
+	c.u = make([]byte, len(c.readers))
+	for i, r := range c.readers {
+		if _, err := io.ReadFull(r, c.u[i:i+1]); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return ErrInvalidShare
+			}
+			return err
+		}
+		if c.u[i] == 0 {
+			return &ShareError{Position: i + 1, Reason: ErrInvalidShareIndex}
+		}
+		for _, u := range c.u[:i] {
+			if u == c.u[i] {
+				return &ShareError{Position: i + 1, Index: u, Reason: ErrDuplicateShare}
+			}
+		}
+	}
+	c.v = make([]byte, len(c.readers))
+	gf256{}.weights(c.v, c.u, 0)
+	c.blocks = make([][]byte, len(c.readers))
+	for i := range c.blocks {
+		c.blocks[i] = make([]byte, StreamBlockBytes)
+	}
+	c.buf = make([]byte, StreamBlockBytes)
+	return nil
+}
+
+// wipe erases the blocks of the shares and of the secret held by c
+func (c *CombineReader) wipe() {
+	for _, b := range c.blocks {
+		erase(b)
+	}
+	erase(c.buf)
+	c.pending = nil
+}
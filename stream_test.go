@@ -0,0 +1,164 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	for _, size := range []int{1, StreamBlockBytes, 3*StreamBlockBytes + 17} {
+		t.Run(fmt.Sprintf("%d", size), func(t *testing.T) {
+			testStream(t, size, 5, 3)
+		})
+	}
+}
+
+func testStream(t *testing.T, secretSize int, sharesCount int, threshold int) {
+	secret := randomBytes(secretSize)
+	buffers := make([]*bytes.Buffer, sharesCount)
+	writers := make([]io.Writer, sharesCount)
+	for i := range buffers {
+		buffers[i] = &bytes.Buffer{}
+		writers[i] = buffers[i]
+	}
+	split, err := NewSplitWriter(bytes.NewReader(secret), writers, threshold)
+	if err != nil {
+		failNow(t, err)
+	}
+	n, err := split.Split()
+	if err != nil {
+		failNow(t, err)
+	}
+	if n != int64(secretSize) {
+		failNow(t, fmt.Errorf("split %d bytes, want %d", n, secretSize))
+	}
+	readers := []io.Reader{buffers[4], buffers[0], buffers[2]}
+	combine, err := NewCombineReader(readers)
+	if err != nil {
+		failNow(t, err)
+	}
+	recovered, err := ioutil.ReadAll(combine)
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+}
+
+func TestStreamIsShare(t *testing.T) {
+	secret := randomBytes(100)
+	a, b := &bytes.Buffer{}, &bytes.Buffer{}
+	split, _ := NewSplitWriter(bytes.NewReader(secret), []io.Writer{a, b}, 2)
+	if _, err := split.Split(); err != nil {
+		failNow(t, err)
+	}
+	testRecover(t, secret, ShareSet{a.Bytes(), b.Bytes()})
+}
+
+func TestStreamErrors(t *testing.T) {
+	if _, err := NewSplitWriter(bytes.NewReader(nil), []io.Writer{ioutil.Discard}, 2); err != ErrTooFewShares {
+		failNow(t, expected(ErrTooFewShares, err))
+	}
+	split, _ := NewSplitWriter(bytes.NewReader(nil), []io.Writer{ioutil.Discard, ioutil.Discard}, 2)
+	if _, err := split.Split(); err != ErrSecretRequired {
+		failNow(t, expected(ErrSecretRequired, err))
+	}
+	shares, _ := CreateShares(randomBytes(10), 2, 2)
+	combine, _ := NewCombineReader([]io.Reader{bytes.NewReader(shares[0]), bytes.NewReader(shares[1][:5])})
+	if _, err := ioutil.ReadAll(combine); err != ErrInvalidShare {
+		failNow(t, expected(ErrInvalidShare, err))
+	}
+}
+
+func TestStreamShareIndexes(t *testing.T) {
+	shares, _ := CreateShares(randomBytes(10), 3, 2)
+	zero := append(Share{0}, shares[1][1:]...)
+	combine, _ := NewCombineReader([]io.Reader{bytes.NewReader(shares[0]), bytes.NewReader(zero)})
+	_, err := ioutil.ReadAll(combine)
+	var se *ShareError
+	if !errors.As(err, &se) || se.Position != 2 || !errors.Is(err, ErrInvalidShareIndex) {
+		failNow(t, expected(ErrInvalidShareIndex, err))
+	}
+	combine, _ = NewCombineReader([]io.Reader{bytes.NewReader(shares[1]), bytes.NewReader(shares[1])})
+	_, err = ioutil.ReadAll(combine)
+	if !errors.As(err, &se) || se.Index != 2 || !errors.Is(err, ErrDuplicateShare) {
+		failNow(t, expected(ErrDuplicateShare, err))
+	}
+}
+
+func TestStreamReadError(t *testing.T) {
+	shares, _ := CreateShares(randomBytes(10), 2, 2)
+	failure := errors.New("read failure")
+	broken := io.MultiReader(bytes.NewReader(shares[1][:1]), errorReader{failure})
+	combine, _ := NewCombineReader([]io.Reader{bytes.NewReader(shares[0]), broken})
+	if _, err := ioutil.ReadAll(combine); err != failure {
+		failNow(t, expected(failure, err))
+	}
+	if bytes.Equal(combine.blocks[0][:10], shares[0][1:]) {
+		failNow(t, fmt.Errorf("share block not wiped"))
+	}
+}
+
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+func TestStreamRand(t *testing.T) {
+	secret := randomBytes(1000)
+	seed := randomBytes(1000 * 3)
+	a, b, c := &bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}
+	split, _ := NewSplitWriter(bytes.NewReader(secret), []io.Writer{a, b, c}, 3, WithRand(bytes.NewReader(seed)))
+	if _, err := split.Split(); err != nil {
+		failNow(t, err)
+	}
+	shares, err := CreateShares(secret, 3, 3, WithRand(bytes.NewReader(seed)), WithConsumeSecret(false))
+	if err != nil {
+		failNow(t, err)
+	}
+	for i, buf := range []*bytes.Buffer{a, b, c} {
+		if !bytes.Equal(buf.Bytes(), shares[i]) {
+			failNow(t, fmt.Errorf("share %d differs from CreateShares", i+1))
+		}
+	}
+	split, _ = NewSplitWriter(bytes.NewReader(secret), []io.Writer{a, b}, 2, WithRand(bytes.NewReader(seed)), WithStrictMode())
+	if _, err := split.Split(); err != ErrNotAllowed {
+		failNow(t, expected(ErrNotAllowed, err))
+	}
+}
+
+func TestStreamDeterministic(t *testing.T) {
+	block := randomBytes(StreamBlockBytes)
+	secret := append(append([]byte{}, block...), block...)
+	split := func() ShareSet {
+		a, b := &bytes.Buffer{}, &bytes.Buffer{}
+		s, _ := NewSplitWriter(bytes.NewReader(secret), []io.Writer{a, b}, 2, WithDeterministic([]byte("salt"), "stream"))
+		if _, err := s.Split(); err != nil {
+			failNow(t, err)
+		}
+		return ShareSet{a.Bytes(), b.Bytes()}
+	}
+	shares := split()
+	if !bytes.Equal(shares[0], split()[0]) {
+		failNow(t, fmt.Errorf("deterministic shares differ"))
+	}
+	if bytes.Equal(shares[0][1:1+StreamBlockBytes], shares[0][1+StreamBlockBytes:]) {
+		failNow(t, fmt.Errorf("equal blocks got equal shares"))
+	}
+	combine, _ := NewCombineReader([]io.Reader{bytes.NewReader(shares[1]), bytes.NewReader(shares[0])})
+	recovered, err := ioutil.ReadAll(combine)
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+}
@@ -0,0 +1,82 @@
+package tss
+
+import (
+	"encoding/binary"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// StringPadBytes is the block the secret of SplitString is padded to, so the
+// share size only tells the passphrase length to the nearest block
+const StringPadBytes = 32
+
+var (
+	ErrInvalidUTF8 = validationError("secret is not valid utf-8")
+)
+
+// SplitString splits a passphrase into printable shares, as written by
+// EncodeShare. The passphrase must be valid UTF-8, it is normalized to NFC so
+// the same passphrase typed on different systems recovers the same, and its
+// length is recorded ahead of padding to a multiple of StringPadBytes.
+// Go strings cannot be erased, callers handling the passphrase as bytes should
+// prefer CreateShares.
+func SplitString(secret string, sharesCount int, threshold int, opts ...Option) ([]string, error) {
+	if secret == "" {
+		return nil, ErrSecretRequired
+	}
+	if !utf8.ValidString(secret) {
+		return nil, ErrInvalidUTF8
+	}
+	secret = norm.NFC.String(secret)
+	if len(secret) > MaxSecretBytes-2 {
+		return nil, ErrSecretTooLarge
+	}
+	size := (2 + len(secret) + StringPadBytes - 1) / StringPadBytes * StringPadBytes
+	if size > MaxSecretBytes {
+		size = MaxSecretBytes
+	}
+	padded := make([]byte, size)
+	defer erase(padded)
+	binary.BigEndian.PutUint16(padded, uint16(len(secret)))
+	copy(padded[2:], secret)
+	shares, err := CreateShares(padded, sharesCount, threshold, opts...)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]string, len(shares))
+	for i, s := range shares {
+		lines[i] = EncodeShare(s)
+		erase(s)
+	}
+	return lines, nil
+}
+
+// RecoverString recovers a passphrase split by SplitString from its printable
+// shares
+func RecoverString(shares []string, opts ...Option) (string, error) {
+	set, err := DecodeShares(shares)
+	if err != nil {
+		return "", err
+	}
+	padded, err := RecoverSecret(set, opts...)
+	for _, s := range set {
+		erase(s)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer erase(padded)
+	if len(padded) < 2 {
+		return "", ErrInvalidShare
+	}
+	n := int(binary.BigEndian.Uint16(padded))
+	if n == 0 || n > len(padded)-2 {
+		return "", ErrInvalidShare
+	}
+	secret := padded[2 : 2+n]
+	if !utf8.Valid(secret) {
+		return "", ErrInvalidUTF8
+	}
+	return string(secret), nil
+}
@@ -0,0 +1,55 @@
+package tss
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSplitString(t *testing.T) {
+	for _, secret := range []string{"x", "correct horse battery staple", strings.Repeat("pässwörd ", 100)} {
+		shares, err := SplitString(secret, 5, 3)
+		if err != nil {
+			failNow(t, err)
+		}
+		got, err := RecoverString([]string{shares[4], shares[0], shares[2]})
+		if err != nil {
+			failNow(t, err)
+		}
+		if got != secret {
+			failNow(t, fmt.Errorf("got %q, want %q", got, secret))
+		}
+	}
+}
+
+func TestSplitStringNFC(t *testing.T) {
+	// e with a combining acute accent recovers as the precomposed é
+	shares, err := SplitString("cafe\u0301", 2, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	got, _ := RecoverString(shares)
+	if got != "caf\u00e9" {
+		failNow(t, fmt.Errorf("got %q", got))
+	}
+}
+
+func TestSplitStringPadding(t *testing.T) {
+	a, _ := SplitString("a", 2, 2)
+	b, _ := SplitString(strings.Repeat("b", StringPadBytes-2), 2, 2)
+	if len(a[0]) != len(b[0]) {
+		failNow(t, fmt.Errorf("share size depends on the passphrase length"))
+	}
+}
+
+func TestSplitStringErrors(t *testing.T) {
+	if _, err := SplitString("", 2, 2); err != ErrSecretRequired {
+		failNow(t, expected(ErrSecretRequired, err))
+	}
+	if _, err := SplitString("\xff", 2, 2); err != ErrInvalidUTF8 {
+		failNow(t, expected(ErrInvalidUTF8, err))
+	}
+	if _, err := SplitString(strings.Repeat("a", MaxSecretBytes), 2, 2); err != ErrSecretTooLarge {
+		failNow(t, expected(ErrSecretTooLarge, err))
+	}
+}
@@ -0,0 +1,7 @@
+//go:build linux && !sparc && !sparc64
+
+package tss
+
+// oTmpfileBase is the __O_TMPFILE of asm-generic, used by every Linux
+// architecture Go supports but sparc
+const oTmpfileBase = 0x400000
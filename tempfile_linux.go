@@ -0,0 +1,57 @@
+package tss
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	// oTmpfile is O_TMPFILE, __O_TMPFILE along O_DIRECTORY, the syscall
+	// package defining neither
+	oTmpfile        = oTmpfileBase | syscall.O_DIRECTORY
+	atFDCWD         = -0x64
+	atSymlinkFollow = 0x400
+)
+
+// openTemp opens an unnamed O_TMPFILE file in dir, linked by linkTemp once
+// complete, so a crash never leaves a partial file behind. Kernels and file
+// systems without it, or systems without /proc to link it, get a named
+// temporary file, linked already.
+func openTemp(dir string, prefix string) (f *os.File, linked bool, err error) {
+	if _, err := os.Stat("/proc/self/fd"); err == nil {
+		fd, err := syscall.Open(dir, syscall.O_WRONLY|syscall.O_CLOEXEC|oTmpfile, ShareFileMode)
+		if err == nil {
+			return os.NewFile(uintptr(fd), filepath.Join(dir, prefix)), false, nil
+		}
+	}
+	f, err = os.CreateTemp(dir, prefix+"*")
+	return f, true, err
+}
+
+// linkTemp gives the file of openTemp a random name in dir
+func linkTemp(f *os.File, dir string, prefix string) (string, error) {
+	from, err := syscall.BytePtrFromString(fmt.Sprintf("/proc/self/fd/%d", f.Fd()))
+	if err != nil {
+		return "", err
+	}
+	cwd := atFDCWD
+	for {
+		name := filepath.Join(dir, prefix+strconv.FormatUint(rand.Uint64(), 36))
+		to, err := syscall.BytePtrFromString(name)
+		if err != nil {
+			return "", err
+		}
+		_, _, errno := syscall.Syscall6(syscall.SYS_LINKAT, uintptr(cwd), uintptr(unsafe.Pointer(from)), uintptr(cwd), uintptr(unsafe.Pointer(to)), atSymlinkFollow, 0)
+		if errno != syscall.EEXIST {
+			if errno != 0 {
+				return "", &os.LinkError{Op: "linkat", Old: f.Name(), New: name, Err: errno}
+			}
+			return name, nil
+		}
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package tss
+
+import "os"
+
+// openTemp opens a named temporary file in dir, linked already
+func openTemp(dir string, prefix string) (f *os.File, linked bool, err error) {
+	f, err = os.CreateTemp(dir, prefix+"*")
+	return f, true, err
+}
+
+// linkTemp returns the name of the file of openTemp
+func linkTemp(f *os.File, dir string, prefix string) (string, error) {
+	return f.Name(), nil
+}
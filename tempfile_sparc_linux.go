@@ -0,0 +1,6 @@
+//go:build linux && (sparc || sparc64)
+
+package tss
+
+// oTmpfileBase is the __O_TMPFILE of sparc
+const oTmpfileBase = 0x2000000
@@ -0,0 +1,133 @@
+package tss
+
+import (
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"strings"
+)
+
+const (
+	// CheckBlockBytes is the number of share bytes covered by each inline check
+	// byte of the text encoding
+	CheckBlockBytes = 16
+	// textGroupChars is the number of hex digits between separators
+	textGroupChars = 8
+)
+
+// ChecksumError reports a share whose checksum does not match
+type ChecksumError struct {
+	// Share is the 1-based position of the share in the input, 0 when a single share was decoded
+	Share int
+	// Offset is the start of the CheckBlockBytes block holding the typo, -1 when it cannot be located
+	Offset int
+}
+
+func (e *ChecksumError) Error() string {
+	s := "share"
+	if e.Share > 0 {
+		s = fmt.Sprintf("share %d", e.Share)
+	}
+	if e.Offset < 0 {
+		return s + " fails checksum"
+	}
+	return fmt.Sprintf("%s fails checksum at approximately byte %d", s, e.Offset)
+}
+
+func (e *ChecksumError) Unwrap() error {
+	return ErrChecksum
+}
+
+// EncodeShare renders a share as grouped hex for manual entry. Every
+// CheckBlockBytes bytes of share are followed by a check byte, and the
+// CRC-32C of the whole share closes the text, so DecodeShare can point out
+// roughly where a typo is.
+func EncodeShare(share Share) string {
+	data := make([]byte, 0, len(share)+len(share)/CheckBlockBytes+5)
+	for i := 0; i < len(share); i += CheckBlockBytes {
+		end := i + CheckBlockBytes
+		if end > len(share) {
+			end = len(share)
+		}
+		data = append(data, share[i:end]...)
+		data = append(data, blockCheck(share[i:end], i))
+	}
+	sum := crc32.Checksum(share, castagnoli)
+	data = append(data, byte(sum>>24), byte(sum>>16), byte(sum>>8), byte(sum))
+	h := hex.EncodeToString(data)
+	groups := make([]string, 0, len(h)/textGroupChars+1)
+	for len(h) > textGroupChars {
+		groups = append(groups, h[:textGroupChars])
+		h = h[textGroupChars:]
+	}
+	groups = append(groups, h)
+	return strings.Join(groups, "-")
+}
+
+// DecodeShare parses a share written by EncodeShare. Case, spaces and dashes
+// are ignored. A typo is reported as a *ChecksumError.
+func DecodeShare(s string) (Share, error) {
+	s = strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, s)
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidShare
+	}
+	if len(data) < 4 {
+		return nil, ErrInvalidShare
+	}
+	data, crc := data[:len(data)-4], data[len(data)-4:]
+	share := make(Share, 0, len(data))
+	for len(data) > 0 {
+		n := CheckBlockBytes
+		if len(data) <= n {
+			n = len(data) - 1
+		}
+		if n < 1 {
+			return nil, ErrInvalidShare
+		}
+		block := data[:n]
+		if data[n] != blockCheck(block, len(share)) {
+			return nil, &ChecksumError{Offset: len(share)}
+		}
+		share = append(share, block...)
+		data = data[n+1:]
+	}
+	sum := crc32.Checksum(share, castagnoli)
+	if crc[0] != byte(sum>>24) || crc[1] != byte(sum>>16) || crc[2] != byte(sum>>8) || crc[3] != byte(sum) {
+		return nil, &ChecksumError{Offset: -1}
+	}
+	if len(share) < MinShareBytes || len(share) > MaxShareBytes {
+		return nil, ErrInvalidShare
+	}
+	return share, nil
+}
+
+// DecodeShares parses one share per line. A typo is reported as a
+// *ChecksumError naming the offending line.
+func DecodeShares(lines []string) (ShareSet, error) {
+	shares := make(ShareSet, len(lines))
+	for i, line := range lines {
+		share, err := DecodeShare(line)
+		if ce, ok := err.(*ChecksumError); ok {
+			ce.Share = i + 1
+			return nil, ce
+		}
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = share
+	}
+	return shares, nil
+}
+
+// blockCheck is the check byte of the block starting at offset, binding the
+// offset so swapped blocks are detected too
+func blockCheck(block []byte, offset int) byte {
+	sum := crc32.Update(crc32.Checksum([]byte{byte(offset >> 8), byte(offset)}, castagnoli), castagnoli, block)
+	return byte(sum)
+}
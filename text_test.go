@@ -0,0 +1,54 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestShareText(t *testing.T) {
+	for _, size := range []int{1, 15, 16, 32, 100} {
+		shares, err := CreateShares(randomBytes(size), 3, 2)
+		if err != nil {
+			failNow(t, err)
+		}
+		text := EncodeShare(shares[0])
+		share, err := DecodeShare(strings.ToUpper(strings.Replace(text, "-", " ", -1)))
+		if err != nil {
+			failNow(t, err)
+		}
+		if !bytes.Equal(share, shares[0]) {
+			failNow(t, fmt.Errorf("share mismatch for size %d", size))
+		}
+	}
+}
+
+func TestShareTextTypo(t *testing.T) {
+	shares, _ := CreateShares(randomBytes(32), 3, 2)
+	lines := []string{EncodeShare(shares[0]), EncodeShare(shares[1]), EncodeShare(shares[2])}
+	// second byte of the second block of the third share, past the first block and its check byte
+	b := []byte(lines[2])
+	pos := 2 * (CheckBlockBytes + 2)
+	pos += pos / textGroupChars
+	if b[pos] == '0' {
+		b[pos] = '1'
+	} else {
+		b[pos] = '0'
+	}
+	lines[2] = string(b)
+	_, err := DecodeShares(lines)
+	ce, ok := err.(*ChecksumError)
+	if !ok {
+		failNow(t, fmt.Errorf("err %v is not a checksum error", err))
+	}
+	if ce.Share != 3 || ce.Offset != CheckBlockBytes {
+		failNow(t, fmt.Errorf("unexpected %v", ce))
+	}
+	if ce.Error() != "share 3 fails checksum at approximately byte 16" {
+		failNow(t, fmt.Errorf("unexpected message %q", ce.Error()))
+	}
+	if _, err := DecodeShare("zz"); err != ErrInvalidShare {
+		failNow(t, expected(ErrInvalidShare, err))
+	}
+}
@@ -0,0 +1,101 @@
+// Package tpm seals tss shares to a TPM 2.0 under a PCR policy, so the share
+// of a custodian machine is only released while the platform measures to a
+// known good state.
+package tpm
+
+import (
+	"fmt"
+
+	"github.com/antik10ud/go-tss"
+)
+
+// PCRSelection is the bank and indexes of the PCRs a share is sealed to
+type PCRSelection struct {
+	// Bank is the TPM_ALG_ID of the PCR bank, 0x000b for SHA-256
+	Bank uint16
+	// PCRs are the PCR indexes, 0 to 23
+	PCRs []int
+}
+
+// DefaultPCRs selects the SHA-256 PCRs of firmware, boot loader and secure
+// boot state: 0, 2, 4 and 7
+var DefaultPCRs = PCRSelection{Bank: 0x000b, PCRs: []int{0, 2, 4, 7}}
+
+// Sealer is a TPM 2.0. Seal creates a sealed data object under the storage
+// root key with a TPM2_PolicyPCR policy over the current values of pcrs, and
+// returns its public and private areas as one blob. Unseal loads the blob
+// and unseals it in a policy session, failing when the PCRs changed. Both
+// are a few commands of a TPM library such as github.com/google/go-tpm.
+type Sealer interface {
+	Seal(pcrs PCRSelection, data []byte) ([]byte, error)
+	Unseal(pcrs PCRSelection, blob []byte) ([]byte, error)
+}
+
+// Store is a tss.ShareStore sealing each share to the TPM before writing the
+// blob to Store. The label is sealed along the share, so a blob moved to
+// another label no longer reads.
+type Store struct {
+	TPM   Sealer
+	PCRs  PCRSelection
+	Store tss.BlobStore
+}
+
+// validate checks the PCR selection
+func (s Store) validate() error {
+	if len(s.PCRs.PCRs) == 0 {
+		return fmt.Errorf("no PCR selected: %w", tss.ErrValidation)
+	}
+	for _, pcr := range s.PCRs.PCRs {
+		if pcr < 0 || pcr > 23 {
+			return fmt.Errorf("PCR %d out of range: %w", pcr, tss.ErrValidation)
+		}
+	}
+	return nil
+}
+
+// Put seals the share and writes the blob to the underlying store
+func (s Store) Put(label string, share tss.Share) error {
+	if !share.Valid() {
+		return tss.ErrInvalidShare
+	}
+	if err := s.validate(); err != nil {
+		return err
+	}
+	data := append(append([]byte(label), 0), share...)
+	defer tss.Wipe(data)
+	blob, err := s.TPM.Seal(s.PCRs, data)
+	if err != nil {
+		return err
+	}
+	return s.Store.PutBlob(label, blob)
+}
+
+// Get reads the blob of label from the underlying store and unseals it
+func (s Store) Get(label string) (tss.Share, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	blob, err := s.Store.GetBlob(label)
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.TPM.Unseal(s.PCRs, blob)
+	if err != nil {
+		return nil, fmt.Errorf("unsealing share %q: %w", label, err)
+	}
+	defer tss.Wipe(data)
+	n := len(label)
+	if len(data) <= n || string(data[:n]) != label || data[n] != 0 {
+		return nil, fmt.Errorf("share %q sealed for another label: %w", label, tss.ErrIntegrity)
+	}
+	share := append(tss.Share{}, data[n+1:]...)
+	if !share.Valid() {
+		return nil, tss.ErrInvalidShare
+	}
+	return share, nil
+}
+
+// Delete removes the blob of label from the underlying store
+func (s Store) Delete(label string) error {
+	return s.Store.Delete(label)
+}
@@ -0,0 +1,83 @@
+package tpm
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/antik10ud/go-tss"
+)
+
+var errPolicy = errors.New("TPM_RC_POLICY_FAIL")
+
+// platform is a Sealer releasing data while its PCRs keep the values they had
+// when sealing
+type platform struct {
+	pcrs [24]byte
+}
+
+func (p *platform) Seal(sel PCRSelection, data []byte) ([]byte, error) {
+	blob := []byte{}
+	for _, i := range sel.PCRs {
+		blob = append(blob, p.pcrs[i])
+	}
+	return append(blob, data...), nil
+}
+
+func (p *platform) Unseal(sel PCRSelection, blob []byte) ([]byte, error) {
+	for j, i := range sel.PCRs {
+		if blob[j] != p.pcrs[i] {
+			return nil, errPolicy
+		}
+	}
+	return append([]byte{}, blob[len(sel.PCRs):]...), nil
+}
+
+func TestStore(t *testing.T) {
+	p := &platform{}
+	store := Store{TPM: p, PCRs: DefaultPCRs, Store: tss.DirStore{Dir: t.TempDir()}}
+	secret := make([]byte, 32)
+	rand.Read(secret)
+	shares, err := tss.CreateShares(secret, 3, 2, tss.WithConsumeSecret(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range shares {
+		if err := store.Put(fmt.Sprint("custodian", i), s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, err := store.Get("custodian1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(s) != string(shares[1]) {
+		t.Fatal("unsealed share mismatch")
+	}
+	// a boot loader update changes PCR 4
+	p.pcrs[4]++
+	if _, err := store.Get("custodian1"); !errors.Is(err, errPolicy) {
+		t.Fatal(err)
+	}
+	p.pcrs[4]--
+	blob, _ := store.Store.GetBlob("custodian1")
+	store.Store.PutBlob("custodian2", blob)
+	if _, err := store.Get("custodian2"); !errors.Is(err, tss.ErrIntegrity) {
+		t.Fatal(err)
+	}
+	if err := (Store{TPM: p, PCRs: PCRSelection{Bank: 0x000b, PCRs: []int{24}}, Store: store.Store}).Put("x", shares[0]); !errors.Is(err, tss.ErrValidation) {
+		t.Fatal(err)
+	}
+	// the largest shares seal although the blobs outgrow them
+	large, err := tss.CreateShares(make([]byte, tss.MaxSecretBytes), 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("large", large[0]); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := store.Get("large"); err != nil || string(s) != string(large[0]) {
+		t.Fatal(err)
+	}
+}
@@ -4,8 +4,12 @@
 package tss
 
 import (
+	"bytes"
+	"context"
 	"crypto/rand"
-	"errors"
+	"fmt"
+	"io"
+	"strings"
 )
 
 const (
@@ -23,24 +27,126 @@ const (
 	MaxShares = 255
 	// MinThreshold specify the minimum number of shares required
 	MinThreshold = 2
+	// MaxIdentifierBytes determine the max size of a split identifier
+	MaxIdentifierBytes = 16
+	// coefficientBatchBytes is the size of the reads of random polynomial
+	// coefficients
+	coefficientBatchBytes = 4096
+	// recoverBlockBytes is the number of secret bytes recovered between
+	// checks of the context
+	recoverBlockBytes = 4096
 )
 
-//Share is a single share
+//Share is a single share, use NewShare, Index and Value rather than relying on its layout
 type Share []byte
 
 //ShareSet is a set of shares used to recover the secret or returned when creating the shares from the secret
 type ShareSet []Share
 
+//ShareParams describe the split a share belongs to, they travel along the share in the encoded formats
+type ShareParams struct {
+	// Identifier tells apart shares of different splits, up to MaxIdentifierBytes
+	Identifier []byte
+	// Threshold is the number of shares required to recover the secret
+	Threshold int
+	// Extensions carry caller metadata, they are ignored by reconstruction
+	Extensions []Extension
+}
+
 var (
-	ErrTooFewShares     = errors.New("too few shares")
-	ErrSecretRequired   = errors.New("some secret is required")
-	ErrSecretTooShort   = errors.New("secret too short")
-	ErrSecretTooLarge   = errors.New("secret too large")
-	ErrTooManyShares    = errors.New("too many shares")
-	ErrInvalidThreshold = errors.New("invalid threshold")
-	ErrInvalidShare     = errors.New("invalid share")
+	ErrTooFewShares      = validationError("too few shares")
+	ErrSecretRequired    = validationError("some secret is required")
+	ErrSecretTooShort    = limitsError("secret too short")
+	ErrSecretTooLarge    = limitsError("secret too large")
+	ErrTooManyShares     = limitsError("too many shares")
+	ErrInvalidThreshold  = validationError("invalid threshold")
+	ErrInvalidShare      = validationError("invalid share")
+	ErrInvalidParams     = validationError("invalid share params")
+	ErrInvalidIndexes    = validationError("invalid share indexes")
+	ErrDuplicateShare    = validationError("duplicate share index")
+	ErrInvalidShareIndex = validationError("invalid share index")
+	ErrShareSize         = validationError("invalid share size")
+	ErrBufferTooSmall    = limitsError("buffer too small for the secret")
+	ErrThresholdNotMet   = validationError("threshold not met")
+	ErrMixedShares       = validationError("shares belong to different splits")
 )
 
+// ThresholdError reports fewer shares than the threshold recorded for them,
+// it matches ErrThresholdNotMet and ErrTooFewShares with errors.Is
+type ThresholdError struct {
+	Need int
+	Have int
+}
+
+func (e *ThresholdError) Error() string {
+	return fmt.Sprintf("threshold not met: %d shares, %d required", e.Have, e.Need)
+}
+
+func (e *ThresholdError) Is(target error) bool {
+	return target == ErrThresholdNotMet || target == ErrTooFewShares
+}
+
+// MixedSharesError reports shares of different splits supplied together, it
+// matches ErrMixedShares with errors.Is
+type MixedSharesError struct {
+	// Identifiers lists the distinct split identifiers found, in the order
+	// of the shares
+	Identifiers [][]byte
+}
+
+func (e *MixedSharesError) Error() string {
+	ids := make([]string, len(e.Identifiers))
+	for i, id := range e.Identifiers {
+		ids[i] = fmt.Sprintf("%x", id)
+	}
+	return fmt.Sprintf("shares belong to %d different splits: %s", len(ids), strings.Join(ids, ", "))
+}
+
+func (e *MixedSharesError) Is(target error) bool {
+	return target == ErrMixedShares
+}
+
+// mixedShares returns a *MixedSharesError when ids are not all equal
+func mixedShares(ids ...[]byte) error {
+	var distinct [][]byte
+next:
+	for _, id := range ids {
+		for _, d := range distinct {
+			if bytes.Equal(d, id) {
+				continue next
+			}
+		}
+		distinct = append(distinct, id)
+	}
+	if len(distinct) < 2 {
+		return nil
+	}
+	return &MixedSharesError{Identifiers: distinct}
+}
+
+// ShareError tells which share of a set failed validation and why, it
+// matches ErrInvalidShare and its Reason with errors.Is
+type ShareError struct {
+	// Position is the 1-based position of the share in the set
+	Position int
+	// Index is the index of the share, 0 when the share is empty
+	Index byte
+	// Reason is ErrShareSize, ErrInvalidShareIndex, ErrDuplicateShare or ErrInvalidShare
+	Reason error
+}
+
+func (e *ShareError) Error() string {
+	return fmt.Sprintf("share %d (index %d): %v", e.Position, e.Index, e.Reason)
+}
+
+func (e *ShareError) Unwrap() error {
+	return e.Reason
+}
+
+func (e *ShareError) Is(target error) bool {
+	return target == ErrInvalidShare
+}
+
 // The expOp "const" is the exponential function table  in GF(256)
 // The operation of raising a field element X to a power i, where i is a
 // positive integer, is denoted as X^i, and it consists of multiplying X
@@ -113,36 +219,17 @@ func add(a byte, b byte) byte {
 	return a ^ b
 }
 
-// The multiplication operation takes two elements X and Y as input and
-// proceeds as follows.  If either X or Y is equal to 0x00, then the
-// operation returns 0x00.
-// this function should inline
-// inline func
-func mul(x byte, y byte) byte {
-	if x == 0 || y == 0 {
-		return 0
-	}
-	return expOp[logOp[x]+logOp[y]]
-}
-
-// The division operation takes a dividend X and a divisor Y as input
-//  and computes X divided by Y as follows.  If X is equal to 0x00, then
-//  the operation returns 0x00.  If Y is equal to 0x00, then the input is
-//  invalid and zero ir returned.
-// inline func
-func div(x byte, y byte) byte {
-	if x == 0 || y == 0 {
-		return 0
-	}
-	return expOp[0xff+logOp[x]-logOp[y]]
-}
-
 // poly is the ith lagrange function, evaluated at zero
 func poly(i int, u []byte) byte {
+	return polyAt(i, u, 0)
+}
+
+// polyAt is the ith lagrange function, evaluated at x
+func polyAt(i int, u []byte, x byte) byte {
 	var r byte = 1
 	for j, m := 0, len(u); j < m; j++ {
 		if j != i {
-			r = mul(r, div(u[j], add(u[j], u[i])))
+			r = mul(r, div(add(x, u[j]), add(u[j], u[i])))
 		}
 	}
 	return r
@@ -152,65 +239,280 @@ func poly(i int, u []byte) byte {
 // two arrays U and V, each consisting of M octets, and returns a single octet
 // note this function does not check if arrays are of the same length
 func interpolate(u []byte, v []byte) byte {
+	return interpolateAt(u, v, 0)
+}
+
+// interpolateAt is interpolate evaluating the polynomial at x instead of zero
+func interpolateAt(u []byte, v []byte, x byte) byte {
 	var r byte
 	for i, m := 0, len(u); i < m; i++ {
-		r = add(r, mul(poly(i, u), v[i]))
+		r = add(r, mul(polyAt(i, u, x), v[i]))
 	}
 	return r
 }
 
 // CreateShares generate a set of 'shares'  from the 'secret' provided. Secret
 // reconstruction will require 'threshold' shares in order to reconstruct correctly a secret.
-// Max secret  len is  65536. Min secret len is 32 bytes
-// Max number of shares is 255
-func CreateShares(secret []byte, sharesCount int, threshold int) (shares ShareSet, err error) {
+// Max secret len is MaxSecretBytes, it can be lowered with WithMaxSecretBytes.
+// Min secret len is MinSecretBytes, it can be raised with WithMinSecretBytes.
+// Max number of shares is 255, it can be lowered with WithMaxShares
+// Shares get indexes 1 to sharesCount, or random ones with WithRandomIndexes.
+func CreateShares(secret []byte, sharesCount int, threshold int, opts ...Option) (shares ShareSet, err error) {
+	return CreateSharesContext(context.Background(), secret, sharesCount, threshold, opts...)
+}
+
+// CreateSharesContext is CreateShares giving up with ctx.Err() when ctx is
+// done, which is checked between blocks of secret bytes
+func CreateSharesContext(ctx context.Context, secret []byte, sharesCount int, threshold int, opts ...Option) (shares ShareSet, err error) {
+	return appendShares(ctx, nil, secret, sharesCount, threshold, newConfig(opts))
+}
+
+// AppendShares is CreateShares appending the shares to dst. The shares reuse
+// the buffers found in the spare capacity of dst when they are large enough,
+// so splitting many secrets into buf[:0] does not allocate once buf has grown:
+//
+//	buf, err = tss.AppendShares(buf[:0], secret, 5, 3)
+//
+// Shares of a previous call reused this way are overwritten.
+func AppendShares(dst ShareSet, secret []byte, sharesCount int, threshold int, opts ...Option) (ShareSet, error) {
+	shares, err := appendShares(context.Background(), dst, secret, sharesCount, threshold, newConfig(opts))
+	if err != nil {
+		return dst, err
+	}
+	return shares, nil
+}
+
+func appendShares(ctx context.Context, dst ShareSet, secret []byte, sharesCount int, threshold int, cfg *config) (ShareSet, error) {
+	if sharesCount < MinShares {
+		return nil, ErrTooFewShares
+	}
+	if sharesCount > cfg.sharesLimit() {
+		return nil, ErrTooManyShares
+	}
+	random, err := cfg.source(secret, threshold)
+	if err != nil {
+		return nil, err
+	}
+	var buf [MaxShares]byte
+	indexes := buf[:sharesCount]
+	if cfg.randomIndexes {
+		if err := randomIndexes(random, indexes); err != nil {
+			return nil, err
+		}
+	} else {
+		for i := range indexes {
+			indexes[i] = byte(i + 1)
+		}
+	}
+	return createShares(ctx, dst, secret, indexes, threshold, cfg, random)
+}
+
+// CreateSharesWithIndexes is CreateShares giving the index of every share,
+// for instance to encode custodian IDs. Indexes must be distinct and not zero.
+func CreateSharesWithIndexes(secret []byte, indexes []byte, threshold int, opts ...Option) (ShareSet, error) {
+	cfg := newConfig(opts)
+	var seen [256]bool
+	for _, x := range indexes {
+		if x == 0 || seen[x] {
+			return nil, ErrInvalidIndexes
+		}
+		seen[x] = true
+	}
+	random, err := cfg.source(secret, threshold)
+	if err != nil {
+		return nil, err
+	}
+	return createShares(context.Background(), nil, secret, indexes, threshold, cfg, random)
+}
+
+// RandomIndexes returns n distinct random non zero indexes, so a single share
+// does not tell how many shares were dealt
+func RandomIndexes(n int) ([]byte, error) {
+	if n < 0 || n > MaxShares {
+		return nil, ErrTooManyShares
+	}
+	indexes := make([]byte, n)
+	if err := randomIndexes(rand.Reader, indexes); err != nil {
+		return nil, err
+	}
+	return indexes, nil
+}
+
+// randomIndexes fills indexes with distinct random non zero indexes
+func randomIndexes(random io.Reader, indexes []byte) error {
+	var perm [MaxShares]byte
+	for i := range perm {
+		perm[i] = byte(i + 1)
+	}
+	// Fisher-Yates shuffle driven by crypto/rand
+	var r [2]byte
+	for i := len(perm) - 1; i > 0; i-- {
+		if _, err := io.ReadFull(random, r[:]); err != nil {
+			return err
+		}
+		// modulo bias is negligible for i < 255 with 16 random bits
+		j := int(uint16(r[0])<<8|uint16(r[1])) % (i + 1)
+		perm[i], perm[j] = perm[j], perm[i]
+	}
+	copy(indexes, perm[:])
+	return nil
+}
+
+// shareIndexes returns the indexes 1 to n, or n distinct random ones when
+// random is set
+func shareIndexes(source io.Reader, n int, random bool) ([]byte, error) {
+	indexes := make([]byte, n)
+	if random {
+		if err := randomIndexes(source, indexes); err != nil {
+			return nil, err
+		}
+		return indexes, nil
+	}
+	for i := range indexes {
+		indexes[i] = byte(i + 1)
+	}
+	return indexes, nil
+}
+
+// createShares appends to dst the shares of secret for the given indexes
+func createShares(ctx context.Context, dst ShareSet, secret []byte, indexes []byte, threshold int, cfg *config, random io.Reader) (shares ShareSet, err error) {
+	sharesCount := len(indexes)
 	if len(secret) == 0 {
 		return nil, ErrSecretRequired
 	}
 	secretSize := len(secret)
-	if secretSize < MinSecretBytes {
+	if secretSize < cfg.minSecretBytes {
 		return nil, ErrSecretTooShort
 	}
-	if secretSize > MaxSecretBytes {
+	if secretSize > cfg.secretLimit() {
 		return nil, ErrSecretTooLarge
 	}
 	if sharesCount < MinShares {
 		return nil, ErrTooFewShares
 	}
-	if sharesCount > MaxShares {
+	if sharesCount > cfg.sharesLimit() {
 		return nil, ErrTooManyShares
 	}
-	if threshold > sharesCount || threshold < MinThreshold {
+	if threshold > sharesCount || threshold < cfg.minThreshold() {
 		return nil, ErrInvalidThreshold
 	}
 
-	shares = make(ShareSet, sharesCount)
+	out := growShares(dst, sharesCount, secretSize+1)
+	shares = out[len(dst):]
+	created := shares
+	defer func() {
+		if err != nil {
+			eraseShares(created)
+		}
+	}()
 	for i := 0; i < sharesCount; i++ {
-		shares[i] = make([]byte, secretSize+1)
-		shares[i][0] = (byte)(i + 1)
+		shares[i][0] = indexes[i]
 	}
 
-	a := make([]byte, threshold)
-	defer erase(a)
-	for i := 0; i < secretSize; i++ {
-		_, err := rand.Read(a)
-		if err != nil {
+	if cfg.xor {
+		if threshold != sharesCount {
+			return nil, ErrInvalidThreshold
+		}
+		if err := xorShares(secret, shares, random); err != nil {
 			return nil, err
 		}
-		a[0] = secret[i]
-		for j := 0; j < sharesCount; j++ {
-			shares[j][i+1] = eval(shares[j][0], a)
+		if cfg.consume {
+			erase(secret)
 		}
+		return out, nil
 	}
-	return shares, nil
+
+	if cfg.parallelism > 1 && secretSize >= 2*parallelBlockBytes {
+		if err := createParallel(ctx, secret, shares, threshold, cfg, random); err != nil {
+			return nil, err
+		}
+		if cfg.consume {
+			erase(secret)
+		}
+		return out, nil
+	}
+
+	// the coefficients of many bytes are drawn in one read, in the order
+	// of the bytes, so batching does not change the shares
+	cols := coefficientBatchBytes / threshold
+	if cols > secretSize {
+		cols = secretSize
+	}
+	var batch, transposed [coefficientBatchBytes]byte
+	coefficients, rows := batch[:cols*threshold], transposed[:cols*threshold]
+	if cfg.allocator != nil {
+		if coefficients, err = cfg.alloc(2 * cols * threshold); err != nil {
+			return nil, err
+		}
+		defer cfg.free(coefficients)
+		coefficients, rows = coefficients[:cols*threshold], coefficients[cols*threshold:]
+	} else {
+		defer erase(coefficients)
+		defer erase(rows)
+	}
+	done := ctx.Done()
+	for i := 0; i < secretSize; i += cols {
+		select {
+		case <-done:
+			return nil, ctx.Err()
+		default:
+		}
+		n := secretSize - i
+		if n > cols {
+			n = cols
+		}
+		if _, err := io.ReadFull(random, coefficients[:n*threshold]); err != nil {
+			return nil, err
+		}
+		evalBatch(cfg.field(), shares, secret[i:i+n], i, coefficients, rows, threshold)
+	}
+	if cfg.consume {
+		erase(secret)
+	}
+	return out, nil
 }
 
-func erase(a []byte) {
-	for i := range a {
-		a[i] = 0xff
+// evalBatch computes the bytes of the shares at offset for the secret bytes
+// in batch, coefficients holding threshold coefficients per byte, the first
+// one being ignored. rows is scratch space as large as coefficients. The
+// polynomials are evaluated by the Eval of the field a whole row of
+// coefficients at a time, so the vector kernels apply.
+func evalBatch(field gf256, shares ShareSet, batch []byte, offset int, coefficients []byte, rows []byte, threshold int) {
+	n := len(batch)
+	for c := 0; c < n; c++ {
+		for k := 1; k < threshold; k++ {
+			rows[(k-1)*n+c] = coefficients[c*threshold+k]
+		}
+	}
+	var cs [MaxShares][]byte
+	cs[0] = batch
+	for k := 1; k < threshold; k++ {
+		cs[k] = rows[(k-1)*n : k*n]
+	}
+	for _, s := range shares {
+		field.Eval(s[1+offset:1+offset+n], s[:1], cs[:threshold])
 	}
 }
 
+// growShares extends dst by n shares of size bytes, reusing the buffers left
+// in its spare capacity
+func growShares(dst ShareSet, n int, size int) ShareSet {
+	if cap(dst)-len(dst) < n {
+		grown := make(ShareSet, len(dst), len(dst)+n)
+		copy(grown[:cap(dst)], dst[:cap(dst)])
+		dst = grown
+	}
+	out := dst[:len(dst)+n]
+	for i := len(dst); i < len(out); i++ {
+		if cap(out[i]) >= size {
+			out[i] = out[i][:size]
+		} else {
+			out[i] = make(Share, size)
+		}
+	}
+	return out
+}
+
 func eval(x byte, a []byte) byte {
 	var r byte
 	var xi byte = 1
@@ -221,51 +523,178 @@ func eval(x byte, a []byte) byte {
 	return r
 }
 
+func (p ShareParams) validate() error {
+	if len(p.Identifier) > MaxIdentifierBytes {
+		return ErrInvalidParams
+	}
+	// threshold 1 is only produced with AllowTrivialThreshold, a container
+	// records it all the same
+	if p.Threshold < 1 || p.Threshold > MaxShares {
+		return ErrInvalidParams
+	}
+	return validateExtensions(p.Extensions)
+}
+
 //RecoverSecret reconstructs a secret from a list of shares.
 //The share at index 0 determines the secret size to be reconstructed, so index 0 is required.
-//All shares must be of the same size.
-func RecoverSecret(shares ShareSet) (secret []byte, err error) {
+//All shares must be of the same size and have distinct non zero indexes, index 0 being reserved for the secret.
+//A share failing validation is reported as a *ShareError.
+func RecoverSecret(shares ShareSet, opts ...Option) (secret []byte, err error) {
+	return RecoverSecretContext(context.Background(), shares, opts...)
+}
+
+// RecoverSecretContext is RecoverSecret giving up with ctx.Err() when ctx is
+// done, which is checked between blocks of secret bytes
+func RecoverSecretContext(ctx context.Context, shares ShareSet, opts ...Option) (secret []byte, err error) {
+	cfg := newConfig(opts)
+	secretSize, err := checkShares(shares, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if secret, err = cfg.alloc(secretSize); err != nil {
+		return nil, err
+	}
+	if cfg.xor {
+		xorRecover(secret, shares)
+		return secret, nil
+	}
+	if cfg.parallelism > 1 && secretSize >= 2*parallelBlockBytes {
+		err = recoverParallel(ctx, secret, shares, cfg)
+	} else if cfg.allocator == nil && !cfg.hardened {
+		err = recoverInto(ctx, secret, shares, 0)
+	} else {
+		var scratch []byte
+		if scratch, err = cfg.alloc(2 * len(shares)); err == nil {
+			err = recoverColumns(ctx, cfg.field(), secret, shares, 0, scratch[:len(shares)], scratch[len(shares):])
+			cfg.free(scratch)
+		}
+	}
+	if err != nil {
+		cfg.free(secret)
+		return nil, err
+	}
+	return secret, nil
+}
+
+// RecoverSecretInto is RecoverSecret writing the secret into dst, which must
+// be large enough, and returning its size. It does not allocate unless the
+// shares are invalid.
+func RecoverSecretInto(dst []byte, shares ShareSet) (int, error) {
+	cfg := config{minSecretBytes: MinSecretBytes}
+	secretSize, err := checkShares(shares, &cfg)
+	if err != nil {
+		return 0, err
+	}
+	if len(dst) < secretSize {
+		return 0, ErrBufferTooSmall
+	}
+	if err := recoverInto(context.Background(), dst[:secretSize], shares, 0); err != nil {
+		return 0, err
+	}
+	return secretSize, nil
+}
+
+// EvaluateAt interpolates the polynomials of a set of shares at x and returns
+// one byte per secret byte: x = 0 gives the secret, any other x the value of
+// the share at index x. It is the building block of share repair, resharing
+// and verification. The set must hold at least threshold shares of the split.
+func EvaluateAt(shares ShareSet, x byte, opts ...Option) ([]byte, error) {
+	secretSize, err := checkShares(shares, newConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	value := make([]byte, secretSize)
+	if err := recoverInto(context.Background(), value, shares, x); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// checkShares validates a set of shares and returns the size of the secret
+// they recover to
+func checkShares(shares ShareSet, cfg *config) (int, error) {
+	if err := cfg.checkStrict(false); err != nil {
+		return 0, err
+	}
 	sharesCount := len(shares)
-	if sharesCount < MinShares {
-		return nil, ErrTooFewShares
+	if sharesCount < cfg.threshold {
+		return 0, &ThresholdError{Need: cfg.threshold, Have: sharesCount}
 	}
-	if sharesCount > MaxShares {
-		return nil, ErrTooManyShares
+	if sharesCount < cfg.minThreshold() {
+		return 0, ErrTooFewShares
+	}
+	if sharesCount > cfg.sharesLimit() {
+		return 0, ErrTooManyShares
 	}
 	shareSize := len(shares[0])
 
-	if shareSize < MinShareBytes {
-		return nil, ErrInvalidShare
+	if shareSize < cfg.minSecretBytes+1 {
+		return 0, &ShareError{Position: 1, Index: shares[0].Index(), Reason: ErrShareSize}
 	}
 
-	if shareSize > MaxShareBytes {
-		return nil, ErrInvalidShare
+	if shareSize > cfg.secretLimit()+1 {
+		return 0, &ShareError{Position: 1, Index: shares[0].Index(), Reason: ErrShareSize}
 	}
 
 	for i := 1; i < sharesCount; i++ {
 		if len(shares[i]) != shareSize {
-			return nil, ErrInvalidShare
+			return 0, &ShareError{Position: i + 1, Index: shares[i].Index(), Reason: ErrShareSize}
 		}
 	}
 
-	u := make([]byte, sharesCount)
-	defer erase(u)
-
+	// interpolation divides by the difference of the indexes, a repeated
+	// index would silently give a wrong secret
+	var seen [256]bool
 	for i := 0; i < sharesCount; i++ {
-		u[i] = shares[i][0]
+		x := shares[i][0]
+		if x == 0 {
+			return 0, &ShareError{Position: i + 1, Reason: ErrInvalidShareIndex}
+		}
+		if seen[x] {
+			return 0, &ShareError{Position: i + 1, Index: x, Reason: ErrDuplicateShare}
+		}
+		seen[x] = true
 	}
+	return shareSize - 1, nil
+}
 
-	v := make([]byte, sharesCount)
+// recoverInto interpolates at x every byte of secret from shares checked by
+// checkShares, x being 0 for the secret itself
+func recoverInto(ctx context.Context, secret []byte, shares ShareSet, x byte) error {
+	sharesCount := len(shares)
+	var ua, va [MaxShares]byte
+	u, v := ua[:sharesCount], va[:sharesCount]
+	defer erase(u)
 	defer erase(v)
+	return recoverColumns(ctx, gf256{}, secret, shares, x, u, v)
+}
 
-	secretSize := shareSize - 1
-	secret = make([]byte, secretSize)
-
-	for j := 0; j < secretSize; j++ {
-		for i := 0; i < sharesCount; i++ {
-			v[i] = shares[i][j+1]
+// recoverColumns is recoverInto over field using u and v, len(shares) bytes
+// each, to hold the indexes and the Lagrange weights of the shares at x. The
+// secret is the sum of the shares scaled by their weights, combined by the
+// field a block of bytes at a time so the vector kernels apply.
+func recoverColumns(ctx context.Context, field gf256, secret []byte, shares ShareSet, x byte, u []byte, v []byte) error {
+	for i, s := range shares {
+		u[i] = s[0]
+	}
+	field.weights(v, u, x)
+	var ys [MaxShares][]byte
+	done := ctx.Done()
+	for start := 0; start < len(secret); start += recoverBlockBytes {
+		select {
+		case <-done:
+			erase(secret)
+			return ctx.Err()
+		default:
+		}
+		end := start + recoverBlockBytes
+		if end > len(secret) {
+			end = len(secret)
 		}
-		secret[j] = interpolate(u, v)
+		for i, s := range shares {
+			ys[i] = s[1+start : 1+end]
+		}
+		field.combine(secret[start:end], v, ys[:len(shares)])
 	}
-	return secret, nil
+	return nil
 }
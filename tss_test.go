@@ -2,10 +2,13 @@ package tss
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"github.com/antik10ud/go-comb/comb"
+	mrand "math/rand"
 	"testing"
 )
 
@@ -27,6 +30,49 @@ func TestCreateSharesErrors(t *testing.T) {
 	testCaseCreateExpect(t, 32, MinShares-1, 3, ErrTooFewShares)
 }
 
+func TestCreateSharesWithIndexes(t *testing.T) {
+	secret := randomBytes(32)
+	indexes := []byte{7, 42, 200, 255}
+	shares, err := CreateSharesWithIndexes(secret, indexes, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	for i, s := range shares {
+		if s[0] != indexes[i] {
+			failNow(t, fmt.Errorf("share %d has index %d, want %d", i, s[0], indexes[i]))
+		}
+	}
+	testRecover(t, secret, shares[1:])
+	for _, bad := range [][]byte{{1, 0, 2}, {1, 2, 1}} {
+		if _, err := CreateSharesWithIndexes(secret, bad, 2); err != ErrInvalidIndexes {
+			failNow(t, expected(ErrInvalidIndexes, err))
+		}
+	}
+	if _, err := CreateSharesWithIndexes(secret, []byte{1}, 2); err != ErrTooFewShares {
+		failNow(t, expected(ErrTooFewShares, err))
+	}
+	if _, err := CreateSharesWithIndexes(secret, []byte{1, 2}, 3); err != ErrInvalidThreshold {
+		failNow(t, expected(ErrInvalidThreshold, err))
+	}
+}
+
+func TestRandomIndexes(t *testing.T) {
+	indexes, err := RandomIndexes(MaxShares)
+	if err != nil {
+		failNow(t, err)
+	}
+	var seen [256]bool
+	for _, x := range indexes {
+		if x == 0 || seen[x] {
+			failNow(t, fmt.Errorf("bad index %d", x))
+		}
+		seen[x] = true
+	}
+	if _, err := RandomIndexes(MaxShares + 1); err != ErrTooManyShares {
+		failNow(t, expected(ErrTooManyShares, err))
+	}
+}
+
 func TestCaseCreateThreshold1(t *testing.T) {
 	secret := randomBytes(32)
 	_, err := CreateShares(secret, 3, 1)
@@ -75,9 +121,34 @@ func TestRecoverErrors(t *testing.T) {
 
 }
 
+func TestRecoverShareError(t *testing.T) {
+	shares, _ := CreateShares(randomBytes(32), 5, 3)
+	zero := append(Share{}, shares[1]...)
+	zero[0] = 0
+	for _, c := range []struct {
+		set      ShareSet
+		position int
+		index    byte
+		reason   error
+	}{
+		{ShareSet{shares[0], zero}, 2, 0, ErrInvalidShareIndex},
+		{ShareSet{shares[2], shares[0], shares[4], shares[2]}, 4, 3, ErrDuplicateShare},
+		{ShareSet{shares[0], shares[1], shares[2][:20]}, 3, 3, ErrShareSize},
+	} {
+		_, err := RecoverSecret(c.set)
+		se, ok := err.(*ShareError)
+		if !ok || !errors.Is(err, c.reason) || !errors.Is(err, ErrInvalidShare) {
+			failNow(t, expected(c.reason, err))
+		}
+		if se.Position != c.position || se.Index != c.index {
+			failNow(t, fmt.Errorf("unexpected error %v", err))
+		}
+	}
+}
+
 func testCaseRecoverExpect(t *testing.T, shares ShareSet, expect error) {
 	_, err := RecoverSecret(shares)
-	if err != expect {
+	if err != expect && !errors.Is(err, expect) {
 		failNow(t, expected(expect, err))
 	}
 }
@@ -162,7 +233,7 @@ func testSharesComb(t *testing.T, sharesCount int, threshold int) {
 		if v == nil {
 			break
 		}
-		testRecover(t, secret, subShares(shares, *v))
+		testRecover(t, secret, shares.Subset(*v...))
 	}
 
 }
@@ -177,14 +248,6 @@ func testRecover(t *testing.T, secret []byte, shares ShareSet) {
 	}
 }
 
-func subShares(shares ShareSet, pickList []int) ShareSet {
-	newShares := make(ShareSet, len(pickList))
-	for i, pick := range pickList {
-		newShares[i] = shares[pick]
-	}
-	return newShares
-}
-
 func expected(exp error, actual error) error {
 	return fmt.Errorf("err '%s' but expected '%s'", actual, exp)
 }
@@ -206,3 +269,131 @@ func randomBytes(i int) []byte {
 	rand.Read(v)
 	return v
 }
+
+func TestContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	shares, err := CreateSharesContext(ctx, randomBytes(32), 3, 2)
+	if err != nil {
+		failNow(t, err)
+	}
+	cancel()
+	if _, err := CreateSharesContext(ctx, randomBytes(32), 3, 2); err != context.Canceled {
+		failNow(t, expected(context.Canceled, err))
+	}
+	if _, err := RecoverSecretContext(ctx, shares); err != context.Canceled {
+		failNow(t, expected(context.Canceled, err))
+	}
+}
+
+func TestRecoverSecretInto(t *testing.T) {
+	secret := randomBytes(32)
+	shares, _ := CreateShares(secret, 5, 3)
+	dst := make([]byte, 64)
+	n, err := RecoverSecretInto(dst, shares[1:4])
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(dst[:n], secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+	if _, err := RecoverSecretInto(dst[:31], shares); err != ErrBufferTooSmall {
+		failNow(t, expected(ErrBufferTooSmall, err))
+	}
+	if raceEnabled {
+		t.Skip("allocations are not counted under the race detector")
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		RecoverSecretInto(dst, shares[1:4])
+	})
+	if allocs != 0 {
+		failNow(t, fmt.Errorf("%v allocations", allocs))
+	}
+}
+
+func BenchmarkRecoverSecretInto(b *testing.B) {
+	shares, _ := CreateShares(randomBytes(32), 5, 3)
+	dst := make([]byte, 32)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		RecoverSecretInto(dst, shares[:3])
+	}
+}
+
+func TestAppendShares(t *testing.T) {
+	secret := randomBytes(32)
+	buf, err := AppendShares(nil, secret, 5, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	testRecover(t, secret, buf[:3])
+	first := &buf[0][0]
+	other := randomBytes(32)
+	buf, err = AppendShares(buf[:0], other, 5, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	if &buf[0][0] != first {
+		failNow(t, fmt.Errorf("share buffer not reused"))
+	}
+	testRecover(t, other, buf[2:])
+	buf, _ = AppendShares(buf, secret, 2, 2)
+	if len(buf) != 7 {
+		failNow(t, fmt.Errorf("got %d shares", len(buf)))
+	}
+	testRecover(t, secret, buf[5:])
+	if got, err := AppendShares(buf[:1], secret, 1, 2); err != ErrTooFewShares || len(got) != 1 {
+		failNow(t, expected(ErrTooFewShares, err))
+	}
+	if raceEnabled {
+		t.Skip("allocations are not counted under the race detector")
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = AppendShares(buf[:0], secret, 5, 3)
+	})
+	// the options only
+	if allocs > 2 {
+		failNow(t, fmt.Errorf("%v allocations", allocs))
+	}
+}
+
+func TestThresholdNotMet(t *testing.T) {
+	shares, _ := CreateShares(randomBytes(32), 5, 3)
+	_, err := RecoverSecret(shares[:2], WithThreshold(3))
+	te, ok := err.(*ThresholdError)
+	if !ok || te.Need != 3 || te.Have != 2 || !errors.Is(err, ErrThresholdNotMet) || !errors.Is(err, ErrTooFewShares) {
+		failNow(t, expected(ErrThresholdNotMet, err))
+	}
+	if _, err := RecoverSecret(shares[:3], WithThreshold(3)); err != nil {
+		failNow(t, err)
+	}
+}
+
+func TestCoefficientBatches(t *testing.T) {
+	// the shares must not depend on how the coefficients are batched
+	secret := randomBytes(3*coefficientBatchBytes + 5)
+	for _, threshold := range []int{2, 7, 20} {
+		shares, err := CreateShares(secret, 20, threshold, WithRand(mrand.New(mrand.NewSource(int64(threshold)))))
+		if err != nil {
+			failNow(t, err)
+		}
+		random := mrand.New(mrand.NewSource(int64(threshold)))
+		a := make([]byte, threshold)
+		for i, b := range secret {
+			random.Read(a)
+			a[0] = b
+			for _, s := range shares {
+				if s[i+1] != eval(s[0], a) {
+					failNow(t, fmt.Errorf("threshold %d: byte %d of share %d differs", threshold, i, s[0]))
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkCreateSharesLarge(b *testing.B) {
+	secret := randomBytes(MaxSecretBytes)
+	b.SetBytes(int64(len(secret)))
+	for i := 0; i < b.N; i++ {
+		CreateShares(secret, 5, 3)
+	}
+}
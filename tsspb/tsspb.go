@@ -0,0 +1,109 @@
+// Package tsspb holds the Go types of the messages defined in tss.proto and
+// the converters from and to the tss package types.
+// The types marshal to the protobuf wire format by themselves, so they can be
+// exchanged with any implementation generated from tss.proto without pulling a
+// protobuf runtime in.
+package tsspb
+
+import (
+	"fmt"
+
+	tss "github.com/antik10ud/go-tss"
+)
+
+var (
+	ErrInvalidMessage = fmt.Errorf("invalid protobuf message: %w", tss.ErrValidation)
+)
+
+// Share mirrors the tss.v1.Share message
+type Share struct {
+	Index      uint32
+	Value      []byte
+	Identifier []byte
+	Threshold  uint32
+	Extensions []*Extension
+}
+
+// Extension mirrors the tss.v1.Extension message
+type Extension struct {
+	Type  uint32
+	Value []byte
+}
+
+// ShareSet mirrors the tss.v1.ShareSet message
+type ShareSet struct {
+	Shares []*Share
+}
+
+// ToProto converts a share and its params to a message. A zero threshold in
+// params is left out.
+func ToProto(share tss.Share, params tss.ShareParams) (*Share, error) {
+	if !share.Valid() {
+		return nil, tss.ErrInvalidShare
+	}
+	m := &Share{
+		Index:      uint32(share.Index()),
+		Value:      append([]byte{}, share.Value()...),
+		Identifier: append([]byte{}, params.Identifier...),
+		Threshold:  uint32(params.Threshold),
+	}
+	for _, e := range params.Extensions {
+		m.Extensions = append(m.Extensions, &Extension{Type: uint32(e.Type), Value: append([]byte{}, e.Value...)})
+	}
+	return m, nil
+}
+
+// FromProto converts a message back to a share and its params
+func FromProto(m *Share) (tss.Share, tss.ShareParams, error) {
+	if m == nil || m.Index == 0 || m.Index > tss.MaxShares || len(m.Value) < tss.MinSecretBytes || len(m.Value) > tss.MaxSecretBytes {
+		return nil, tss.ShareParams{}, tss.ErrInvalidShare
+	}
+	if len(m.Identifier) > tss.MaxIdentifierBytes || m.Threshold > tss.MaxShares {
+		return nil, tss.ShareParams{}, tss.ErrInvalidParams
+	}
+	share := tss.NewShare(byte(m.Index), m.Value)
+	params := tss.ShareParams{Identifier: append([]byte(nil), m.Identifier...), Threshold: int(m.Threshold)}
+	for _, e := range m.Extensions {
+		if e == nil || e.Type > 0xff {
+			return nil, tss.ShareParams{}, tss.ErrInvalidExtension
+		}
+		params.Extensions = append(params.Extensions, tss.Extension{Type: byte(e.Type), Value: append([]byte(nil), e.Value...)})
+	}
+	return share, params, nil
+}
+
+// ShareSetToProto converts a share set whose shares all have params
+func ShareSetToProto(shares tss.ShareSet, params tss.ShareParams) (*ShareSet, error) {
+	m := &ShareSet{Shares: make([]*Share, len(shares))}
+	for i, share := range shares {
+		s, err := ToProto(share, params)
+		if err != nil {
+			return nil, err
+		}
+		m.Shares[i] = s
+	}
+	return m, nil
+}
+
+// ShareSetFromProto converts a message back to a share set, the params are
+// taken from the first share and must be the same for all of them
+func ShareSetFromProto(m *ShareSet) (tss.ShareSet, tss.ShareParams, error) {
+	if m == nil || len(m.Shares) == 0 {
+		return nil, tss.ShareParams{}, tss.ErrTooFewShares
+	}
+	shares := make(tss.ShareSet, len(m.Shares))
+	var params tss.ShareParams
+	for i, s := range m.Shares {
+		share, p, err := FromProto(s)
+		if err != nil {
+			return nil, tss.ShareParams{}, err
+		}
+		if i == 0 {
+			params = p
+		} else if string(p.Identifier) != string(params.Identifier) || p.Threshold != params.Threshold {
+			return nil, tss.ShareParams{}, tss.ErrInvalidParams
+		}
+		shares[i] = share
+	}
+	return shares, params, nil
+}
@@ -0,0 +1,74 @@
+package tsspb
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	tss "github.com/antik10ud/go-tss"
+)
+
+func TestWire(t *testing.T) {
+	m := &Share{Index: 3, Value: []byte{0xaa, 0xbb}, Identifier: []byte("id"), Threshold: 2}
+	// as encoded by protoc generated code
+	want := "080312" + "02aabb" + "1a02" + hex.EncodeToString([]byte("id")) + "2002"
+	if got := hex.EncodeToString(m.Marshal()); got != want {
+		t.Fatalf("wire %s, want %s", got, want)
+	}
+	var u Share
+	// unknown field 7 is skipped
+	if err := u.Unmarshal(append(m.Marshal(), 0x38, 0x01)); err != nil {
+		t.Fatal(err)
+	}
+	if u.Index != 3 || !bytes.Equal(u.Value, m.Value) || !bytes.Equal(u.Identifier, m.Identifier) || u.Threshold != 2 {
+		t.Fatalf("unexpected %+v", u)
+	}
+	if err := u.Unmarshal([]byte{0x12, 0x05, 0x00}); err != ErrInvalidMessage {
+		t.Fatal(err)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	secret := []byte("a short secret")
+	shares, err := tss.CreateShares(secret, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := tss.ShareParams{Identifier: []byte("id"), Threshold: 3, Extensions: []tss.Extension{{Type: tss.ExtCustodian, Value: []byte("bob")}}}
+	m, err := ShareSetToProto(shares[1:4], params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var u ShareSet
+	if err := u.Unmarshal(m.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	back, p, err := ShareSetFromProto(&u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := p.Extension(tss.ExtCustodian); p.Threshold != 3 || string(p.Identifier) != "id" || string(v) != "bob" {
+		t.Fatalf("unexpected params %+v", p)
+	}
+	recovered, err := tss.RecoverSecret(back)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Fatal("secret mismatch")
+	}
+	u.Shares[1].Threshold = 4
+	if _, _, err := ShareSetFromProto(&u); err != tss.ErrInvalidParams {
+		t.Fatal(err)
+	}
+	if _, _, err := FromProto(&Share{Index: 0, Value: []byte{1}}); err != tss.ErrInvalidShare {
+		t.Fatal(err)
+	}
+}
+
+func TestInvalidMessageCategory(t *testing.T) {
+	if !errors.Is(ErrInvalidMessage, tss.ErrValidation) {
+		t.Fatal("ErrInvalidMessage is not a validation error")
+	}
+}
@@ -0,0 +1,167 @@
+package tsspb
+
+import "encoding/binary"
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// Marshal encodes the message in the protobuf wire format
+func (m *Share) Marshal() []byte {
+	var b []byte
+	if m.Index != 0 {
+		b = appendVarint(appendTag(b, 1, wireVarint), uint64(m.Index))
+	}
+	if len(m.Value) > 0 {
+		b = appendBytes(appendTag(b, 2, wireBytes), m.Value)
+	}
+	if len(m.Identifier) > 0 {
+		b = appendBytes(appendTag(b, 3, wireBytes), m.Identifier)
+	}
+	if m.Threshold != 0 {
+		b = appendVarint(appendTag(b, 4, wireVarint), uint64(m.Threshold))
+	}
+	for _, e := range m.Extensions {
+		b = appendBytes(appendTag(b, 5, wireBytes), e.Marshal())
+	}
+	return b
+}
+
+// Unmarshal decodes a message in the protobuf wire format, unknown fields are skipped
+func (m *Share) Unmarshal(b []byte) error {
+	*m = Share{}
+	return walk(b, func(field int, wire int, v uint64, data []byte) error {
+		switch {
+		case field == 1 && wire == wireVarint:
+			m.Index = uint32(v)
+		case field == 2 && wire == wireBytes:
+			m.Value = append([]byte{}, data...)
+		case field == 3 && wire == wireBytes:
+			m.Identifier = append([]byte{}, data...)
+		case field == 4 && wire == wireVarint:
+			m.Threshold = uint32(v)
+		case field == 5 && wire == wireBytes:
+			e := &Extension{}
+			if err := e.Unmarshal(data); err != nil {
+				return err
+			}
+			m.Extensions = append(m.Extensions, e)
+		case field <= 5:
+			return ErrInvalidMessage
+		}
+		return nil
+	})
+}
+
+// Marshal encodes the message in the protobuf wire format
+func (m *Extension) Marshal() []byte {
+	var b []byte
+	if m.Type != 0 {
+		b = appendVarint(appendTag(b, 1, wireVarint), uint64(m.Type))
+	}
+	if len(m.Value) > 0 {
+		b = appendBytes(appendTag(b, 2, wireBytes), m.Value)
+	}
+	return b
+}
+
+// Unmarshal decodes a message in the protobuf wire format, unknown fields are skipped
+func (m *Extension) Unmarshal(b []byte) error {
+	*m = Extension{}
+	return walk(b, func(field int, wire int, v uint64, data []byte) error {
+		switch {
+		case field == 1 && wire == wireVarint:
+			m.Type = uint32(v)
+		case field == 2 && wire == wireBytes:
+			m.Value = append([]byte{}, data...)
+		case field <= 2:
+			return ErrInvalidMessage
+		}
+		return nil
+	})
+}
+
+// Marshal encodes the message in the protobuf wire format
+func (m *ShareSet) Marshal() []byte {
+	var b []byte
+	for _, s := range m.Shares {
+		b = appendBytes(appendTag(b, 1, wireBytes), s.Marshal())
+	}
+	return b
+}
+
+// Unmarshal decodes a message in the protobuf wire format, unknown fields are skipped
+func (m *ShareSet) Unmarshal(b []byte) error {
+	*m = ShareSet{}
+	return walk(b, func(field int, wire int, v uint64, data []byte) error {
+		switch {
+		case field == 1 && wire == wireBytes:
+			s := &Share{}
+			if err := s.Unmarshal(data); err != nil {
+				return err
+			}
+			m.Shares = append(m.Shares, s)
+		case field == 1:
+			return ErrInvalidMessage
+		}
+		return nil
+	})
+}
+
+func appendTag(b []byte, field int, wire int) []byte {
+	return appendVarint(b, uint64(field)<<3|uint64(wire))
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	return binary.AppendUvarint(b, v)
+}
+
+func appendBytes(b []byte, data []byte) []byte {
+	return append(appendVarint(b, uint64(len(data))), data...)
+}
+
+// walk calls fn for every field of the message, with the value of varints or
+// the content of length delimited fields
+func walk(b []byte, fn func(field int, wire int, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 || tag>>3 == 0 || tag>>3 > 1<<29 {
+			return ErrInvalidMessage
+		}
+		b = b[n:]
+		field, wire := int(tag>>3), int(tag&7)
+		var v uint64
+		var data []byte
+		switch wire {
+		case wireVarint:
+			v, n = binary.Uvarint(b)
+			if n <= 0 {
+				return ErrInvalidMessage
+			}
+		case wireFixed64:
+			n = 8
+		case wireFixed32:
+			n = 4
+		case wireBytes:
+			l, m := binary.Uvarint(b)
+			if m <= 0 || l > uint64(len(b)-m) {
+				return ErrInvalidMessage
+			}
+			data = b[m : m+int(l)]
+			n = m + int(l)
+		default:
+			return ErrInvalidMessage
+		}
+		if n > len(b) {
+			return ErrInvalidMessage
+		}
+		if err := fn(field, wire, v, data); err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+// Package unseal runs the unseal workflow of Vault over tss shares: a sealed
+// service registers a callback, custodians submit their shares one at a
+// time, through Submit or the HTTP API of Handler, and once the quorum is
+// met the secret is recovered in memory and handed to the callback.
+package unseal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/antik10ud/go-tss"
+)
+
+// maxRequestBytes bounds the bodies of the unseal requests
+const maxRequestBytes = 64 << 10
+
+var (
+	ErrUnsealed     = fmt.Errorf("already unsealed: %w", tss.ErrValidation)
+	ErrNonceChanged = fmt.Errorf("unseal nonce does not match the running attempt: %w", tss.ErrValidation)
+)
+
+// Status is the state of an Unsealer, as reported by Vault
+type Status struct {
+	Sealed    bool `json:"sealed"`
+	Threshold int  `json:"t"`
+	Progress  int  `json:"progress"`
+	// Nonce identifies the running unseal attempt, it changes with every
+	// reset so custodians do not add to an attempt they did not start
+	Nonce string `json:"nonce"`
+}
+
+// Unsealer collects shares until the quorum is met and delivers the secret
+// to its callback. It is safe for concurrent use.
+type Unsealer struct {
+	mu        sync.Mutex
+	opts      []tss.Option
+	threshold int
+	unseal    func(secret []byte) error
+	combiner  *tss.Combiner
+	sealed    bool
+	nonce     string
+}
+
+// NewUnsealer returns a sealed Unsealer waiting for threshold shares. The
+// recovered secret is passed to unseal and wiped once it returns: unseal
+// copies what it keeps. When unseal fails, with a secret the service does
+// not accept, the attempt is reset and the Unsealer stays sealed.
+func NewUnsealer(threshold int, unseal func(secret []byte) error, opts ...tss.Option) *Unsealer {
+	u := &Unsealer{
+		opts:      append(append([]tss.Option{}, opts...), tss.WithThreshold(threshold)),
+		threshold: threshold,
+		unseal:    unseal,
+	}
+	u.combiner = tss.NewCombiner(u.opts...)
+	u.seal()
+	return u
+}
+
+// seal starts a new attempt, the caller holding the lock
+func (u *Unsealer) seal() {
+	u.combiner.Reset()
+	u.sealed = true
+	var nonce [16]byte
+	rand.Read(nonce[:])
+	u.nonce = hex.EncodeToString(nonce[:])
+}
+
+// status returns the status, the caller holding the lock
+func (u *Unsealer) status() Status {
+	if !u.sealed {
+		return Status{Threshold: u.threshold}
+	}
+	return Status{Sealed: true, Threshold: u.threshold, Progress: u.combiner.Len(), Nonce: u.nonce}
+}
+
+// Status returns the state of the Unsealer
+func (u *Unsealer) Status() Status {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.status()
+}
+
+// Submit adds a share to the running attempt, nonce being the one of the
+// attempt or empty. Once the quorum is met the secret is recovered and
+// delivered: the status returned tells whether the service is unsealed.
+// Invalid shares are refused without resetting the attempt.
+func (u *Unsealer) Submit(share tss.Share, nonce string) (Status, error) {
+	return u.submit(nonce, func() error {
+		return u.combiner.AddShare(share)
+	})
+}
+
+// SubmitWithParams is Submit for a share decoded along its params, such as
+// from a container
+func (u *Unsealer) SubmitWithParams(share tss.Share, params tss.ShareParams, nonce string) (Status, error) {
+	return u.submit(nonce, func() error {
+		return u.combiner.AddShareWithParams(share, params)
+	})
+}
+
+func (u *Unsealer) submit(nonce string, add func() error) (Status, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if !u.sealed {
+		return u.status(), ErrUnsealed
+	}
+	if nonce != "" && nonce != u.nonce {
+		return u.status(), ErrNonceChanged
+	}
+	if err := add(); err != nil {
+		return u.status(), err
+	}
+	if !u.combiner.CanRecover() {
+		return u.status(), nil
+	}
+	secret, err := u.combiner.Recover()
+	if err == nil {
+		err = u.unseal(secret)
+		tss.Wipe(secret)
+	}
+	if err != nil {
+		u.seal()
+		return u.status(), err
+	}
+	u.combiner.Reset()
+	u.sealed = false
+	return u.status(), nil
+}
+
+// Reset discards the shares of the running attempt and starts a new one
+func (u *Unsealer) Reset() Status {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.sealed {
+		u.seal()
+	}
+	return u.status()
+}
+
+// Seal seals the Unsealer again, for the service to be unsealed anew
+func (u *Unsealer) Seal() Status {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.seal()
+	return u.status()
+}
+
+// unsealRequest is the body of PUT /sys/unseal, Key being a share in the
+// text encoding of tss.EncodeShare
+type unsealRequest struct {
+	Key   string `json:"key"`
+	Reset bool   `json:"reset"`
+	Nonce string `json:"nonce"`
+}
+
+// Handler serves the HTTP API of Vault for unsealing:
+//
+//	GET /sys/seal-status returns the Status
+//	PUT /sys/unseal submits {"key": share, "nonce": nonce} or resets with
+//	{"reset": true}, and returns the Status
+//
+// Errors are returned as {"errors": [message]}. Serve it over TLS, shares
+// travel in the request bodies.
+func (u *Unsealer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sys/seal-status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		writeJSON(w, http.StatusOK, u.Status())
+	})
+	mux.HandleFunc("/sys/unseal", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut && r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		var req unsealRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("invalid request body"))
+			return
+		}
+		if req.Reset {
+			writeJSON(w, http.StatusOK, u.Reset())
+			return
+		}
+		share, err := tss.DecodeShare(req.Key)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		status, err := u.Submit(share, req.Nonce)
+		tss.Wipe(share)
+		switch {
+		case errors.Is(err, tss.ErrValidation):
+			writeError(w, http.StatusBadRequest, err)
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, err)
+		default:
+			writeJSON(w, http.StatusOK, status)
+		}
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string][]string{"errors": {err.Error()}})
+}
@@ -0,0 +1,119 @@
+package unseal
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/antik10ud/go-tss"
+)
+
+func split(t *testing.T, secret []byte) tss.ShareSet {
+	shares, err := tss.CreateShares(secret, 5, 3, tss.WithConsumeSecret(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return shares
+}
+
+func TestUnsealer(t *testing.T) {
+	secret := make([]byte, 32)
+	rand.Read(secret)
+	shares := split(t, secret)
+	var unsealed []byte
+	u := NewUnsealer(3, func(s []byte) error {
+		if !bytes.Equal(s, secret) {
+			return errors.New("wrong master key")
+		}
+		unsealed = append([]byte{}, s...)
+		return nil
+	})
+	status := u.Status()
+	if !status.Sealed || status.Threshold != 3 || status.Progress != 0 || status.Nonce == "" {
+		t.Fatalf("status %+v", status)
+	}
+	if _, err := u.Submit(shares[0], ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.Submit(shares[1], "stale"); err != ErrNonceChanged {
+		t.Fatal(err)
+	}
+	if status, err := u.Submit(shares[1], status.Nonce); err != nil || status.Progress != 2 {
+		t.Fatal(status, err)
+	}
+	status, err := u.Submit(shares[4], "")
+	if err != nil || status.Sealed || !bytes.Equal(unsealed, secret) {
+		t.Fatal(status, err)
+	}
+	if _, err := u.Submit(shares[2], ""); err != ErrUnsealed {
+		t.Fatal(err)
+	}
+
+	// shares of another secret fail the callback and reset the attempt
+	other := split(t, []byte("not the master key of the service"))
+	nonce := u.Seal().Nonce
+	u.Submit(other[0], "")
+	u.Submit(other[1], "")
+	status, err = u.Submit(other[2], "")
+	if err == nil || !status.Sealed || status.Progress != 0 || status.Nonce == nonce {
+		t.Fatal(status, err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	secret := make([]byte, 32)
+	rand.Read(secret)
+	shares := split(t, secret)
+	unsealed := false
+	u := NewUnsealer(3, func(s []byte) error {
+		unsealed = bytes.Equal(s, secret)
+		return nil
+	})
+	server := httptest.NewServer(u.Handler())
+	defer server.Close()
+	put := func(body string) (Status, int) {
+		req, _ := http.NewRequest(http.MethodPut, server.URL+"/sys/unseal", strings.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var status Status
+		json.NewDecoder(resp.Body).Decode(&status)
+		return status, resp.StatusCode
+	}
+	key := func(s tss.Share) string {
+		b, _ := json.Marshal(unsealRequest{Key: tss.EncodeShare(s)})
+		return string(b)
+	}
+	if status, code := put(key(shares[3])); code != http.StatusOK || status.Progress != 1 {
+		t.Fatal(status, code)
+	}
+	if status, code := put(`{"reset": true}`); code != http.StatusOK || status.Progress != 0 {
+		t.Fatal(status, code)
+	}
+	if _, code := put(`{"key": "not a share"}`); code != http.StatusBadRequest {
+		t.Fatal(code)
+	}
+	for _, s := range shares[:3] {
+		put(key(s))
+	}
+	resp, err := http.Get(server.URL + "/sys/seal-status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var status Status
+	json.NewDecoder(resp.Body).Decode(&status)
+	if status.Sealed || !unsealed {
+		t.Fatalf("status %+v", status)
+	}
+	if _, code := put(key(shares[4])); code != http.StatusBadRequest {
+		t.Fatal(code)
+	}
+}
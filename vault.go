@@ -0,0 +1,43 @@
+package tss
+
+// HashiCorp Vault's shamir package splits over the same field, GF(256) with
+// the AES polynomial, with the secret as constant term and random distinct
+// non zero x coordinates, so its shares are ours with the index moved from
+// the first to the last byte.
+
+// MarshalVaultShare writes share in the layout of Vault's shamir package:
+// the share bytes followed by the index
+func MarshalVaultShare(share Share) ([]byte, error) {
+	parts, err := MarshalShares(ShareSet{share}, LayoutIndexLast)
+	if err != nil {
+		return nil, ErrInvalidShare
+	}
+	return parts[0], nil
+}
+
+// UnmarshalVaultShare reads a share written by Vault's shamir package, such
+// as an unseal key
+func UnmarshalVaultShare(part []byte) (Share, error) {
+	shares, err := UnmarshalShares([][]byte{part}, LayoutIndexLast, nil)
+	if err != nil {
+		return nil, ErrInvalidShare
+	}
+	return shares[0], nil
+}
+
+// CreateVaultShares is CreateShares writing the shares in the layout of
+// Vault's shamir package, with random indexes as Vault's.
+func CreateVaultShares(secret []byte, sharesCount int, threshold int, opts ...Option) ([][]byte, error) {
+	shares, err := CreateShares(secret, sharesCount, threshold, append([]Option{WithRandomIndexes()}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer eraseShares(shares)
+	return MarshalShares(shares, LayoutIndexLast)
+}
+
+// RecoverVaultSecret is RecoverSecret for shares in the layout of Vault's
+// shamir package, a malformed part being reported as a *ShareError
+func RecoverVaultSecret(parts [][]byte, opts ...Option) ([]byte, error) {
+	return RecoverSecretWithLayout(parts, LayoutIndexLast, nil, opts...)
+}
@@ -0,0 +1,41 @@
+package tss
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestVaultShares(t *testing.T) {
+	secret := randomBytes(32)
+	parts, err := CreateVaultShares(secret, 5, 3, WithConsumeSecret(false))
+	if err != nil {
+		failNow(t, err)
+	}
+	recovered, err := RecoverVaultSecret([][]byte{parts[4], parts[0], parts[2]})
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, ErrIntegrity)
+	}
+}
+
+// TestVaultLayout recovers parts laid out as Vault writes them, computed here
+// for the line secret ^ 0x53·x
+func TestVaultLayout(t *testing.T) {
+	secret := []byte{0x2a, 0x99}
+	parts := [][]byte{
+		{secret[0] ^ mul(0x53, 0x07), secret[1] ^ mul(0x53, 0x07), 0x07},
+		{secret[0] ^ mul(0x53, 0xc4), secret[1] ^ mul(0x53, 0xc4), 0xc4},
+	}
+	recovered, err := RecoverVaultSecret(parts)
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		failNow(t, ErrIntegrity)
+	}
+	if _, err := UnmarshalVaultShare([]byte{0x01, 0x00}); err != ErrInvalidShare {
+		failNow(t, expected(ErrInvalidShare, err))
+	}
+}
@@ -0,0 +1,221 @@
+// Package vectors exposes the known answer vectors of the tss package as
+// data, and generates fresh ones, so implementations in other languages can
+// test against go-tss. Vectors marshal to JSON with bytes in hex.
+package vectors
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	tss "github.com/antik10ud/go-tss"
+)
+
+// DefaultInfo is the HKDF info of the deterministic splits of Generate
+const DefaultInfo = "go-tss vectors"
+
+var (
+	ErrMismatch = fmt.Errorf("vector mismatch: %w", tss.ErrIntegrity)
+)
+
+// Hex is a byte string written in hex in JSON
+type Hex []byte
+
+func (h Hex) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(h)), nil
+}
+
+func (h *Hex) UnmarshalText(text []byte) error {
+	b, err := hex.DecodeString(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid hex: %w", tss.ErrValidation)
+	}
+	*h = b
+	return nil
+}
+
+// Vector is a secret and shares of it. Any Threshold of the shares recover
+// the secret. When Deterministic is set the shares are those of
+// tss.CreateShares with tss.WithDeterministic(Salt, Info), and Containers,
+// when present, hold the shares in tss.MarshalContainer format with
+// Identifier and Threshold as params.
+type Vector struct {
+	Name          string `json:"name"`
+	Secret        Hex    `json:"secret"`
+	Threshold     int    `json:"threshold"`
+	Shares        []Hex  `json:"shares"`
+	Deterministic bool   `json:"deterministic,omitempty"`
+	Salt          Hex    `json:"salt,omitempty"`
+	Info          string `json:"info,omitempty"`
+	Identifier    Hex    `json:"identifier,omitempty"`
+	Containers    []Hex  `json:"containers,omitempty"`
+}
+
+// Known returns the known answer vectors of the tss package
+func Known() []Vector {
+	return []Vector{
+		{
+			Name:      "recover",
+			Secret:    mustHex("a217525ab5cab096e455acba00a4032c0cc1a1ef7ccd280642d994cdee7694ca"),
+			Threshold: 2,
+			Shares: []Hex{
+				mustHex("016fbb11a9264bdf4188e3911827ea30e27a283cbea177d7c421524fb448bbfedc"),
+				mustHex("022354d4a788d36e233c22d6e54e3865abe008804ddda2cd9984d4393fb9f740e6"),
+			},
+		},
+		{
+			Name:      "deterministic",
+			Secret:    mustHex("a217525ab5cab096e455acba00a4032c0cc1a1ef7ccd280642d994cdee7694ca"),
+			Threshold: 2,
+			Shares: []Hex{
+				mustHex("017c84ed8d2fd81e7b2c4767bae5e37ed52006441773b042df9e5fde4bc8fd13b1"),
+				mustHex("02052a37ef9aeef7576f7121bad12af9c5545470046237fcafe1ce00daa27b813c"),
+				mustHex("03dbb9883800fc59baa763eaba346d843c789395fc6d4a96763d484a5c84f00647"),
+			},
+			Deterministic: true,
+			Salt:          Hex("go-tss self-test"),
+			Info:          "kat",
+		},
+	}
+}
+
+func mustHex(s string) Hex {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Generate makes a vector of a random secretBytes secret split into
+// sharesCount deterministic shares with the given threshold, along their
+// containers. Secret, salt and identifier are read from random.
+func Generate(random io.Reader, name string, secretBytes int, sharesCount int, threshold int) (Vector, error) {
+	v := Vector{
+		Name:          name,
+		Secret:        make(Hex, secretBytes),
+		Threshold:     threshold,
+		Deterministic: true,
+		Salt:          make(Hex, 16),
+		Info:          DefaultInfo,
+		Identifier:    make(Hex, 8),
+	}
+	for _, b := range [][]byte{v.Secret, v.Salt, v.Identifier} {
+		if _, err := io.ReadFull(random, b); err != nil {
+			return Vector{}, err
+		}
+	}
+	shares, err := v.split(sharesCount)
+	if err != nil {
+		return Vector{}, err
+	}
+	params := tss.ShareParams{Identifier: v.Identifier, Threshold: threshold}
+	for _, s := range shares {
+		container, err := tss.MarshalContainer(s, params)
+		if err != nil {
+			return Vector{}, err
+		}
+		v.Shares = append(v.Shares, Hex(s))
+		v.Containers = append(v.Containers, container)
+	}
+	return v, nil
+}
+
+// split makes the deterministic shares of the vector
+func (v Vector) split(sharesCount int) (tss.ShareSet, error) {
+	return tss.CreateShares(v.Secret, sharesCount, v.Threshold, tss.WithDeterministic(v.Salt, v.Info), tss.WithConsumeSecret(false))
+}
+
+// Check runs the vector against the tss package: the first and the last
+// Threshold shares must recover the secret, deterministic shares must be
+// reproduced and containers must decode to the shares and params. It returns
+// ErrMismatch, wrapped with the failing step, when they do not.
+func (v Vector) Check() error {
+	if v.Threshold < 1 || v.Threshold > len(v.Shares) {
+		return fmt.Errorf("%s: threshold %d for %d shares: %w", v.Name, v.Threshold, len(v.Shares), tss.ErrValidation)
+	}
+	shares := make(tss.ShareSet, len(v.Shares))
+	for i, s := range v.Shares {
+		shares[i] = tss.Share(s)
+	}
+	for _, subset := range []tss.ShareSet{shares[:v.Threshold], shares[len(shares)-v.Threshold:]} {
+		secret, err := tss.RecoverSecret(subset, tss.AllowTrivialThreshold())
+		if err != nil {
+			return fmt.Errorf("%s: recover: %w", v.Name, err)
+		}
+		if !bytes.Equal(secret, v.Secret) {
+			return fmt.Errorf("%s: recovered secret: %w", v.Name, ErrMismatch)
+		}
+	}
+	if v.Deterministic {
+		split, err := v.split(len(shares))
+		if err != nil {
+			return fmt.Errorf("%s: split: %w", v.Name, err)
+		}
+		for i := range split {
+			if !bytes.Equal(split[i], shares[i]) {
+				return fmt.Errorf("%s: share %d: %w", v.Name, i+1, ErrMismatch)
+			}
+		}
+	}
+	if v.Containers != nil && len(v.Containers) != len(shares) {
+		return fmt.Errorf("%s: %d containers for %d shares: %w", v.Name, len(v.Containers), len(shares), tss.ErrValidation)
+	}
+	for i, c := range v.Containers {
+		share, params, err := tss.UnmarshalContainer(c)
+		if err != nil {
+			return fmt.Errorf("%s: container %d: %w", v.Name, i+1, err)
+		}
+		if !bytes.Equal(share, shares[i]) || !bytes.Equal(params.Identifier, v.Identifier) || params.Threshold != v.Threshold {
+			return fmt.Errorf("%s: container %d: %w", v.Name, i+1, ErrMismatch)
+		}
+	}
+	return nil
+}
+
+// RTSSFixture is a secret and shares of it written with the header of
+// draft-mcgrew-tss by some implementation, each share in hex or in the
+// tss~v1~ text format of the Ruby tss gem
+type RTSSFixture struct {
+	Name   string   `json:"name"`
+	Source string   `json:"source,omitempty"`
+	Secret Hex      `json:"secret"`
+	Shares []string `json:"shares"`
+}
+
+// LoadRTSSFixtures reads a JSON array of fixtures
+func LoadRTSSFixtures(r io.Reader) ([]RTSSFixture, error) {
+	var fixtures []RTSSFixture
+	if err := json.NewDecoder(r).Decode(&fixtures); err != nil {
+		return nil, fmt.Errorf("invalid fixtures: %v: %w", err, tss.ErrValidation)
+	}
+	return fixtures, nil
+}
+
+// Check recovers the secret of the fixture with tss.RecoverRTSSSecret, it
+// returns ErrMismatch when it differs
+func (f RTSSFixture) Check() error {
+	data := make([][]byte, len(f.Shares))
+	for i, s := range f.Shares {
+		var err error
+		if strings.HasPrefix(s, "tss~") {
+			data[i], err = tss.ParseRTSSText(s)
+		} else {
+			data[i], err = hex.DecodeString(s)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: share %d: %v: %w", f.Name, i+1, err, tss.ErrValidation)
+		}
+	}
+	secret, err := tss.RecoverRTSSSecret(data)
+	if err != nil {
+		return fmt.Errorf("%s: recover: %w", f.Name, err)
+	}
+	if !bytes.Equal(secret, f.Secret) {
+		return fmt.Errorf("%s: recovered secret: %w", f.Name, ErrMismatch)
+	}
+	return nil
+}
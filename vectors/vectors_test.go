@@ -0,0 +1,68 @@
+package vectors
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	tss "github.com/antik10ud/go-tss"
+)
+
+func TestKnown(t *testing.T) {
+	for _, v := range Known() {
+		if err := v.Check(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	v, err := Generate(rand.Reader, "generated", 48, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Vector
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, v) {
+		t.Fatalf("json round trip changed the vector: %s", data)
+	}
+	if err := decoded.Check(); err != nil {
+		t.Fatal(err)
+	}
+	decoded.Shares[4][1] ^= 1
+	if err := decoded.Check(); !errors.Is(err, tss.ErrIntegrity) {
+		t.Fatalf("tampered vector: %v", err)
+	}
+}
+
+func TestRTSSFixtures(t *testing.T) {
+	secret := []byte("correct horse battery staple")
+	data, err := tss.CreateRTSSShares(secret, 3, 2, []byte("fixture"), tss.RTSSHashSHA256, tss.WithConsumeSecret(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := "tss~v1~fixture~2~" + base64.URLEncoding.EncodeToString(data[2])
+	doc := fmt.Sprintf(`[{"name": "go-tss", "secret": "%x", "shares": ["%x", "%s"]}]`, secret, data[0], text)
+	fixtures, err := LoadRTSSFixtures(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fixtures[0].Check(); err != nil {
+		t.Fatal(err)
+	}
+	fixtures[0].Secret[0] ^= 1
+	if err := fixtures[0].Check(); !errors.Is(err, ErrMismatch) {
+		t.Fatalf("wrong secret: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package tss
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const (
+	// bundleMagic starts the binary form of a Bundle
+	bundleMagic = "TSSBUNDL"
+	// bundleVersion is the version of the binary form of a Bundle
+	bundleVersion = 1
+)
+
+// Bundle is what a weighted custodian holds: as many shares of the split as
+// its weight, used together on recovery
+type Bundle struct {
+	Shares ShareSet
+}
+
+// Weight returns the number of shares in the bundle
+func (b Bundle) Weight() int {
+	return len(b.Shares)
+}
+
+// CreateWeightedShares splits secret among custodians of the given weights, a
+// custodian of weight w counting as w custodians: any set of custodians whose
+// weights add up to threshold recovers the secret. The weights add up to at
+// most MaxShares, a weight of 0 is not allowed.
+func CreateWeightedShares(secret []byte, weights []int, threshold int, opts ...Option) ([]Bundle, error) {
+	total := 0
+	for _, w := range weights {
+		if w < 1 || w > MaxShares {
+			return nil, ErrInvalidParams
+		}
+		total += w
+	}
+	if total > MaxShares {
+		return nil, ErrTooManyShares
+	}
+	shares, err := CreateShares(secret, total, threshold, opts...)
+	if err != nil {
+		return nil, err
+	}
+	bundles := make([]Bundle, len(weights))
+	for i, w := range weights {
+		bundles[i].Shares, shares = shares[:w:w], shares[w:]
+	}
+	return bundles, nil
+}
+
+// RecoverWeightedSecret recovers a secret split by CreateWeightedShares from
+// bundles whose weights add up to the threshold at least
+func RecoverWeightedSecret(bundles []Bundle, opts ...Option) ([]byte, error) {
+	var shares ShareSet
+	for _, b := range bundles {
+		shares = append(shares, b.Shares...)
+	}
+	return RecoverSecret(shares, opts...)
+}
+
+// MarshalBinary encodes the bundle as the share size and count followed by
+// the shares
+func (b Bundle) MarshalBinary() ([]byte, error) {
+	if len(b.Shares) == 0 || len(b.Shares) > MaxShares {
+		return nil, ErrInvalidShare
+	}
+	size := len(b.Shares[0])
+	for _, s := range b.Shares {
+		if !s.Valid() || len(s) != size {
+			return nil, ErrInvalidShare
+		}
+	}
+	var buf bytes.Buffer
+	buf.WriteString(bundleMagic)
+	buf.WriteByte(bundleVersion)
+	buf.WriteByte(byte(len(b.Shares)))
+	binary.Write(&buf, binary.BigEndian, uint16(size))
+	for _, s := range b.Shares {
+		buf.Write(s)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a bundle written by MarshalBinary
+func (b *Bundle) UnmarshalBinary(data []byte) error {
+	if !bytes.HasPrefix(data, []byte(bundleMagic)) {
+		return ErrInvalidShare
+	}
+	p := data[len(bundleMagic):]
+	if len(p) < 4 {
+		return ErrInvalidShare
+	}
+	if p[0] != bundleVersion {
+		return ErrUnsupportedVersion
+	}
+	count := int(p[1])
+	size := int(binary.BigEndian.Uint16(p[2:]))
+	p = p[4:]
+	if count == 0 || size < MinShareBytes || len(p) != count*size {
+		return ErrInvalidShare
+	}
+	shares := make(ShareSet, count)
+	for i := range shares {
+		shares[i] = append(Share{}, p[i*size:(i+1)*size]...)
+		if !shares[i].Valid() {
+			return ErrInvalidShare
+		}
+	}
+	b.Shares = shares
+	return nil
+}
@@ -0,0 +1,40 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestWeightedShares(t *testing.T) {
+	secret := randomBytes(32)
+	// the CEO counts double
+	bundles, err := CreateWeightedShares(secret, []int{2, 1, 1, 1}, 3)
+	if err != nil {
+		failNow(t, err)
+	}
+	data, _ := bundles[0].MarshalBinary()
+	var ceo Bundle
+	if err := ceo.UnmarshalBinary(data); err != nil {
+		failNow(t, err)
+	}
+	if ceo.Weight() != 2 {
+		failNow(t, fmt.Errorf("weight %d", ceo.Weight()))
+	}
+	for _, quorum := range [][]Bundle{{ceo, bundles[3]}, {bundles[1], bundles[2], bundles[3]}} {
+		got, err := RecoverWeightedSecret(quorum)
+		if err != nil {
+			failNow(t, err)
+		}
+		if !bytes.Equal(got, secret) {
+			failNow(t, fmt.Errorf("secret mismatch"))
+		}
+	}
+	got, _ := RecoverWeightedSecret([]Bundle{bundles[1], bundles[2]})
+	if bytes.Equal(got, secret) {
+		failNow(t, fmt.Errorf("recovered below the threshold"))
+	}
+	if _, err := CreateWeightedShares(secret, []int{1, 0}, 2); err != ErrInvalidParams {
+		failNow(t, expected(ErrInvalidParams, err))
+	}
+}
@@ -0,0 +1,181 @@
+package tss
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"io"
+	"sync"
+)
+
+const (
+	// MaxWideShares is the largest number of wide shares, their indexes
+	// being 16 bits
+	MaxWideShares = 65535
+	// wideField is the polynomial of GF(2^16), x^16+x^12+x^3+x+1, 2 being a
+	// generator
+	wideField = 0x1100b
+	// wideOrder is the number of non zero elements of GF(2^16)
+	wideOrder = 65535
+)
+
+// WideShare is a share over GF(2^16), for more custodians than MaxShares: a
+// 16 bit big endian index, then 2 bytes per 2 bytes of the padded secret.
+// The secret is padded, 0x80 then zeros, to an even size.
+type WideShare []byte
+
+// WideShareSet is a set of wide shares
+type WideShareSet []WideShare
+
+// Index returns the x coordinate of the share, 0 for a short share
+func (s WideShare) Index() uint16 {
+	if len(s) < 2 {
+		return 0
+	}
+	return uint16(s[0])<<8 | uint16(s[1])
+}
+
+var (
+	wideOnce sync.Once
+	wideExp  [2 * wideOrder]uint16
+	wideLog  [wideOrder + 1]uint16
+)
+
+// wideTables builds the exp and log tables of GF(2^16) on first use
+func wideTables() {
+	wideOnce.Do(func() {
+		x := 1
+		for i := 0; i < wideOrder; i++ {
+			wideExp[i] = uint16(x)
+			wideExp[i+wideOrder] = uint16(x)
+			wideLog[x] = uint16(i)
+			x <<= 1
+			if x&0x10000 != 0 {
+				x ^= wideField
+			}
+		}
+	})
+}
+
+func mul16(x uint16, y uint16) uint16 {
+	if x == 0 || y == 0 {
+		return 0
+	}
+	return wideExp[int(wideLog[x])+int(wideLog[y])]
+}
+
+func div16(x uint16, y uint16) uint16 {
+	if x == 0 || y == 0 {
+		return 0
+	}
+	return wideExp[int(wideLog[x])+wideOrder-int(wideLog[y])]
+}
+
+// GF65536 is GF(2^16) with the polynomial x^16+x^12+x^3+x+1, the field of
+// WideShare
+var GF65536 Field = gf65536{}
+
+type gf65536 struct{}
+
+func (gf65536) ElementBytes() int {
+	return 2
+}
+
+func (gf65536) Add(dst []byte, a []byte, b []byte) {
+	subtle.XORBytes(dst[:len(a)], a, b)
+}
+
+func (gf65536) Mul(dst []byte, a []byte, b []byte) {
+	wideTables()
+	for i := 0; i+1 < len(a); i += 2 {
+		binary.BigEndian.PutUint16(dst[i:], mul16(binary.BigEndian.Uint16(a[i:]), binary.BigEndian.Uint16(b[i:])))
+	}
+}
+
+func (gf65536) Div(dst []byte, a []byte, b []byte) error {
+	for i := 0; i+1 < len(b); i += 2 {
+		if b[i]|b[i+1] == 0 {
+			return ErrDivisionByZero
+		}
+	}
+	wideTables()
+	for i := 0; i+1 < len(a); i += 2 {
+		binary.BigEndian.PutUint16(dst[i:], div16(binary.BigEndian.Uint16(a[i:]), binary.BigEndian.Uint16(b[i:])))
+	}
+	return nil
+}
+
+func (gf65536) Eval(dst []byte, x []byte, coefficients [][]byte) {
+	wideTables()
+	u := binary.BigEndian.Uint16(x)
+	for i := 0; i+1 < len(dst); i += 2 {
+		var y uint16
+		for k := len(coefficients) - 1; k >= 0; k-- {
+			y = mul16(y, u) ^ binary.BigEndian.Uint16(coefficients[k][i:])
+		}
+		binary.BigEndian.PutUint16(dst[i:], y)
+	}
+}
+
+func (gf65536) Interp(dst []byte, x []byte, xs []byte, ys [][]byte) error {
+	wideTables()
+	n := len(ys)
+	u := make([]uint16, n)
+	seen := make([]bool, wideOrder+1)
+	for i := range u {
+		u[i] = binary.BigEndian.Uint16(xs[2*i:])
+		if seen[u[i]] {
+			return ErrDivisionByZero
+		}
+		seen[u[i]] = true
+	}
+	// Lagrange weights at x, the product of (x - x_j) / (x_i - x_j)
+	at := binary.BigEndian.Uint16(x)
+	weights := make([]uint16, n)
+	for i := range weights {
+		w := uint16(1)
+		for j := range u {
+			if j != i {
+				w = mul16(w, div16(at^u[j], u[i]^u[j]))
+			}
+		}
+		weights[i] = w
+	}
+	for b := 0; b+1 < len(dst); b += 2 {
+		var y uint16
+		for i, v := range ys {
+			y ^= mul16(weights[i], binary.BigEndian.Uint16(v[b:]))
+		}
+		binary.BigEndian.PutUint16(dst[b:], y)
+	}
+	return nil
+}
+
+func (gf65536) Random(dst []byte, random io.Reader) error {
+	_, err := io.ReadFull(random, dst)
+	return err
+}
+
+// CreateWideShares is CreateShares over GF(2^16), allowing up to
+// MaxWideShares shares. WithRandomIndexes picks random distinct 16 bit
+// indexes.
+func CreateWideShares(secret []byte, sharesCount int, threshold int, opts ...Option) (WideShareSet, error) {
+	shares, err := CreateFieldShares(GF65536, secret, sharesCount, threshold, opts...)
+	if err != nil {
+		return nil, err
+	}
+	wide := make(WideShareSet, len(shares))
+	for i, s := range shares {
+		wide[i] = s
+	}
+	return wide, nil
+}
+
+// RecoverWideSecret reconstructs a secret from wide shares, the same checks
+// as RecoverSecret applying
+func RecoverWideSecret(shares WideShareSet, opts ...Option) ([]byte, error) {
+	set := make([][]byte, len(shares))
+	for i, s := range shares {
+		set[i] = s
+	}
+	return RecoverFieldSecret(GF65536, set, opts...)
+}
@@ -0,0 +1,57 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWideShares(t *testing.T) {
+	for _, size := range []int{1, 2, 33} {
+		secret := randomBytes(size)
+		shares, err := CreateWideShares(secret, 300, 257)
+		if err != nil {
+			failNow(t, err)
+		}
+		if len(shares) != 300 || shares[299].Index() != 300 || len(shares[0]) != 2+2*(size/2+1) {
+			failNow(t, fmt.Errorf("unexpected shares"))
+		}
+		recovered, err := RecoverWideSecret(shares[43:])
+		if err != nil || !bytes.Equal(recovered, secret) {
+			failNow(t, fmt.Errorf("size %d: secret mismatch %v", size, err))
+		}
+		recovered, _ = RecoverWideSecret(shares[:256])
+		if bytes.Equal(recovered, secret) {
+			failNow(t, fmt.Errorf("size %d: recovered below threshold", size))
+		}
+	}
+}
+
+func TestWideSharesRandomIndexes(t *testing.T) {
+	secret := randomBytes(32)
+	shares, err := CreateWideShares(secret, 5, 3, WithRandomIndexes())
+	if err != nil {
+		failNow(t, err)
+	}
+	recovered, err := RecoverWideSecret(WideShareSet{shares[4], shares[0], shares[2]})
+	if err != nil || !bytes.Equal(recovered, secret) {
+		failNow(t, fmt.Errorf("secret mismatch %v", err))
+	}
+}
+
+func TestWideSharesErrors(t *testing.T) {
+	shares, _ := CreateWideShares(randomBytes(32), 5, 3)
+	if _, err := CreateWideShares(randomBytes(32), MaxWideShares+1, 3); err != ErrTooManyShares {
+		failNow(t, expected(ErrTooManyShares, err))
+	}
+	if _, err := RecoverWideSecret(WideShareSet{shares[0], shares[1], shares[1]}); !errors.Is(err, ErrDuplicateShare) {
+		failNow(t, expected(ErrDuplicateShare, err))
+	}
+	if _, err := RecoverWideSecret(WideShareSet{shares[0], shares[1][:10]}); !errors.Is(err, ErrShareSize) {
+		failNow(t, expected(ErrShareSize, err))
+	}
+	if _, err := RecoverWideSecret(shares[:2], WithThreshold(3)); !errors.Is(err, ErrThresholdNotMet) {
+		failNow(t, expected(ErrThresholdNotMet, err))
+	}
+}
@@ -0,0 +1,72 @@
+package tss
+
+import (
+	"crypto/rand"
+	"runtime"
+	"sync/atomic"
+)
+
+// WipeStrategy is how buffers holding secret material are overwritten before
+// they are released
+type WipeStrategy int32
+
+const (
+	// WipeOnes fills with 0xff, the default
+	WipeOnes WipeStrategy = iota
+	// WipeZero fills with zeros
+	WipeZero
+	// WipeRandom fills with random bytes
+	WipeRandom
+	// WipeMultiPass fills with zeros, then 0xff, then random bytes
+	WipeMultiPass
+)
+
+var wipeStrategy atomic.Int32
+
+// SetWipeStrategy sets how the package wipes the buffers holding secret
+// material, polynomial coefficients and shares left over on errors included.
+// It applies to the whole process and is safe to call concurrently.
+func SetWipeStrategy(s WipeStrategy) {
+	if s < WipeOnes || s > WipeMultiPass {
+		return
+	}
+	wipeStrategy.Store(int32(s))
+}
+
+// Wipe overwrites b following the strategy set by SetWipeStrategy, for the
+// buffers the caller holds, such as recovered secrets
+func Wipe(b []byte) {
+	erase(b)
+}
+
+// erase overwrites a with the current wipe strategy. runtime.KeepAlive keeps
+// the writes from being elided as dead stores.
+func erase(a []byte) {
+	switch WipeStrategy(wipeStrategy.Load()) {
+	case WipeZero:
+		fill(a, 0)
+	case WipeRandom:
+		rand.Read(a)
+	case WipeMultiPass:
+		fill(a, 0)
+		fill(a, 0xff)
+		rand.Read(a)
+	default:
+		fill(a, 0xff)
+	}
+	runtime.KeepAlive(a)
+}
+
+func fill(a []byte, v byte) {
+	for i := range a {
+		a[i] = v
+	}
+	runtime.KeepAlive(a)
+}
+
+// eraseShares erases every share of ss
+func eraseShares(ss ShareSet) {
+	for _, s := range ss {
+		erase(s)
+	}
+}
@@ -0,0 +1,65 @@
+package tss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWipeStrategy(t *testing.T) {
+	defer SetWipeStrategy(WipeOnes)
+	for _, c := range []struct {
+		strategy WipeStrategy
+		want     byte
+	}{
+		{WipeOnes, 0xff},
+		{WipeZero, 0},
+	} {
+		SetWipeStrategy(c.strategy)
+		b := randomBytes(64)
+		Wipe(b)
+		if !bytes.Equal(b, bytes.Repeat([]byte{c.want}, 64)) {
+			failNow(t, fmt.Errorf("strategy %d left %x", c.strategy, b))
+		}
+	}
+	for _, strategy := range []WipeStrategy{WipeRandom, WipeMultiPass} {
+		SetWipeStrategy(strategy)
+		b := bytes.Repeat([]byte{0x42}, 64)
+		Wipe(b)
+		if bytes.Count(b, []byte{0x42}) > 8 {
+			failNow(t, fmt.Errorf("strategy %d left %x", strategy, b))
+		}
+	}
+}
+
+type failingReader struct {
+	n int
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.n <= 0 {
+		return 0, errors.New("entropy exhausted")
+	}
+	if len(p) > r.n {
+		p = p[:r.n]
+	}
+	r.n -= len(p)
+	return len(p), nil
+}
+
+func TestWipeSharesOnError(t *testing.T) {
+	secret := randomBytes(32)
+	dst := make(ShareSet, 0, 3)
+	for i := 0; i < 3; i++ {
+		dst = append(dst, make(Share, 33))
+	}
+	if _, err := AppendShares(dst[:0], secret, 3, 2, WithRand(&failingReader{n: 10})); err == nil {
+		failNow(t, fmt.Errorf("expected an error"))
+	}
+	for _, s := range dst[:3] {
+		if !bytes.Equal(s, bytes.Repeat([]byte{0xff}, 33)) {
+			failNow(t, fmt.Errorf("share left after error %x", s))
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package tss
+
+import (
+	"crypto/subtle"
+	"io"
+)
+
+// xorShares fills the values of shares with the XOR scheme: random values for
+// all but the last share, the secret xored with them for the last
+func xorShares(secret []byte, shares ShareSet, random io.Reader) error {
+	last := shares[len(shares)-1][1:]
+	copy(last, secret)
+	for _, s := range shares[:len(shares)-1] {
+		if _, err := io.ReadFull(random, s[1:]); err != nil {
+			return err
+		}
+		subtle.XORBytes(last, last, s[1:])
+	}
+	return nil
+}
+
+// xorRecover xors the values of shares into secret
+func xorRecover(secret []byte, shares ShareSet) {
+	for i := range secret {
+		secret[i] = 0
+	}
+	for _, s := range shares {
+		subtle.XORBytes(secret, secret, s[1:])
+	}
+}
@@ -0,0 +1,39 @@
+package tss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestXOR(t *testing.T) {
+	secret := randomBytes(1000)
+	shares, err := CreateShares(secret, 4, 4, WithXOR())
+	if err != nil {
+		failNow(t, err)
+	}
+	got, err := RecoverSecret(ShareSet{shares[2], shares[0], shares[3], shares[1]}, WithXOR())
+	if err != nil {
+		failNow(t, err)
+	}
+	if !bytes.Equal(got, secret) {
+		failNow(t, fmt.Errorf("secret mismatch"))
+	}
+	if _, err := CreateShares(secret, 4, 3, WithXOR()); err != ErrInvalidThreshold {
+		failNow(t, expected(ErrInvalidThreshold, err))
+	}
+}
+
+func BenchmarkCreateSharesXOR(b *testing.B) {
+	secret := randomBytes(MaxSecretBytes)
+	for i := 0; i < b.N; i++ {
+		CreateShares(secret, 10, 10, WithXOR())
+	}
+}
+
+func BenchmarkCreateSharesNofN(b *testing.B) {
+	secret := randomBytes(MaxSecretBytes)
+	for i := 0; i < b.N; i++ {
+		CreateShares(secret, 10, 10)
+	}
+}